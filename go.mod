@@ -17,6 +17,7 @@ require (
 	kubevirt.io/containerized-data-importer v1.23.1
 	kubevirt.io/vm-import-operator v0.0.0-00010101000000-000000000000
 	sigs.k8s.io/controller-runtime v0.6.4
+	sigs.k8s.io/yaml v1.2.0
 )
 
 replace bitbucket.org/ww/goautoneg v0.0.0-20120707110453-75cd24fc2f2c => github.com/markusthoemmes/goautoneg v0.0.0-20190713162725-c6008fefa5b1
@@ -26,3 +26,12 @@ type DestinationStorage struct {
 	// +kubebuilder:validation:Enum=ReadWriteOnce;ReadWriteMany;ReadOnlyMany
 	AccessMode core.PersistentVolumeAccessMode `json:"accessMode,omitempty"`
 }
+
+//
+// Shared disk destination.
+// Shared (multi-writer) disks are migrated into a single
+// ReadWriteMany block PVC attached to all consuming VMs.
+type SharedDisks struct {
+	// A storage class.
+	StorageClass string `json:"storageClass"`
+}
@@ -69,6 +69,21 @@ func (in *NetworkPair) DeepCopy() *NetworkPair {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedDisks) DeepCopyInto(out *SharedDisks) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedDisks.
+func (in *SharedDisks) DeepCopy() *SharedDisks {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedDisks)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StoragePair) DeepCopyInto(out *StoragePair) {
 	*out = *in
@@ -35,6 +35,12 @@ type PlanSpec struct {
 	Map plan.Map `json:"map,omitempty"`
 	// List of VMs.
 	VMs []plan.VM `json:"vms"`
+	// Target disk bus.
+	// Overrides the bus selected by guest OS.
+	// +kubebuilder:validation:Enum=virtio;sata;scsi
+	DiskBus string `json:"diskBus,omitempty"`
+	// Target VM template.
+	Template *plan.Template `json:"template,omitempty"`
 }
 
 //
@@ -9,6 +9,8 @@ type Map struct {
 	Networks []mapped.NetworkPair `json:"networks,omitempty"`
 	// Datastores.
 	Datastores []mapped.StoragePair `json:"datastores,omitempty"`
+	// Shared disks.
+	SharedDisks *mapped.SharedDisks `json:"sharedDisks,omitempty"`
 }
 
 //
@@ -0,0 +1,31 @@
+package plan
+
+//
+// Template kinds.
+const (
+	// OpenShift template containing a VirtualMachine.
+	TemplateKind = "Template"
+	// ConfigMap containing a VirtualMachine skeleton.
+	ConfigMapKind = "ConfigMap"
+)
+
+//
+// ConfigMap key containing the VirtualMachine skeleton (YAML).
+const TemplateKey = "vm"
+
+//
+// Target VM template.
+// References either an (OpenShift) Template containing
+// a VirtualMachine or a ConfigMap containing a VirtualMachine
+// skeleton. Fields are merged with the VM built for each
+// migrated VM and take precedence over the values mapped from
+// the source VM. Located on the destination cluster.
+type Template struct {
+	// Template kind.
+	// +kubebuilder:validation:Enum=Template;ConfigMap
+	Kind string `json:"kind"`
+	// Namespace.
+	Namespace string `json:"namespace"`
+	// Name.
+	Name string `json:"name"`
+}
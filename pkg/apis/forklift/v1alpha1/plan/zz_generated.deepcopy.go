@@ -83,6 +83,11 @@ func (in *Map) DeepCopyInto(out *Map) {
 		*out = make([]mapped.StoragePair, len(*in))
 		copy(*out, *in)
 	}
+	if in.SharedDisks != nil {
+		in, out := &in.SharedDisks, &out.SharedDisks
+		*out = new(mapped.SharedDisks)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Map.
@@ -178,6 +183,21 @@ func (in *Task) DeepCopy() *Task {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Template) DeepCopyInto(out *Template) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Template.
+func (in *Template) DeepCopy() *Template {
+	if in == nil {
+		return nil
+	}
+	out := new(Template)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Timed) DeepCopyInto(out *Timed) {
 	*out = *in
@@ -17,16 +17,25 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
 	libcnd "github.com/konveyor/controller/pkg/condition"
 	liberr "github.com/konveyor/controller/pkg/error"
+	"github.com/konveyor/controller/pkg/logging"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/auth"
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"strconv"
 )
 
+//
+// Logger.
+var log = logging.WithName("provider")
+
 //
 // Provider types.
 const (
@@ -39,7 +48,28 @@ const (
 //
 // Secret fields.
 const (
+	// Bearer token (openshift).
 	Token = "token"
+	// Kubeconfig (openshift).
+	Kubeconfig = "kubeconfig"
+	// Kubeconfig context (openshift).
+	// The current context is used by default.
+	Context = "context"
+	// PEM encoded client certificate (openshift).
+	ClientCert = core.TLSCertKey
+	// PEM encoded client key (openshift).
+	ClientKey = core.TLSPrivateKeyKey
+	// Service account (namespace/name) for which short-lived
+	// tokens are requested using the TokenRequest API (openshift).
+	ServiceAccount = "serviceAccount"
+	// PEM encoded CA certificate bundle.
+	CACert = "cacert"
+	// Certificate SHA-1 fingerprint (vsphere).
+	// Required: passed to the importer (VDDK).
+	Thumbprint = "thumbprint"
+	// Skip certificate verification.
+	// Must be explicitly set to: "true".
+	Insecure = "insecureSkipVerify"
 )
 
 //
@@ -62,6 +92,21 @@ type ProviderSpec struct {
 	// References a secret containing credentials and
 	// other confidential information.
 	Secret core.ObjectReference `json:"secret" ref:"Secret"`
+	// Restricts the collected inventory.
+	// vsphere only.
+	Scope *ProviderScope `json:"scope,omitempty"`
+}
+
+//
+// Inventory scope.
+// Specified as inventory paths to datacenters, clusters or folders.
+// Example: /dc1/host/cluster1 or /dc1/vm/team-a.
+type ProviderScope struct {
+	// Included paths.
+	// Everything is included when not specified.
+	Include []string `json:"include,omitempty"`
+	// Excluded paths.
+	Exclude []string `json:"exclude,omitempty"`
 }
 
 //
@@ -100,18 +145,122 @@ func init() {
 
 //
 // Build k8s REST configuration.
+// Errors are logged and reported by the provider validation.
+// Returns nil on error; rejected by the k8s client and manager.
+// See: BuildRestCfg().
 func (p *Provider) RestCfg(secret *core.Secret) (cfg *rest.Config) {
+	cfg, err := p.BuildRestCfg(secret)
+	if err != nil {
+		log.Trace(err)
+		cfg = nil
+	}
+
+	return
+}
+
+//
+// Build k8s REST configuration.
+// The credentials are (in order of precedence):
+//   kubeconfig: a kubeconfig with optional context.
+//   tls.crt, tls.key: a client certificate.
+//   token: a bearer token.
+// The provider URL takes precedence over the server in the kubeconfig.
+// When a service account is specified, the credentials are only used
+// to request short-lived tokens for the service account.
+func (p *Provider) BuildRestCfg(secret *core.Secret) (cfg *rest.Config, err error) {
 	if p.IsHost() {
-		cfg, _ = config.GetConfig()
+		cfg, err = config.GetConfig()
+		if err != nil {
+			err = liberr.Wrap(err)
+		}
 		return
 	}
-	cfg = &rest.Config{
-		Host:            p.Spec.URL,
-		BearerToken:     string(secret.Data[Token]),
-		TLSClientConfig: rest.TLSClientConfig{Insecure: true},
+	if kubeconfig, found := secret.Data[Kubeconfig]; found {
+		cfg, err = KubeconfigRestCfg(kubeconfig, string(secret.Data[Context]))
+		if err != nil {
+			return
+		}
+		cfg.Host = p.Spec.URL
+	} else {
+		cfg = &rest.Config{
+			Host:        p.Spec.URL,
+			BearerToken: string(secret.Data[Token]),
+		}
+		cfg.TLSClientConfig.CertData = secret.Data[ClientCert]
+		cfg.TLSClientConfig.KeyData = secret.Data[ClientKey]
+	}
+	if ca, found := secret.Data[CACert]; found {
+		cfg.TLSClientConfig.CAData = ca
+		cfg.TLSClientConfig.CAFile = ""
+		cfg.TLSClientConfig.Insecure = false
+	} else if InsecureSkipVerify(secret) {
+		cfg.TLSClientConfig.CAData = nil
+		cfg.TLSClientConfig.CAFile = ""
+		cfg.TLSClientConfig.Insecure = true
 	}
 	cfg.Burst = 1000
 	cfg.QPS = 100
+	if sa, found := secret.Data[ServiceAccount]; found {
+		cfg, err = auth.RestCfg(p, secret, cfg, string(sa))
+	}
+
+	return
+}
+
+//
+// Build k8s REST configuration using the kubeconfig.
+// Only inline credentials and certificates are supported. Exec and
+// auth-provider plugins, and references to files are rejected.
+func KubeconfigRestCfg(kubeconfig []byte, context string) (cfg *rest.Config, err error) {
+	apiCfg, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for name, auth := range apiCfg.AuthInfos {
+		if auth.Exec != nil ||
+			auth.AuthProvider != nil ||
+			auth.TokenFile != "" ||
+			auth.ClientCertificate != "" ||
+			auth.ClientKey != "" {
+			err = liberr.New(
+				fmt.Sprintf(
+					"kubeconfig user: %s, only inline credentials are supported.",
+					name))
+			return
+		}
+	}
+	for name, cluster := range apiCfg.Clusters {
+		if cluster.CertificateAuthority != "" {
+			err = liberr.New(
+				fmt.Sprintf(
+					"kubeconfig cluster: %s, only inline certificates are supported.",
+					name))
+			return
+		}
+	}
+	cfg, err = clientcmd.NewNonInteractiveClientConfig(
+		*apiCfg,
+		context,
+		&clientcmd.ConfigOverrides{},
+		nil).ClientConfig()
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
+
+//
+// The secret explicitly opts-in to skip
+// certificate verification.
+func InsecureSkipVerify(secret *core.Secret) (insecure bool) {
+	if secret == nil {
+		return
+	}
+	if b, found := secret.Data[Insecure]; found {
+		insecure, _ = strconv.ParseBool(string(b))
+	}
 
 	return
 }
@@ -119,8 +268,12 @@ func (p *Provider) RestCfg(secret *core.Secret) (cfg *rest.Config) {
 //
 // Build a k8s client.
 func (p *Provider) Client(secret *core.Secret) (c client.Client, err error) {
+	cfg, err := p.BuildRestCfg(secret)
+	if err != nil {
+		return
+	}
 	c, err = client.New(
-		p.RestCfg(secret),
+		cfg,
 		client.Options{
 			Scheme: scheme.Scheme,
 		})
@@ -0,0 +1,251 @@
+package v1alpha1
+
+import (
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+	"testing"
+)
+
+func TestBuildRestCfg(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	provider := &Provider{
+		Spec: ProviderSpec{
+			Type: OpenShift,
+			URL:  "https://cluster.example.com:6443",
+		},
+	}
+	ca := []byte("-----BEGIN CERTIFICATE-----")
+	kubeconfig := func(cluster string) []byte {
+		return []byte(`
+apiVersion: v1
+kind: Config
+clusters:
+- name: cluster
+  cluster:
+    server: https://kubeconfig.example.com:6443
+` + cluster + `
+users:
+- name: user
+  user:
+    token: kubeconfig-token
+contexts:
+- name: context
+  context:
+    cluster: cluster
+    user: user
+current-context: context
+`)
+	}
+	cases := []struct {
+		name     string
+		data     map[string][]byte
+		insecure bool
+		ca       []byte
+		token    string
+	}{
+		{
+			name:  "token",
+			data:  map[string][]byte{Token: []byte("token")},
+			token: "token",
+		},
+		{
+			name: "cacert",
+			data: map[string][]byte{
+				Token:  []byte("token"),
+				CACert: ca,
+			},
+			ca:    ca,
+			token: "token",
+		},
+		{
+			name: "insecure",
+			data: map[string][]byte{
+				Token:    []byte("token"),
+				Insecure: []byte("true"),
+			},
+			insecure: true,
+			token:    "token",
+		},
+		{
+			name: "insecure not opted-in",
+			data: map[string][]byte{
+				Token:    []byte("token"),
+				Insecure: []byte("false"),
+			},
+			token: "token",
+		},
+		{
+			name: "cacert and insecure",
+			data: map[string][]byte{
+				Token:    []byte("token"),
+				CACert:   ca,
+				Insecure: []byte("true"),
+			},
+			ca:    ca,
+			token: "token",
+		},
+		{
+			name: "kubeconfig insecure and cacert",
+			data: map[string][]byte{
+				Kubeconfig: kubeconfig("    insecure-skip-tls-verify: true"),
+				CACert:     ca,
+			},
+			ca:    ca,
+			token: "kubeconfig-token",
+		},
+		{
+			name: "kubeconfig CA and insecure",
+			data: map[string][]byte{
+				Kubeconfig: kubeconfig("    certificate-authority-data: " +
+					"LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0t"),
+				Insecure: []byte("true"),
+			},
+			insecure: true,
+			token:    "kubeconfig-token",
+		},
+	}
+	for _, c := range cases {
+		cfg, err := provider.BuildRestCfg(&core.Secret{Data: c.data})
+		g.Expect(err).NotTo(gomega.HaveOccurred(), c.name)
+		g.Expect(cfg.Host).To(gomega.Equal(provider.Spec.URL), c.name)
+		g.Expect(cfg.BearerToken).To(gomega.Equal(c.token), c.name)
+		g.Expect(cfg.Insecure).To(gomega.Equal(c.insecure), c.name)
+		g.Expect(cfg.CAData).To(gomega.Equal(c.ca), c.name)
+		g.Expect(cfg.CAFile).To(gomega.BeEmpty(), c.name)
+		if c.insecure {
+			_, err = rest.TransportFor(cfg)
+			g.Expect(err).NotTo(gomega.HaveOccurred(), c.name)
+		}
+	}
+}
+
+func TestKubeconfigRestCfg(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	kubeconfig := func(cluster, user string) []byte {
+		return []byte(`
+apiVersion: v1
+kind: Config
+clusters:
+- name: cluster
+  cluster:
+    server: https://kubeconfig.example.com:6443
+` + cluster + `
+users:
+- name: user
+  user:
+` + user + `
+contexts:
+- name: context
+  context:
+    cluster: cluster
+    user: user
+- name: other
+  context:
+    cluster: cluster
+    user: user
+    namespace: other
+current-context: context
+`)
+	}
+	token := "    token: kubeconfig-token"
+	cases := []struct {
+		name    string
+		cluster string
+		user    string
+		context string
+		valid   bool
+	}{
+		{
+			name:  "inline",
+			user:  token,
+			valid: true,
+		},
+		{
+			name:    "context",
+			user:    token,
+			context: "other",
+			valid:   true,
+		},
+		{
+			name:    "context not found",
+			user:    token,
+			context: "missing",
+		},
+		{
+			name: "exec",
+			user: `    exec:
+      apiVersion: client.authentication.k8s.io/v1beta1
+      command: /bin/sh`,
+		},
+		{
+			name: "auth-provider",
+			user: `    auth-provider:
+      name: oidc`,
+		},
+		{
+			name: "token file",
+			user: "    tokenFile: /etc/passwd",
+		},
+		{
+			name: "client certificate file",
+			user: "    client-certificate: /etc/passwd",
+		},
+		{
+			name: "client key file",
+			user: "    client-key: /etc/passwd",
+		},
+		{
+			name:    "certificate authority file",
+			cluster: "    certificate-authority: /etc/passwd",
+			user:    token,
+		},
+	}
+	for _, c := range cases {
+		cfg, err := KubeconfigRestCfg(kubeconfig(c.cluster, c.user), c.context)
+		if !c.valid {
+			g.Expect(err).To(gomega.HaveOccurred(), c.name)
+			continue
+		}
+		g.Expect(err).NotTo(gomega.HaveOccurred(), c.name)
+		g.Expect(cfg.Host).To(gomega.Equal("https://kubeconfig.example.com:6443"), c.name)
+		g.Expect(cfg.BearerToken).To(gomega.Equal("kubeconfig-token"), c.name)
+	}
+	_, err := KubeconfigRestCfg([]byte("not: [valid"), "")
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+func TestRestCfg(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	provider := &Provider{
+		Spec: ProviderSpec{
+			Type: OpenShift,
+			URL:  "https://cluster.example.com:6443",
+		},
+	}
+	cfg := provider.RestCfg(
+		&core.Secret{
+			Data: map[string][]byte{
+				Token: []byte("token"),
+			},
+		})
+	g.Expect(cfg).NotTo(gomega.BeNil())
+	g.Expect(cfg.BearerToken).To(gomega.Equal("token"))
+
+	// Not valid.
+	cfg = provider.RestCfg(
+		&core.Secret{
+			Data: map[string][]byte{
+				Kubeconfig: []byte("not: [valid"),
+			},
+		})
+	g.Expect(cfg).To(gomega.BeNil())
+	cfg = provider.RestCfg(
+		&core.Secret{
+			Data: map[string][]byte{
+				Token:          []byte("token"),
+				ServiceAccount: []byte("sa"),
+			},
+		})
+	g.Expect(cfg).To(gomega.BeNil())
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/controller/plan/builder/vsphere"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	cdi "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	vmio "kubevirt.io/vm-import-operator/pkg/apis/v2v/v1beta1"
 )
 
@@ -20,8 +22,14 @@ type Builder interface {
 	Secret(vmRef ref.Ref, in, object *core.Secret) error
 	// Build VMIO import spec.
 	Import(vmRef ref.Ref, mp *plan.Map, object *vmio.VirtualMachineImportSpec) error
+	// Build the access modes keyed by VMIO storage mapping (source) ID.
+	AccessModes(vmRef ref.Ref, mp *plan.Map) (map[string]core.PersistentVolumeAccessMode, error)
+	// Build the shared disk and (VMIO) placeholder DataVolumes.
+	SharedDisks(vmRef ref.Ref, mp *plan.Map, spec *vmio.VirtualMachineImportSpec) (shared, placeholders []cdi.DataVolume, err error)
 	// Build tasks.
 	Tasks(vmRef ref.Ref) ([]*plan.Task, error)
+	// Build (update) the KubeVirt VM created by the import.
+	VirtualMachine(vmRef ref.Ref, mp *plan.Map, object *unstructured.Unstructured, dataVolumes []cdi.DataVolume) error
 }
 
 //
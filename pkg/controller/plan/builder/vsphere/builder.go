@@ -12,16 +12,46 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1/plan"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1/ref"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ocp"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/types"
 	"gopkg.in/yaml.v2"
+	"hash/fnv"
 	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+	cdi "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	vmio "kubevirt.io/vm-import-operator/pkg/apis/v2v/v1beta1"
 	liburl "net/url"
+	"regexp"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//
+// Firmware types.
+const (
+	BIOS = "bios"
+	EFI  = "efi"
+)
+
+//
+// Characters not legal in a DNS (1123) name.
+var illegalDNS = regexp.MustCompile("[^a-z0-9-]+")
+
+//
+// Disk bus types.
+const (
+	Virtio = "virtio"
+	Sata   = "sata"
+	Scsi   = "scsi"
 )
 
 //
@@ -36,8 +66,41 @@ type Builder struct {
 
 //
 // Build the VMIO secret.
+// The CDI (VDDK) keys are included for the shared disk
+// DataVolumes. See: SharedDisks().
 func (r *Builder) Secret(vmRef ref.Ref, in, object *core.Secret) (err error) {
-	url := r.Source.Provider.Spec.URL
+	url, in, err := r.credentials(vmRef, in)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	content, mErr := yaml.Marshal(
+		map[string]string{
+			"apiUrl":     url,
+			"username":   string(in.Data["user"]),
+			"password":   string(in.Data["password"]),
+			"thumbprint": string(in.Data["thumbprint"]),
+		})
+	if mErr != nil {
+		err = liberr.Wrap(mErr)
+		return
+	}
+	object.StringData = map[string]string{
+		"vmware":      string(content),
+		"accessKeyId": string(in.Data["user"]),
+		"secretKey":   string(in.Data["password"]),
+	}
+
+	return
+}
+
+//
+// Source URL and credentials for the VM.
+// The ESX host URL and credentials are used when
+// defined by a Host CR.
+func (r *Builder) credentials(vmRef ref.Ref, in *core.Secret) (url string, secret *core.Secret, err error) {
+	url = r.Source.Provider.Spec.URL
+	secret = in
 	hostID, err := r.hostID(vmRef)
 	if err != nil {
 		err = liberr.Wrap(err)
@@ -61,21 +124,7 @@ func (r *Builder) Secret(vmRef ref.Ref, in, object *core.Secret) (err error) {
 			return
 		}
 		hostSecret.Data["thumbprint"] = []byte(h.Thumbprint)
-		in = hostSecret
-	}
-	content, mErr := yaml.Marshal(
-		map[string]string{
-			"apiUrl":     url,
-			"username":   string(in.Data["user"]),
-			"password":   string(in.Data["password"]),
-			"thumbprint": string(in.Data["thumbprint"]),
-		})
-	if mErr != nil {
-		err = liberr.Wrap(mErr)
-		return
-	}
-	object.StringData = map[string]string{
-		"vmware": string(content),
+		secret = hostSecret
 	}
 
 	return
@@ -96,7 +145,9 @@ func (r *Builder) Import(vmRef ref.Ref, mp *plan.Map, object *vmio.VirtualMachin
 	}
 	uuid := vm.UUID
 	object.TargetVMName = &vm.Name
-	start := vm.PowerState == string(types.VirtualMachinePowerStatePoweredOn)
+	// Started (as needed) after the firmware
+	// has been translated. See: VirtualMachine().
+	start := false
 	object.StartVM = &start
 	object.Source.Vmware = &vmio.VirtualMachineImportVmwareSourceSpec{
 		VM: vmio.VirtualMachineImportVmwareSourceVMSpec{
@@ -112,6 +163,137 @@ func (r *Builder) Import(vmRef ref.Ref, mp *plan.Map, object *vmio.VirtualMachin
 	return
 }
 
+//
+// Access modes keyed by VMIO storage mapping (source) ID.
+// The datastore is identified the same way as in the
+// storage mapping built by Import().
+func (r *Builder) AccessModes(vmRef ref.Ref, mp *plan.Map) (modes map[string]core.PersistentVolumeAccessMode, err error) {
+	vm := &model.VM{}
+	pErr := r.Source.Inventory.Find(vm, vmRef)
+	if pErr != nil {
+		err = liberr.New(
+			fmt.Sprintf(
+				"VM %s lookup failed: %s",
+				vmRef.String(),
+				pErr.Error()))
+		return
+	}
+	storage, err := r.storageMapping(mp, vm)
+	if err != nil {
+		return
+	}
+	modes = map[string]core.PersistentVolumeAccessMode{}
+	for _, mapped := range storage {
+		if mapped.Destination.AccessMode != "" {
+			modes[mapped.ID] = mapped.Destination.AccessMode
+		}
+	}
+
+	return
+}
+
+//
+// Build the DataVolumes for shared disks.
+// Each (non-RDM) shared disk is imported once per migration into
+// a ReadWriteMany block volume and referenced by each VM. The
+// VMIO (v0.2.5) imports every disk of the VM but does not import
+// a disk when the DataVolume (by name) exists; the existing DataVolume
+// is used once Succeeded. See: importDisks() in the VMIO
+// pkg/controller/virtualmachineimport/virtualmachineimport_controller.go.
+// A (blank) placeholder is built for each shared disk using the name
+// expected by the VMIO. The placeholder volumes (and disks) added to
+// the VM by the VMIO are removed. See: attachSharedDisks().
+func (r *Builder) SharedDisks(vmRef ref.Ref, mp *plan.Map, spec *vmio.VirtualMachineImportSpec) (shared, placeholders []cdi.DataVolume, err error) {
+	if mp.SharedDisks == nil {
+		return
+	}
+	vm := &model.VM{}
+	pErr := r.Source.Inventory.Find(vm, vmRef)
+	if pErr != nil {
+		err = liberr.New(
+			fmt.Sprintf(
+				"VM %s lookup failed: %s",
+				vmRef.String(),
+				pErr.Error()))
+		return
+	}
+	url, secret, err := r.credentials(vmRef, r.Source.Secret)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	vmName := vm.Name
+	if spec.TargetVMName != nil {
+		vmName = *spec.TargetVMName
+	}
+	vmName = labelValue(vmName)
+	block := core.PersistentVolumeBlock
+	for i := range vm.Disks {
+		disk := &vm.Disks[i]
+		if !disk.Shared || disk.RDM {
+			continue
+		}
+		shared = append(
+			shared,
+			cdi.DataVolume{
+				ObjectMeta: meta.ObjectMeta{
+					Name: r.sharedDiskName(disk),
+				},
+				Spec: cdi.DataVolumeSpec{
+					Source: cdi.DataVolumeSource{
+						VDDK: &cdi.DataVolumeSourceVDDK{
+							URL:         url,
+							UUID:        vm.UUID,
+							BackingFile: disk.File,
+							Thumbprint:  string(secret.Data["thumbprint"]),
+							SecretRef:   spec.ProviderCredentialsSecret.Name,
+						},
+					},
+					PVC: &core.PersistentVolumeClaimSpec{
+						AccessModes: []core.PersistentVolumeAccessMode{
+							core.ReadWriteMany,
+						},
+						VolumeMode:       &block,
+						StorageClassName: &mp.SharedDisks.StorageClass,
+						Resources: core.ResourceRequirements{
+							Requests: core.ResourceList{
+								core.ResourceStorage: *resource.NewQuantity(
+									disk.Capacity,
+									resource.BinarySI),
+							},
+						},
+					},
+				},
+			})
+		placeholders = append(
+			placeholders,
+			cdi.DataVolume{
+				ObjectMeta: meta.ObjectMeta{
+					Name: r.placeholderName(vmName, disk),
+				},
+				Spec: cdi.DataVolumeSpec{
+					Source: cdi.DataVolumeSource{
+						Blank: &cdi.DataVolumeBlankImage{},
+					},
+					PVC: &core.PersistentVolumeClaimSpec{
+						AccessModes: []core.PersistentVolumeAccessMode{
+							core.ReadWriteOnce,
+						},
+						VolumeMode:       &block,
+						StorageClassName: &mp.SharedDisks.StorageClass,
+						Resources: core.ResourceRequirements{
+							Requests: core.ResourceList{
+								core.ResourceStorage: resource.MustParse("1Mi"),
+							},
+						},
+					},
+				},
+			})
+	}
+
+	return
+}
+
 //
 // Build tasks.
 func (r *Builder) Tasks(vmRef ref.Ref) (list []*plan.Task, err error) {
@@ -143,6 +325,54 @@ func (r *Builder) Tasks(vmRef ref.Ref) (list []*plan.Task, err error) {
 	return
 }
 
+//
+// Build (update) the KubeVirt VM created by the import.
+// The shared disks are attached. The VM is started when
+// the source VM is powered on. The DataVolumes are those
+// associated with the import.
+func (r *Builder) VirtualMachine(vmRef ref.Ref, mp *plan.Map, object *unstructured.Unstructured, dataVolumes []cdi.DataVolume) (err error) {
+	vm := &model.VM{}
+	pErr := r.Source.Inventory.Find(vm, vmRef)
+	if pErr != nil {
+		err = liberr.New(
+			fmt.Sprintf(
+				"VM %s lookup failed: %s",
+				vmRef.String(),
+				pErr.Error()))
+		return
+	}
+	err = r.firmware(vm, object)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = r.diskBus(vm, object, dataVolumes)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = r.resources(vm, object)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if mp.SharedDisks != nil {
+		err = r.attachSharedDisks(vm, object)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	start := vm.PowerState == string(types.VirtualMachinePowerStatePoweredOn)
+	err = unstructured.SetNestedField(object.Object, start, "spec", "running")
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
 //
 // Load
 func (r *Builder) Load() (err error) {
@@ -320,6 +550,49 @@ func (r *Builder) mapping(in *plan.Map, vm *model.VM) (out *vmio.VmwareMappings,
 				Type: &mapped.Destination.Type,
 			})
 	}
+	storage, err := r.storageMapping(in, vm)
+	if err != nil {
+		return
+	}
+	for _, mapped := range storage {
+		id := mapped.ID
+		item := vmio.StorageResourceMappingItem{
+			Source: vmio.Source{
+				ID: &id,
+			},
+			Target: vmio.ObjectIdentifier{
+				Name: mapped.Destination.StorageClass,
+			},
+		}
+		if mapped.Destination.VolumeMode != "" {
+			item.VolumeMode = &mapped.Destination.VolumeMode
+		}
+		// The access mode is not supported by the VMIO (v0.2.5)
+		// API and is applied when the import is created.
+		// See: AccessModes().
+		dsMap = append(dsMap, item)
+	}
+	out = &vmio.VmwareMappings{
+		NetworkMappings: &netMap,
+		StorageMappings: &dsMap,
+	}
+
+	return
+}
+
+//
+// Storage mapping entry.
+type storageMapping struct {
+	// Datastore (mapping source) ID.
+	// Translated to the ESX host oriented ID as needed.
+	ID string
+	// Destination with the modes defaulted.
+	Destination *mapped.DestinationStorage
+}
+
+//
+// Build the storage mapping for the datastores used by the VM.
+func (r *Builder) storageMapping(in *plan.Map, vm *model.VM) (list []storageMapping, err error) {
 	for i := range in.Datastores {
 		mapped := &in.Datastores[i]
 		ref := mapped.Source
@@ -346,29 +619,15 @@ func (r *Builder) mapping(in *plan.Map, vm *model.VM) (out *vmio.VmwareMappings,
 		}
 		mErr := r.defaultModes(&mapped.Destination)
 		if mErr != nil {
-			err = liberr.Wrap(pErr)
+			err = liberr.Wrap(mErr)
 			return
 		}
-		item := vmio.StorageResourceMappingItem{
-			Source: vmio.Source{
-				ID: &id,
-			},
-			Target: vmio.ObjectIdentifier{
-				Name: mapped.Destination.StorageClass,
-			},
-		}
-		if mapped.Destination.VolumeMode != "" {
-			item.VolumeMode = &mapped.Destination.VolumeMode
-		}
-		/* VMIO > 0.2.5 needed.
-		if mapped.Destination.AccessMode != "" {
-			item = &mapped.Destination.AccessMode
-		}*/
-		dsMap = append(dsMap, item)
-	}
-	out = &vmio.VmwareMappings{
-		NetworkMappings: &netMap,
-		StorageMappings: &dsMap,
+		list = append(
+			list,
+			storageMapping{
+				ID:          id,
+				Destination: &mapped.Destination,
+			})
 	}
 
 	return
@@ -454,6 +713,8 @@ func (r *Builder) esxHost(vm *model.VM) (esxHost *EsxHost, found bool, err error
 
 //
 // Set volume and access modes.
+// Modes not specified in the mapping are defaulted using
+// the Provisioner CR matched by storage class provisioner.
 func (r *Builder) defaultModes(dm *mapped.DestinationStorage) (err error) {
 	model := &ocp.StorageClass{}
 	err = r.Destination.Inventory.Get(model, dm.StorageClass)
@@ -476,3 +737,413 @@ func (r *Builder) defaultModes(dm *mapped.DestinationStorage) (err error) {
 
 	return
 }
+
+//
+// Translate the CPU and memory allocation.
+// The VM cannot consume more than the limit of an enclosing
+// resource pool (or vApp) so the effective limit is the lowest
+// of the VM and the pools. A limit <= 0 is unlimited. Pool
+// reservations are shared by the VMs in the pool and are not
+// mapped. CPU (MHz) is translated to cores using the host CPU
+// speed. The VMIO (v0.2.5) requests the configured memory which
+// is used as the guest memory so the memory reservation is
+// already satisfied and the memory limit is not set below
+// the configured memory.
+func (r *Builder) resources(vm *model.VM, object *unstructured.Unstructured) (err error) {
+	cpuLimit, memoryLimit, err := r.limits(vm)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	resources := []string{"spec", "template", "spec", "domain", "resources"}
+	set := func(kind, name string, q *resource.Quantity) (err error) {
+		err = unstructured.SetNestedField(
+			object.Object,
+			q.String(),
+			append(resources, kind, name)...)
+		if err != nil {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	if memoryLimit > 0 {
+		if memoryLimit < int64(vm.MemoryMB) {
+			memoryLimit = int64(vm.MemoryMB)
+		}
+		err = set("limits", string(core.ResourceMemory), resource.NewQuantity(memoryLimit*0x100000, resource.BinarySI))
+		if err != nil {
+			return
+		}
+	}
+	if vm.Host.ID == "" {
+		return
+	}
+	host := &model.Host{}
+	pErr := r.Source.Inventory.Find(host, ref.Ref{ID: vm.Host.ID})
+	if pErr != nil {
+		err = liberr.New(
+			fmt.Sprintf(
+				"Host %s lookup failed: %s",
+				vm.Host.ID,
+				pErr.Error()))
+		return
+	}
+	if host.CpuMhz <= 0 {
+		return
+	}
+	cores := func(mhz int64) *resource.Quantity {
+		return resource.NewMilliQuantity(
+			(mhz*1000+int64(host.CpuMhz)-1)/int64(host.CpuMhz),
+			resource.DecimalSI)
+	}
+	cpuRequest := vm.CpuAllocation.Reservation
+	if cpuRequest > 0 {
+		err = set("requests", string(core.ResourceCPU), cores(cpuRequest))
+		if err != nil {
+			return
+		}
+	}
+	if cpuLimit > 0 {
+		if cpuLimit < cpuRequest {
+			cpuLimit = cpuRequest
+		}
+		err = set("limits", string(core.ResourceCPU), cores(cpuLimit))
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+//
+// Effective CPU (MHz) and memory (MB) limits.
+// The lowest limit of the VM and the enclosing resource
+// pools (and vApps). A limit <= 0 is unlimited.
+func (r *Builder) limits(vm *model.VM) (cpu, memory int64, err error) {
+	lower := func(current, limit int64) int64 {
+		if limit > 0 && (current <= 0 || limit < current) {
+			return limit
+		}
+		return current
+	}
+	cpu = lower(0, vm.CpuAllocation.Limit)
+	memory = lower(0, vm.MemoryAllocation.Limit)
+	next := vm.ResourcePool
+	for next.ID != "" {
+		pool := &model.ResourcePool{}
+		switch next.Kind {
+		case vsphere.PoolKind:
+			err = r.Source.Inventory.Find(pool, ref.Ref{ID: next.ID})
+		case vsphere.VAppKind:
+			vApp := &model.VApp{}
+			err = r.Source.Inventory.Find(vApp, ref.Ref{ID: next.ID})
+			pool = &vApp.ResourcePool
+		default:
+			return
+		}
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		cpu = lower(cpu, pool.CpuAllocation.Limit)
+		memory = lower(memory, pool.MemoryAllocation.Limit)
+		next = pool.Parent
+	}
+
+	return
+}
+
+//
+// Translate the firmware.
+// The VMIO (v0.2.5) maps EFI without regard to secure boot.
+// Secure boot requires SMM.
+func (r *Builder) firmware(vm *model.VM, object *unstructured.Unstructured) (err error) {
+	domain := []string{"spec", "template", "spec", "domain"}
+	bootloader := map[string]interface{}{}
+	switch vm.Firmware {
+	case EFI:
+		bootloader["efi"] = map[string]interface{}{
+			"secureBoot": vm.SecureBoot,
+		}
+	default:
+		bootloader["bios"] = map[string]interface{}{}
+	}
+	err = unstructured.SetNestedMap(
+		object.Object,
+		bootloader,
+		append(domain, "firmware", "bootloader")...)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if vm.Firmware == EFI && vm.SecureBoot {
+		err = unstructured.SetNestedField(
+			object.Object,
+			true,
+			append(domain, "features", "smm", "enabled")...)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+
+	return
+}
+
+//
+// Set the disk bus.
+// The VMIO (v0.2.5) maps all disks to the virtio bus. The
+// disks are matched to the VM disks using the backing file of
+// the DataVolume referenced by the volume of the same name.
+// Only the bus is set; other disk settings are preserved.
+func (r *Builder) diskBus(vm *model.VM, object *unstructured.Unstructured, dataVolumes []cdi.DataVolume) (err error) {
+	vFields := []string{"spec", "template", "spec", "volumes"}
+	dFields := []string{"spec", "template", "spec", "domain", "devices", "disks"}
+	list, found, err := unstructured.NestedSlice(object.Object, dFields...)
+	if err != nil || !found {
+		err = liberr.Wrap(err)
+		return
+	}
+	volumes, _, err := unstructured.NestedSlice(object.Object, vFields...)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	byFile := map[string]*vsphere.Disk{}
+	for i := range vm.Disks {
+		disk := &vm.Disks[i]
+		byFile[disk.File] = disk
+	}
+	byDv := map[string]*vsphere.Disk{}
+	for i := range dataVolumes {
+		dv := &dataVolumes[i]
+		if dv.Spec.Source.VDDK == nil {
+			continue
+		}
+		if disk, found := byFile[dv.Spec.Source.VDDK.BackingFile]; found {
+			byDv[dv.Name] = disk
+		}
+	}
+	byName := map[string]*vsphere.Disk{}
+	for _, item := range volumes {
+		volume, cast := item.(map[string]interface{})
+		if !cast {
+			continue
+		}
+		name, _ := volume["name"].(string)
+		dvName, _, _ := unstructured.NestedString(volume, "dataVolume", "name")
+		if disk, found := byDv[dvName]; found {
+			byName[name] = disk
+		}
+	}
+	for _, item := range list {
+		disk, cast := item.(map[string]interface{})
+		if !cast {
+			continue
+		}
+		target, cast := disk["disk"].(map[string]interface{})
+		if !cast {
+			continue
+		}
+		name, _ := disk["name"].(string)
+		target["bus"] = r.selectDiskBus(vm, byName[name])
+	}
+	err = unstructured.SetNestedSlice(object.Object, list, dFields...)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
+//
+// Attach the shared disks.
+// The volume and disk are added (as needed) for each shared
+// DataVolume. The volumes (and disks) referencing the shared
+// disk placeholders are removed. See: SharedDisks().
+func (r *Builder) attachSharedDisks(vm *model.VM, object *unstructured.Unstructured) (err error) {
+	vFields := []string{"spec", "template", "spec", "volumes"}
+	dFields := []string{"spec", "template", "spec", "domain", "devices", "disks"}
+	volumes, _, err := unstructured.NestedSlice(object.Object, vFields...)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	disks, _, err := unstructured.NestedSlice(object.Object, dFields...)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	placeholders := map[string]bool{}
+	for i := range vm.Disks {
+		disk := &vm.Disks[i]
+		if disk.Shared && !disk.RDM {
+			placeholders[r.placeholderName(object.GetName(), disk)] = true
+		}
+	}
+	detached := map[string]bool{}
+	attached := map[string]bool{}
+	kept := []interface{}{}
+	for _, item := range volumes {
+		volume, cast := item.(map[string]interface{})
+		if !cast {
+			kept = append(kept, item)
+			continue
+		}
+		name, _ := volume["name"].(string)
+		dvName, _, _ := unstructured.NestedString(volume, "dataVolume", "name")
+		if placeholders[dvName] {
+			detached[name] = true
+			continue
+		}
+		attached[name] = true
+		kept = append(kept, item)
+	}
+	volumes = kept
+	kept = []interface{}{}
+	for _, item := range disks {
+		if disk, cast := item.(map[string]interface{}); cast {
+			name, _ := disk["name"].(string)
+			if detached[name] {
+				continue
+			}
+		}
+		kept = append(kept, item)
+	}
+	disks = kept
+	for i := range vm.Disks {
+		disk := &vm.Disks[i]
+		if !disk.Shared || disk.RDM {
+			continue
+		}
+		dvName := r.sharedDiskName(disk)
+		name := labelValue("dv-" + dvName)
+		if attached[name] {
+			continue
+		}
+		volumes = append(
+			volumes,
+			map[string]interface{}{
+				"name": name,
+				"dataVolume": map[string]interface{}{
+					"name": dvName,
+				},
+			})
+		disks = append(
+			disks,
+			map[string]interface{}{
+				"name": name,
+				"disk": map[string]interface{}{
+					"bus": r.selectDiskBus(vm, disk),
+				},
+			})
+	}
+	err = unstructured.SetNestedSlice(object.Object, volumes, vFields...)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = unstructured.SetNestedSlice(object.Object, disks, dFields...)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
+//
+// Shared disk DataVolume name.
+// Unique to the plan, migration and the disk (backing file).
+func (r *Builder) sharedDiskName(disk *vsphere.Disk) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(disk.File))
+	uid := string(r.Plan.Status.Migration.Active)
+	if len(uid) > 4 {
+		uid = uid[len(uid)-4:]
+	}
+	parts := []string{
+		"plan",
+		r.Plan.Name,
+		"shared",
+		fmt.Sprintf("%08x", h.Sum32()),
+	}
+	if uid != "" {
+		parts = append(parts, uid)
+	}
+
+	return dnsName(strings.Join(parts, "-"))
+}
+
+//
+// Shared disk placeholder DataVolume name.
+// Matches the name of the DataVolume imported by the VMIO
+// (v0.2.5) for the disk. See: buildDataVolumeName() in the
+// VMIO pkg/providers/vmware/mapper/mapper.go.
+func (r *Builder) placeholderName(vmName string, disk *vsphere.Disk) string {
+	return dnsName(vmName + "-" + disk.Label)
+}
+
+//
+// Select the disk bus.
+// The plan (override) is used when specified. Otherwise
+// selected by guest OS and the disk controller. Guests
+// without virtio drivers (Windows) and disks on an IDE
+// controller use the sata bus.
+func (r *Builder) selectDiskBus(vm *model.VM, disk *vsphere.Disk) (bus string) {
+	bus = r.Plan.Spec.DiskBus
+	if bus != "" {
+		return
+	}
+	bus = Virtio
+	if strings.Contains(strings.ToLower(vm.GuestName), "windows") {
+		bus = Sata
+		return
+	}
+	if disk != nil && disk.Bus == vsphere.IDE {
+		bus = Sata
+	}
+
+	return
+}
+
+//
+// Normalize the name to a DNS (1123) subdomain.
+// Matches the VMIO (v0.2.5) name normalization.
+func dnsName(name string) string {
+	if len(validation.IsDNS1123Subdomain(name)) == 0 {
+		return name
+	}
+	legal := func(c rune) bool {
+		return unicode.IsLower(c) || unicode.IsDigit(c)
+	}
+	name = strings.ToLower(name)
+	name = strings.ReplaceAll(name, ".", "-")
+	first := strings.IndexFunc(name, legal)
+	last := strings.LastIndexFunc(name, legal)
+	if first < 0 {
+		return ""
+	}
+	name = name[first : last+1]
+	name = illegalDNS.ReplaceAllString(name, "")
+	if len(name) > validation.DNS1123SubdomainMaxLength {
+		name = name[:validation.DNS1123SubdomainMaxLength]
+	}
+
+	return name
+}
+
+//
+// Shorten the value to the max label value length.
+// Matches the VMIO (v0.2.5) label value shortening.
+func labelValue(value string) string {
+	n := len(value)
+	if n > validation.LabelValueMaxLength {
+		suffix := strconv.Itoa(n)
+		value = value[:validation.LabelValueMaxLength-len(suffix)-1] + "-" + suffix
+	}
+
+	return value
+}
@@ -0,0 +1,860 @@
+package vsphere
+
+import (
+	"fmt"
+	libcnd "github.com/konveyor/controller/pkg/condition"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1/mapped"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1/ref"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/vcsim"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ocp"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
+	"github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	cdi "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	vmio "kubevirt.io/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"strings"
+	"testing"
+)
+
+//
+// Test defaults.
+const (
+	StorageClass = "standard"
+	Provisioner  = "kubernetes.io/no-provisioner"
+)
+
+func TestBuilder(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	h := &vcsim.Harness{}
+	defer h.Stop()
+	g.Expect(h.Start()).NotTo(gomega.HaveOccurred())
+	builder := build(g, h)
+	vm := firstVM(g, builder)
+	vmRef := ref.Ref{ID: vm.ID}
+
+	// Secret.
+	secret := &core.Secret{}
+	g.Expect(builder.Secret(vmRef, h.Secret, secret)).NotTo(gomega.HaveOccurred())
+	content := map[string]string{}
+	g.Expect(yaml.Unmarshal([]byte(secret.StringData["vmware"]), &content)).NotTo(gomega.HaveOccurred())
+	g.Expect(content["apiUrl"]).To(gomega.Equal(h.Provider.Spec.URL))
+	g.Expect(content["username"]).To(gomega.Equal(string(h.Secret.Data["user"])))
+	g.Expect(content["thumbprint"]).To(gomega.Equal(string(h.Secret.Data["thumbprint"])))
+	g.Expect(secret.StringData["accessKeyId"]).To(gomega.Equal(string(h.Secret.Data["user"])))
+	g.Expect(secret.StringData["secretKey"]).To(gomega.Equal(string(h.Secret.Data["password"])))
+
+	// Import.
+	mp := mapping(vm)
+	spec := &vmio.VirtualMachineImportSpec{}
+	g.Expect(builder.Import(vmRef, mp, spec)).NotTo(gomega.HaveOccurred())
+	g.Expect(*spec.TargetVMName).To(gomega.Equal(vm.Name))
+	g.Expect(*spec.Source.Vmware.VM.ID).To(gomega.Equal(vm.UUID))
+	g.Expect(*spec.StartVM).To(gomega.BeFalse())
+	mappings := spec.Source.Vmware.Mappings
+	netMap := *mappings.NetworkMappings
+	g.Expect(netMap).To(gomega.HaveLen(len(vm.Networks)))
+	for i, item := range netMap {
+		g.Expect(*item.Source.ID).To(gomega.Equal(vm.Networks[i].ID))
+		g.Expect(*item.Type).To(gomega.Equal("pod"))
+	}
+	dsMap := *mappings.StorageMappings
+	g.Expect(dsMap).To(gomega.HaveLen(1))
+	g.Expect(*dsMap[0].Source.ID).To(gomega.Equal(vm.Disks[0].Datastore.ID))
+	g.Expect(dsMap[0].Target.Name).To(gomega.Equal(StorageClass))
+	// Defaulted by the provisioner.
+	g.Expect(*dsMap[0].VolumeMode).To(gomega.Equal(core.PersistentVolumeBlock))
+	g.Expect(mappings.DiskMappings).To(gomega.BeNil())
+
+	// Access modes (keyed by mapping source ID).
+	modes, err := builder.AccessModes(vmRef, mp)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(modes).To(
+		gomega.Equal(
+			map[string]core.PersistentVolumeAccessMode{
+				*dsMap[0].Source.ID: core.ReadWriteOnce,
+			}))
+
+	// Tasks.
+	tasks, err := builder.Tasks(vmRef)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(tasks).To(gomega.HaveLen(len(vm.Disks)))
+	for i, task := range tasks {
+		g.Expect(task.Name).To(gomega.Equal(vm.Disks[i].File))
+		g.Expect(task.Progress.Total).To(gomega.Equal(vm.Disks[i].Capacity / 0x100000))
+	}
+
+	// VirtualMachine.
+	object := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"domain": map[string]interface{}{
+							"devices": map[string]interface{}{
+								"disks": []interface{}{
+									map[string]interface{}{
+										"name": "dv-disk0",
+										"disk": map[string]interface{}{},
+									},
+								},
+							},
+						},
+						"volumes": []interface{}{
+							map[string]interface{}{
+								"name": "dv-disk0",
+								"dataVolume": map[string]interface{}{
+									"name": "disk0",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	object.SetName(vm.Name)
+	dataVolumes := []cdi.DataVolume{
+		{
+			ObjectMeta: meta.ObjectMeta{
+				Name: "disk0",
+			},
+			Spec: cdi.DataVolumeSpec{
+				Source: cdi.DataVolumeSource{
+					VDDK: &cdi.DataVolumeSourceVDDK{
+						BackingFile: vm.Disks[0].File,
+					},
+				},
+			},
+		},
+	}
+	g.Expect(builder.VirtualMachine(vmRef, mp, object, dataVolumes)).NotTo(gomega.HaveOccurred())
+	running, _, _ := unstructured.NestedBool(object.Object, "spec", "running")
+	g.Expect(running).To(gomega.Equal(vm.PowerState == "poweredOn"))
+	disks, _, _ := unstructured.NestedSlice(object.Object, "spec", "template", "spec", "domain", "devices", "disks")
+	bus, _, _ := unstructured.NestedString(disks[0].(map[string]interface{}), "disk", "bus")
+	g.Expect(vm.Disks[0].Bus).To(gomega.Equal(vsphere.SCSI))
+	g.Expect(bus).To(gomega.Equal(Virtio))
+}
+
+func TestDiskBus(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	disk := func(label, bus string) vsphere.Disk {
+		return vsphere.Disk{
+			File:  "[ds-1] test/" + label + ".vmdk",
+			Label: label,
+			Bus:   bus,
+		}
+	}
+	cases := []struct {
+		name     string
+		guest    string
+		override string
+		disks    []vsphere.Disk
+		// By disk label.
+		expected map[string]string
+	}{
+		{
+			name:  "linux",
+			guest: "Red Hat Enterprise Linux 8 (64-bit)",
+			disks: []vsphere.Disk{
+				disk("Hard disk 1", vsphere.SCSI),
+				disk("Hard disk 2", vsphere.SCSI),
+			},
+			expected: map[string]string{
+				"Hard disk 1": Virtio,
+				"Hard disk 2": Virtio,
+			},
+		},
+		{
+			name:  "windows",
+			guest: "Microsoft Windows Server 2016 (64-bit)",
+			disks: []vsphere.Disk{
+				disk("Hard disk 1", vsphere.SCSI),
+				disk("Hard disk 2", vsphere.SCSI),
+			},
+			expected: map[string]string{
+				"Hard disk 1": Sata,
+				"Hard disk 2": Sata,
+			},
+		},
+		{
+			name:  "ide only",
+			guest: "Other Linux (64-bit)",
+			disks: []vsphere.Disk{
+				disk("Hard disk 1", vsphere.IDE),
+				disk("Hard disk 2", vsphere.IDE),
+			},
+			expected: map[string]string{
+				"Hard disk 1": Sata,
+				"Hard disk 2": Sata,
+			},
+		},
+		{
+			name:  "ide and scsi",
+			guest: "Other Linux (64-bit)",
+			disks: []vsphere.Disk{
+				disk("Hard disk 2", vsphere.IDE),
+				disk("Hard disk 10", vsphere.SCSI),
+			},
+			expected: map[string]string{
+				"Hard disk 2":  Sata,
+				"Hard disk 10": Virtio,
+			},
+		},
+		{
+			name:     "override",
+			guest:    "Microsoft Windows Server 2016 (64-bit)",
+			override: Scsi,
+			disks: []vsphere.Disk{
+				disk("Hard disk 1", vsphere.IDE),
+				disk("Hard disk 2", vsphere.SCSI),
+			},
+			expected: map[string]string{
+				"Hard disk 1": Scsi,
+				"Hard disk 2": Scsi,
+			},
+		},
+	}
+	for _, c := range cases {
+		builder := &Builder{
+			Context: &plancontext.Context{
+				Plan: &api.Plan{
+					Spec: api.PlanSpec{
+						DiskBus: c.override,
+					},
+				},
+			},
+		}
+		vm := &model.VM{
+			GuestName: c.guest,
+			Disks:     c.disks,
+		}
+		vm.Name = "Test.VM"
+		// Listed by name (as mapped by the VMIO). The
+		// DataVolume names are not derived from the disk.
+		names := map[string]string{}
+		disks := []interface{}{
+			map[string]interface{}{
+				"name":  "cdrom",
+				"cdrom": map[string]interface{}{},
+			},
+		}
+		volumes := []interface{}{}
+		dataVolumes := []cdi.DataVolume{}
+		for i := range c.disks {
+			disk := &c.disks[i]
+			dvName := fmt.Sprintf("imported-%d", i)
+			name := "dv-" + dvName
+			names[name] = disk.Label
+			disks = append(
+				disks,
+				map[string]interface{}{
+					"name": name,
+					"disk": map[string]interface{}{
+						"readonly": true,
+					},
+				})
+			volumes = append(
+				volumes,
+				map[string]interface{}{
+					"name": name,
+					"dataVolume": map[string]interface{}{
+						"name": dvName,
+					},
+				})
+			dataVolumes = append(
+				dataVolumes,
+				cdi.DataVolume{
+					ObjectMeta: meta.ObjectMeta{
+						Name: dvName,
+					},
+					Spec: cdi.DataVolumeSpec{
+						Source: cdi.DataVolumeSource{
+							VDDK: &cdi.DataVolumeSourceVDDK{
+								BackingFile: disk.File,
+							},
+						},
+					},
+				})
+		}
+		object := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		object.SetName(vm.Name)
+		fields := []string{"spec", "template", "spec", "domain", "devices", "disks"}
+		g.Expect(unstructured.SetNestedSlice(object.Object, disks, fields...)).NotTo(gomega.HaveOccurred())
+		g.Expect(
+			unstructured.SetNestedSlice(
+				object.Object,
+				volumes,
+				"spec", "template", "spec", "volumes")).NotTo(gomega.HaveOccurred())
+		g.Expect(builder.diskBus(vm, object, dataVolumes)).NotTo(gomega.HaveOccurred(), c.name)
+		disks, _, _ = unstructured.NestedSlice(object.Object, fields...)
+		actual := map[string]string{}
+		for _, item := range disks {
+			disk := item.(map[string]interface{})
+			if _, found := disk["cdrom"]; found {
+				_, found, _ := unstructured.NestedString(disk, "cdrom", "bus")
+				g.Expect(found).To(gomega.BeFalse(), c.name)
+				continue
+			}
+			bus, _, _ := unstructured.NestedString(disk, "disk", "bus")
+			readOnly, _, _ := unstructured.NestedBool(disk, "disk", "readonly")
+			g.Expect(readOnly).To(gomega.BeTrue(), c.name)
+			actual[names[disk["name"].(string)]] = bus
+		}
+		g.Expect(actual).To(gomega.Equal(c.expected), c.name)
+	}
+}
+
+func TestSharedDisks(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	vm := &model.VM{
+		UUID: "4200-0001",
+		Disks: []vsphere.Disk{
+			{
+				Label:    "Hard disk 1",
+				File:     "[ds0] vm/vm.vmdk",
+				Capacity: 0x40000000,
+				Bus:      vsphere.SCSI,
+			},
+			{
+				Label:    "Hard disk 2",
+				File:     "[ds0] shared/shared.vmdk",
+				Capacity: 0x80000000,
+				Shared:   true,
+				Bus:      vsphere.SCSI,
+			},
+			{
+				Label:  "Hard disk 3",
+				Shared: true,
+				RDM:    true,
+			},
+		},
+	}
+	vm.ID = "vm-1"
+	vm.Name = "Test.VM"
+	builder := &Builder{
+		Context: &plancontext.Context{
+			Plan: &api.Plan{
+				ObjectMeta: meta.ObjectMeta{
+					Name: "test",
+				},
+			},
+			Source: plancontext.Source{
+				Provider: &api.Provider{
+					Spec: api.ProviderSpec{
+						URL: "https://vcenter/sdk",
+					},
+				},
+				Inventory: &Source{vm: vm},
+				Secret: &core.Secret{
+					Data: map[string][]byte{
+						"thumbprint": []byte("AA:BB"),
+					},
+				},
+			},
+		},
+	}
+	builder.Plan.Status.Migration.Active = "0000-abcd"
+	vmRef := ref.Ref{ID: vm.ID}
+	spec := &vmio.VirtualMachineImportSpec{
+		TargetVMName: &vm.Name,
+		ProviderCredentialsSecret: vmio.ObjectIdentifier{
+			Name: "secret",
+		},
+	}
+
+	// Not mapped.
+	shared, placeholders, err := builder.SharedDisks(vmRef, &plan.Map{}, spec)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(shared).To(gomega.BeEmpty())
+	g.Expect(placeholders).To(gomega.BeEmpty())
+
+	// Mapped.
+	mp := &plan.Map{
+		SharedDisks: &mapped.SharedDisks{
+			StorageClass: "shared",
+		},
+	}
+	shared, placeholders, err = builder.SharedDisks(vmRef, mp, spec)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(shared).To(gomega.HaveLen(1))
+	g.Expect(placeholders).To(gomega.HaveLen(1))
+	dv := shared[0]
+	g.Expect(dv.Name).To(gomega.Equal(builder.sharedDiskName(&vm.Disks[1])))
+	g.Expect(strings.HasPrefix(dv.Name, "plan-test-shared-")).To(gomega.BeTrue())
+	g.Expect(strings.HasSuffix(dv.Name, "-abcd")).To(gomega.BeTrue())
+	g.Expect(dv.Spec.Source.VDDK.URL).To(gomega.Equal("https://vcenter/sdk"))
+	g.Expect(dv.Spec.Source.VDDK.UUID).To(gomega.Equal(vm.UUID))
+	g.Expect(dv.Spec.Source.VDDK.BackingFile).To(gomega.Equal(vm.Disks[1].File))
+	g.Expect(dv.Spec.Source.VDDK.Thumbprint).To(gomega.Equal("AA:BB"))
+	g.Expect(dv.Spec.Source.VDDK.SecretRef).To(gomega.Equal("secret"))
+	g.Expect(dv.Spec.PVC.AccessModes).To(gomega.Equal([]core.PersistentVolumeAccessMode{core.ReadWriteMany}))
+	g.Expect(*dv.Spec.PVC.VolumeMode).To(gomega.Equal(core.PersistentVolumeBlock))
+	g.Expect(*dv.Spec.PVC.StorageClassName).To(gomega.Equal("shared"))
+	capacity := dv.Spec.PVC.Resources.Requests[core.ResourceStorage]
+	g.Expect(capacity.Value()).To(gomega.Equal(vm.Disks[1].Capacity))
+	// Named as expected by the VMIO.
+	placeholder := placeholders[0]
+	g.Expect(placeholder.Name).To(gomega.Equal("test-vm-harddisk2"))
+	g.Expect(placeholder.Spec.Source.Blank).NotTo(gomega.BeNil())
+	g.Expect(placeholder.Spec.Source.VDDK).To(gomega.BeNil())
+
+	// Same DataVolume (name) for each VM.
+	other := *vm
+	other.ID = "vm-2"
+	other.Name = "other"
+	builder.Source.Inventory = &Source{vm: &other}
+	otherShared, otherPlaceholders, err := builder.SharedDisks(ref.Ref{ID: other.ID}, mp, spec)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(otherShared[0].Name).To(gomega.Equal(dv.Name))
+	g.Expect(otherPlaceholders[0].Name).To(gomega.Equal(placeholder.Name))
+
+	// Attached.
+	object := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	object.SetName(vm.Name)
+	volumes := []string{"spec", "template", "spec", "volumes"}
+	disks := []string{"spec", "template", "spec", "domain", "devices", "disks"}
+	name := "dv-disk0"
+	// Added by the VMIO for the placeholder.
+	placeholderName := "dv-" + placeholder.Name
+	g.Expect(
+		unstructured.SetNestedSlice(
+			object.Object,
+			[]interface{}{
+				map[string]interface{}{
+					"name": name,
+					"dataVolume": map[string]interface{}{
+						"name": name[3:],
+					},
+				},
+				map[string]interface{}{
+					"name": placeholderName,
+					"dataVolume": map[string]interface{}{
+						"name": placeholder.Name,
+					},
+				},
+			},
+			volumes...)).NotTo(gomega.HaveOccurred())
+	g.Expect(
+		unstructured.SetNestedSlice(
+			object.Object,
+			[]interface{}{
+				map[string]interface{}{
+					"name": name,
+					"disk": map[string]interface{}{},
+				},
+				map[string]interface{}{
+					"name": placeholderName,
+					"disk": map[string]interface{}{},
+				},
+			},
+			disks...)).NotTo(gomega.HaveOccurred())
+	builder.Source.Inventory = &Source{vm: vm}
+	for i := 0; i < 2; i++ {
+		g.Expect(builder.VirtualMachine(vmRef, mp, object, nil)).NotTo(gomega.HaveOccurred())
+	}
+	// The placeholder is replaced by the shared disk.
+	list, _, _ := unstructured.NestedSlice(object.Object, volumes...)
+	g.Expect(list).To(gomega.HaveLen(2))
+	g.Expect(list[0].(map[string]interface{})["name"]).To(gomega.Equal(name))
+	dvName, _, _ := unstructured.NestedString(list[1].(map[string]interface{}), "dataVolume", "name")
+	g.Expect(dvName).To(gomega.Equal(dv.Name))
+	list, _, _ = unstructured.NestedSlice(object.Object, disks...)
+	g.Expect(list).To(gomega.HaveLen(2))
+	g.Expect(list[0].(map[string]interface{})["name"]).To(gomega.Equal(name))
+	for _, item := range list {
+		g.Expect(item.(map[string]interface{})["name"]).NotTo(gomega.Equal(placeholderName))
+		bus, _, _ := unstructured.NestedString(item.(map[string]interface{}), "disk", "bus")
+		g.Expect(bus).To(gomega.Equal(Virtio))
+	}
+
+	// Short (or empty) migration UID.
+	for _, uid := range []string{"", "ab"} {
+		builder.Plan.Status.Migration.Active = types.UID(uid)
+		name := builder.sharedDiskName(&vm.Disks[1])
+		g.Expect(strings.HasPrefix(name, "plan-test-shared-")).To(gomega.BeTrue())
+		g.Expect(strings.HasSuffix(name, uid)).To(gomega.BeTrue())
+	}
+}
+
+func TestResources(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	allocation := func(reservation, limit int64) vsphere.Allocation {
+		return vsphere.Allocation{
+			Reservation: reservation,
+			Limit:       limit,
+		}
+	}
+	pool := func(id string, parent vsphere.Ref, cpu, memory int64) *model.ResourcePool {
+		m := &model.ResourcePool{
+			CpuAllocation:    allocation(0, cpu),
+			MemoryAllocation: allocation(0, memory),
+		}
+		m.ID = id
+		m.Parent = parent
+		return m
+	}
+	cluster := vsphere.Ref{Kind: vsphere.ClusterKind, ID: "cluster"}
+	root := vsphere.Ref{Kind: vsphere.PoolKind, ID: "root"}
+	vApp := vsphere.Ref{Kind: vsphere.VAppKind, ID: "vApp"}
+	host := &model.Host{CpuMhz: 2000}
+	host.ID = "host"
+	cases := []struct {
+		name   string
+		cpu    vsphere.Allocation
+		memory vsphere.Allocation
+		pools  []*model.ResourcePool
+		host   *model.Host
+		// Resources by path (kind/name).
+		expected map[string]string
+	}{
+		{
+			name:   "unlimited",
+			cpu:    allocation(0, -1),
+			memory: allocation(0, -1),
+			pools: []*model.ResourcePool{
+				pool(root.ID, cluster, -1, -1),
+			},
+			host:     host,
+			expected: map[string]string{},
+		},
+		{
+			name:     "not collected",
+			pools:    []*model.ResourcePool{},
+			host:     host,
+			expected: map[string]string{},
+		},
+		{
+			name:   "vm",
+			cpu:    allocation(1000, 3000),
+			memory: allocation(1024, 8192),
+			pools: []*model.ResourcePool{
+				pool(root.ID, cluster, -1, -1),
+			},
+			host: host,
+			expected: map[string]string{
+				"requests/cpu":  "500m",
+				"limits/cpu":    "1500m",
+				"limits/memory": "8Gi",
+			},
+		},
+		{
+			name:   "pool limits",
+			cpu:    allocation(0, 3000),
+			memory: allocation(0, -1),
+			pools: []*model.ResourcePool{
+				pool(vApp.ID, root, 4000, 6144),
+				pool(root.ID, cluster, 2000, -1),
+			},
+			host: host,
+			expected: map[string]string{
+				"limits/cpu":    "1",
+				"limits/memory": "6Gi",
+			},
+		},
+		{
+			name:   "memory limit below configured",
+			cpu:    allocation(2000, 1000),
+			memory: allocation(0, 1024),
+			pools: []*model.ResourcePool{
+				pool(root.ID, cluster, -1, -1),
+			},
+			host: host,
+			expected: map[string]string{
+				"requests/cpu":  "1",
+				"limits/cpu":    "1",
+				"limits/memory": "4Gi",
+			},
+		},
+		{
+			name:   "host speed unknown",
+			cpu:    allocation(1000, 3000),
+			memory: allocation(0, -1),
+			pools: []*model.ResourcePool{
+				pool(root.ID, cluster, -1, -1),
+			},
+			expected: map[string]string{},
+		},
+	}
+	for _, c := range cases {
+		vm := &model.VM{
+			MemoryMB:         4096,
+			CpuAllocation:    c.cpu,
+			MemoryAllocation: c.memory,
+			Host:             vsphere.Ref{Kind: vsphere.HostKind, ID: host.ID},
+			ResourcePool:     root,
+		}
+		if len(c.pools) > 1 {
+			vm.ResourcePool = vApp
+		}
+		if len(c.pools) == 0 {
+			vm.ResourcePool = vsphere.Ref{}
+		}
+		vm.ID = "vm-1"
+		builder := &Builder{
+			Context: &plancontext.Context{
+				Source: plancontext.Source{
+					Inventory: &Source{
+						vm:    vm,
+						host:  c.host,
+						pools: c.pools,
+					},
+				},
+			},
+		}
+		if c.host == nil {
+			vm.Host = vsphere.Ref{}
+		}
+		object := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		g.Expect(builder.resources(vm, object)).NotTo(gomega.HaveOccurred(), c.name)
+		actual := map[string]string{}
+		for _, kind := range []string{"requests", "limits"} {
+			list, _, _ := unstructured.NestedStringMap(
+				object.Object,
+				"spec", "template", "spec", "domain", "resources", kind)
+			for name, q := range list {
+				actual[kind+"/"+name] = q
+			}
+		}
+		g.Expect(actual).To(gomega.Equal(c.expected), c.name)
+	}
+}
+
+func TestEsxHost(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	h := &vcsim.Harness{}
+	defer h.Stop()
+	g.Expect(h.Start()).NotTo(gomega.HaveOccurred())
+	builder := build(g, h)
+	vm := firstVM(g, builder)
+	vmRef := ref.Ref{ID: vm.ID}
+	host := &model.Host{}
+	g.Expect(builder.Source.Inventory.Get(host, vm.Host.ID)).NotTo(gomega.HaveOccurred())
+
+	// Host CR.
+	// The simulator is (also) used as the ESX host.
+	hostSecret := h.Secret.DeepCopy()
+	hostSecret.Name = "esx"
+	delete(hostSecret.Data, "thumbprint")
+	hostCR := &api.Host{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: h.Provider.Namespace,
+			Name:      "esx",
+		},
+		Spec: api.HostSpec{
+			Ref:       ref.Ref{ID: host.ID},
+			IpAddress: h.Server.URL.Host,
+			Secret: core.ObjectReference{
+				Namespace: hostSecret.Namespace,
+				Name:      hostSecret.Name,
+			},
+		},
+	}
+	hostCR.Status.SetCondition(
+		libcnd.Condition{
+			Type:     libcnd.Ready,
+			Status:   libcnd.True,
+			Category: libcnd.Required,
+		})
+	builder = build(g, h, hostCR, hostSecret)
+	g.Expect(builder.hosts).To(gomega.HaveKey(host.ID))
+
+	// Secret.
+	secret := &core.Secret{}
+	g.Expect(builder.Secret(vmRef, h.Secret, secret)).NotTo(gomega.HaveOccurred())
+	content := map[string]string{}
+	g.Expect(yaml.Unmarshal([]byte(secret.StringData["vmware"]), &content)).NotTo(gomega.HaveOccurred())
+	g.Expect(content["apiUrl"]).To(gomega.Equal("https://" + h.Server.URL.Host + "/sdk"))
+	g.Expect(content["thumbprint"]).To(gomega.Equal(host.Thumbprint))
+
+	// Translated IDs.
+	esxHost, found, err := builder.esxHost(vm)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(esxHost.TestConnection()).NotTo(gomega.HaveOccurred())
+	ds := &model.Datastore{}
+	g.Expect(builder.Source.Inventory.Get(ds, vm.Disks[0].Datastore.ID)).NotTo(gomega.HaveOccurred())
+	id, err := builder.datastoreID(vm, ds)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(id).To(gomega.Equal(ds.ID))
+	network := &model.Network{}
+	g.Expect(builder.Source.Inventory.Get(network, vm.Networks[0].ID)).NotTo(gomega.HaveOccurred())
+	id, err = builder.networkID(vm, network)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(id).To(gomega.Equal(network.ID))
+
+	// Import.
+	spec := &vmio.VirtualMachineImportSpec{}
+	g.Expect(builder.Import(vmRef, mapping(vm), spec)).NotTo(gomega.HaveOccurred())
+	dsMap := *spec.Source.Vmware.Mappings.StorageMappings
+	g.Expect(*dsMap[0].Source.ID).To(gomega.Equal(ds.ID))
+}
+
+//
+// Build the builder.
+func build(g *gomega.GomegaWithT, h *vcsim.Harness, objects ...runtime.Object) (builder *Builder) {
+	s := runtime.NewScheme()
+	g.Expect(scheme.AddToScheme(s)).NotTo(gomega.HaveOccurred())
+	g.Expect(api.SchemeBuilder.AddToScheme(s)).NotTo(gomega.HaveOccurred())
+	objects = append(
+		objects,
+		h.Secret,
+		&api.Provisioner{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace: h.Provider.Namespace,
+				Name:      "local",
+			},
+			Spec: api.ProvisionerSpec{
+				Name: Provisioner,
+				VolumeModes: []api.VolumeMode{
+					{
+						Name: core.PersistentVolumeBlock,
+						AccessModes: []api.AccessMode{
+							{Name: core.ReadWriteOnce},
+						},
+					},
+				},
+			},
+		})
+	client := fake.NewFakeClientWithScheme(s, objects...)
+	inventory, err := web.NewClient(h.Provider)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	builder = &Builder{
+		Context: &plancontext.Context{
+			Client: client,
+			Plan: &api.Plan{
+				ObjectMeta: meta.ObjectMeta{
+					Namespace: h.Provider.Namespace,
+					Name:      "test",
+				},
+			},
+			Source: plancontext.Source{
+				Provider:  h.Provider,
+				Inventory: inventory,
+				Secret:    h.Secret,
+			},
+			Destination: plancontext.Destination{
+				Client:    client,
+				Inventory: &Destination{},
+			},
+		},
+	}
+	g.Expect(builder.Load()).NotTo(gomega.HaveOccurred())
+
+	return
+}
+
+//
+// First VM in the inventory.
+func firstVM(g *gomega.GomegaWithT, builder *Builder) (vm *model.VM) {
+	list := []model.VM{}
+	g.Expect(builder.Source.Inventory.List(&list)).NotTo(gomega.HaveOccurred())
+	g.Expect(list).NotTo(gomega.BeEmpty())
+	vm = &model.VM{}
+	g.Expect(builder.Source.Inventory.Get(vm, list[0].ID)).NotTo(gomega.HaveOccurred())
+	g.Expect(vm.Disks).NotTo(gomega.BeEmpty())
+	g.Expect(vm.Networks).NotTo(gomega.BeEmpty())
+
+	return
+}
+
+//
+// Map the VM networks and datastores.
+func mapping(vm *model.VM) (mp *plan.Map) {
+	mp = &plan.Map{}
+	for _, network := range vm.Networks {
+		mp.Networks = append(
+			mp.Networks,
+			mapped.NetworkPair{
+				Source: ref.Ref{ID: network.ID},
+				Destination: mapped.DestinationNetwork{
+					Type: "pod",
+				},
+			})
+	}
+	mp.Datastores = append(
+		mp.Datastores,
+		mapped.StoragePair{
+			Source: ref.Ref{ID: vm.Disks[0].Datastore.ID},
+			Destination: mapped.DestinationStorage{
+				StorageClass: StorageClass,
+			},
+		})
+
+	return
+}
+
+//
+// Destination (OpenShift) inventory.
+// Only storage classes are supported.
+type Destination struct {
+	web.Client
+}
+
+//
+// Get a resource.
+func (r *Destination) Get(resource interface{}, id string) (err error) {
+	if sc, cast := resource.(*ocp.StorageClass); cast {
+		sc.Name = id
+		sc.Object.Name = id
+		sc.Object.Provisioner = Provisioner
+		return
+	}
+	err = web.NotFoundError{}
+
+	return
+}
+
+//
+// Source (vSphere) inventory.
+// Only the VM, host and resource pools (and vApps) are supported.
+type Source struct {
+	web.Client
+	vm    *model.VM
+	host  *model.Host
+	pools []*model.ResourcePool
+}
+
+//
+// Find a resource.
+func (r *Source) Find(resource interface{}, rf ref.Ref) (err error) {
+	switch resource.(type) {
+	case *model.VM:
+		if rf.ID == r.vm.ID {
+			*resource.(*model.VM) = *r.vm
+			return
+		}
+	case *model.Host:
+		if r.host != nil && rf.ID == r.host.ID {
+			*resource.(*model.Host) = *r.host
+			return
+		}
+	case *model.ResourcePool, *model.VApp:
+		for _, pool := range r.pools {
+			if rf.ID != pool.ID {
+				continue
+			}
+			if vApp, cast := resource.(*model.VApp); cast {
+				vApp.ResourcePool = *pool
+			} else {
+				*resource.(*model.ResourcePool) = *pool
+			}
+			return
+		}
+	}
+	err = web.NotFoundError{}
+
+	return
+}
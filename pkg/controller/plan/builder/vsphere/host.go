@@ -3,6 +3,7 @@ package vsphere
 import (
 	"context"
 	liberr "github.com/konveyor/controller/pkg/error"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/certificate"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/find"
@@ -105,7 +106,10 @@ func (r *EsxHost) connect(ctx context.Context) (err error) {
 		r.user(),
 		r.password())
 	soapClient := soap.NewClient(url, false)
-	soapClient.SetThumbprint(url.Host, r.thumbprint())
+	err = certificate.TLSConfig(soapClient, r.Secret)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
 	vimClient, err := vim25.NewClient(ctx, soapClient)
 	if err != nil {
 		return liberr.Wrap(err)
@@ -143,13 +147,3 @@ func (r *EsxHost) password() string {
 
 	return ""
 }
-
-//
-// Thumbprint.
-func (r *EsxHost) thumbprint() string {
-	if password, found := r.Secret.Data["thumbprint"]; found {
-		return string(password)
-	}
-
-	return ""
-}
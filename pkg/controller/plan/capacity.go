@@ -0,0 +1,451 @@
+package plan
+
+import (
+	"context"
+	"errors"
+	libcnd "github.com/konveyor/controller/pkg/condition"
+	liberr "github.com/konveyor/controller/pkg/error"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
+	core "k8s.io/api/core/v1"
+	storage "k8s.io/api/storage/v1alpha1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
+	"strings"
+)
+
+//
+// Quota resource names.
+const (
+	// Storage class quota suffix.
+	// Format: <class>.storageclass.storage.k8s.io/requests.storage
+	StorageClassQuota = ".storageclass.storage.k8s.io/" + string(core.ResourceRequestsStorage)
+)
+
+//
+// Destination capacity.
+// Negative values are unknown (unlimited).
+type Capacity struct {
+	// Storage (bytes) by storage class.
+	Storage map[string]int64
+	// Total storage (bytes).
+	TotalStorage int64
+	// CPU (millicores).
+	CPU int64
+	// Memory (bytes).
+	Memory int64
+}
+
+//
+// Build unknown (unlimited) capacity.
+func (r *Capacity) unknown() {
+	r.Storage = map[string]int64{}
+	r.TotalStorage = -1
+	r.CPU = -1
+	r.Memory = -1
+}
+
+//
+// Storage capacity for a storage class.
+func (r *Capacity) storage(class string) int64 {
+	if n, found := r.Storage[class]; found {
+		return n
+	}
+
+	return -1
+}
+
+//
+// Lower (set) the limit.
+func lower(limit *int64, n int64) {
+	if n < 0 {
+		n = 0
+	}
+	if *limit < 0 || n < *limit {
+		*limit = n
+	}
+}
+
+//
+// Destination capacity (preflight) validation.
+// The storage, CPU and memory required by the VMs listed in the
+// plan is compared (in order) against:
+//   - The capacity reported for mapped storage classes.
+//   - ResourceQuota in the target namespace.
+//   - The allocatable capacity of schedulable nodes.
+// The VMs for which the accumulated demand exceeds the capacity
+// are reported as condition items. VMs that have been started (or
+// completed) by a migration are not included. A capacity that cannot
+// be determined is treated as unknown (unlimited) and reported by
+// the (advisory) CapacityNotValidated condition.
+type Preflight struct {
+	// Destination cluster client.
+	Client client.Client
+	// Source inventory.
+	Inventory web.Client
+	// The plan.
+	Plan *api.Plan
+	// Storage class by datastore ID.
+	storageClass map[string]string
+}
+
+//
+// Validate destination capacity.
+func (r *Preflight) Validate() (result libcnd.Conditions, err error) {
+	storageClass := libcnd.Condition{
+		Type:     InsufficientStorage,
+		Status:   True,
+		Reason:   NotValid,
+		Category: Critical,
+		Message:  "Mapped storage class capacity exceeded.",
+		Items:    []string{},
+	}
+	storageQuota := libcnd.Condition{
+		Type:     StorageQuotaExceeded,
+		Status:   True,
+		Reason:   NotValid,
+		Category: Critical,
+		Message:  "Target namespace storage quota exceeded.",
+		Items:    []string{},
+	}
+	computeQuota := libcnd.Condition{
+		Type:     ComputeQuotaExceeded,
+		Status:   True,
+		Reason:   NotValid,
+		Category: Critical,
+		Message:  "Target namespace CPU/memory quota exceeded.",
+		Items:    []string{},
+	}
+	compute := libcnd.Condition{
+		Type:     InsufficientCompute,
+		Status:   True,
+		Reason:   NotValid,
+		Category: Warn,
+		Message:  "Schedulable node CPU/memory capacity exceeded.",
+		Items:    []string{},
+	}
+	notSchedulable := libcnd.Condition{
+		Type:     VMNotSchedulable,
+		Status:   True,
+		Reason:   NotValid,
+		Category: Critical,
+		Message:  "VM CPU/memory exceeds the capacity of every schedulable node.",
+		Items:    []string{},
+	}
+	notValidated := libcnd.Condition{
+		Type:     CapacityNotValidated,
+		Status:   True,
+		Reason:   NotValidated,
+		Category: Advisory,
+		Message:  "Destination capacity could not be determined.",
+		Items:    []string{},
+	}
+	err = r.buildStorageMap()
+	if err != nil {
+		return
+	}
+	classes, cErr := r.classCapacity()
+	if cErr != nil {
+		log.Trace(cErr)
+		classes.unknown()
+		notValidated.Items = append(notValidated.Items, "storage class capacity")
+	} else {
+		for _, class := range r.mappedClasses() {
+			if _, found := classes.Storage[class]; !found {
+				notValidated.Items = append(notValidated.Items, "storage class: "+class)
+			}
+		}
+	}
+	quota, qErr := r.quota()
+	if qErr != nil {
+		log.Trace(qErr)
+		quota.unknown()
+		notValidated.Items = append(notValidated.Items, "resource quota")
+	}
+	nodes, largest, nErr := r.nodeCapacity()
+	if nErr != nil {
+		log.Trace(nErr)
+		nodes.unknown()
+		largest.unknown()
+		notValidated.Items = append(notValidated.Items, "node capacity")
+	}
+	used := Capacity{Storage: map[string]int64{}}
+	shared := map[string]bool{}
+	for _, planned := range r.Plan.Spec.VMs {
+		vmRef := planned.Ref
+		object, pErr := r.Inventory.VM(&vmRef)
+		if pErr != nil {
+			if errors.As(pErr, &web.NotFoundError{}) ||
+				errors.As(pErr, &web.RefNotUniqueError{}) {
+				continue
+			}
+			err = liberr.Wrap(pErr)
+			return
+		}
+		vm, cast := object.(*vsphere.VM)
+		if !cast {
+			continue
+		}
+		if status, found := r.Plan.Status.Migration.FindVM(vm.ID); found {
+			if status.MarkedStarted() {
+				continue
+			}
+		}
+		demand := r.demand(vm, shared)
+		item := vmRef.String()
+		exceeded := func(limit, used, demand int64) bool {
+			return limit >= 0 && used+demand > limit
+		}
+		quotaExceeded := exceeded(quota.TotalStorage, used.TotalStorage, demand.TotalStorage)
+		classExceeded := false
+		for class, n := range demand.Storage {
+			if exceeded(quota.storage(class), used.Storage[class], n) {
+				quotaExceeded = true
+			}
+			if exceeded(classes.storage(class), used.Storage[class], n) {
+				classExceeded = true
+			}
+		}
+		if quotaExceeded {
+			storageQuota.Items = append(storageQuota.Items, item)
+		}
+		if classExceeded {
+			storageClass.Items = append(storageClass.Items, item)
+		}
+		if exceeded(quota.CPU, used.CPU, demand.CPU) ||
+			exceeded(quota.Memory, used.Memory, demand.Memory) {
+			computeQuota.Items = append(computeQuota.Items, item)
+		}
+		if exceeded(largest.CPU, 0, demand.CPU) ||
+			exceeded(largest.Memory, 0, demand.Memory) {
+			notSchedulable.Items = append(notSchedulable.Items, item)
+		} else if exceeded(nodes.CPU, used.CPU, demand.CPU) ||
+			exceeded(nodes.Memory, used.Memory, demand.Memory) {
+			compute.Items = append(compute.Items, item)
+		}
+		for class, n := range demand.Storage {
+			used.Storage[class] += n
+		}
+		used.TotalStorage += demand.TotalStorage
+		used.CPU += demand.CPU
+		used.Memory += demand.Memory
+	}
+	for _, cnd := range []libcnd.Condition{
+		storageClass,
+		storageQuota,
+		computeQuota,
+		compute,
+		notSchedulable,
+		notValidated,
+	} {
+		if len(cnd.Items) > 0 {
+			result.SetCondition(cnd)
+		}
+	}
+
+	return
+}
+
+//
+// Build the storage class (by datastore ID) map.
+func (r *Preflight) buildStorageMap() (err error) {
+	r.storageClass = map[string]string{}
+	for _, mapped := range r.Plan.Spec.Map.Datastores {
+		dsRef := mapped.Source
+		object, pErr := r.Inventory.Storage(&dsRef)
+		if pErr != nil {
+			if errors.As(pErr, &web.NotFoundError{}) ||
+				errors.As(pErr, &web.RefNotUniqueError{}) {
+				continue
+			}
+			err = liberr.Wrap(pErr)
+			return
+		}
+		if ds, cast := object.(*vsphere.Datastore); cast {
+			r.storageClass[ds.ID] = mapped.Destination.StorageClass
+		}
+	}
+
+	return
+}
+
+//
+// Mapped storage classes (sorted).
+func (r *Preflight) mappedClasses() (list []string) {
+	set := map[string]bool{}
+	for _, class := range r.storageClass {
+		set[class] = true
+	}
+	if sharedDisks := r.Plan.Spec.Map.SharedDisks; sharedDisks != nil {
+		set[sharedDisks.StorageClass] = true
+	}
+	for class := range set {
+		list = append(list, class)
+	}
+	sort.Strings(list)
+
+	return
+}
+
+//
+// Resources required by a VM.
+// Shared disks (mapped) are counted once.
+func (r *Preflight) demand(vm *vsphere.VM, shared map[string]bool) (demand Capacity) {
+	demand.Storage = map[string]int64{}
+	sharedDisks := r.Plan.Spec.Map.SharedDisks
+	for _, disk := range vm.Disks {
+		class := ""
+		if disk.Shared && sharedDisks != nil {
+			if shared[disk.File] {
+				continue
+			}
+			shared[disk.File] = true
+			class = sharedDisks.StorageClass
+		} else {
+			mapped, found := r.storageClass[disk.Datastore.ID]
+			if !found {
+				continue
+			}
+			class = mapped
+		}
+		demand.Storage[class] += disk.Capacity
+		demand.TotalStorage += disk.Capacity
+	}
+	demand.CPU = int64(vm.CpuCount) * 1000
+	demand.Memory = int64(vm.MemoryMB) * 0x100000
+
+	return
+}
+
+//
+// Storage class capacity reported by CSI drivers.
+// The capacity is reported by topology segment and a volume
+// cannot span segments so the largest segment is used.
+// Unknown when not reported. Mapped classes without reported
+// capacity are listed by the CapacityNotValidated condition.
+func (r *Preflight) classCapacity() (capacity Capacity, err error) {
+	capacity.unknown()
+	list := &storage.CSIStorageCapacityList{}
+	err = r.Client.List(context.TODO(), list)
+	if err != nil {
+		if meta.IsNoMatchError(err) || k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	for _, item := range list.Items {
+		if item.Capacity == nil {
+			continue
+		}
+		n := item.Capacity.Value()
+		if n > capacity.storage(item.StorageClassName) {
+			capacity.Storage[item.StorageClassName] = n
+		}
+	}
+
+	return
+}
+
+//
+// Available (hard - used) quota in the target namespace.
+func (r *Preflight) quota() (capacity Capacity, err error) {
+	capacity.unknown()
+	list := &core.ResourceQuotaList{}
+	err = r.Client.List(
+		context.TODO(),
+		list,
+		&client.ListOptions{
+			Namespace: r.namespace(),
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, quota := range list.Items {
+		for name, hard := range quota.Status.Hard {
+			used := quota.Status.Used[name]
+			switch name {
+			case core.ResourceRequestsStorage:
+				lower(&capacity.TotalStorage, hard.Value()-used.Value())
+			case core.ResourceCPU, core.ResourceRequestsCPU, core.ResourceLimitsCPU:
+				lower(&capacity.CPU, hard.MilliValue()-used.MilliValue())
+			case core.ResourceMemory, core.ResourceRequestsMemory, core.ResourceLimitsMemory:
+				lower(&capacity.Memory, hard.Value()-used.Value())
+			default:
+				if strings.HasSuffix(string(name), StorageClassQuota) {
+					class := strings.TrimSuffix(string(name), StorageClassQuota)
+					n := capacity.storage(class)
+					lower(&n, hard.Value()-used.Value())
+					capacity.Storage[class] = n
+				}
+			}
+		}
+	}
+
+	return
+}
+
+//
+// Allocatable capacity of schedulable (ready) nodes.
+// Returns the total and the largest node capacity.
+func (r *Preflight) nodeCapacity() (total, largest Capacity, err error) {
+	total.unknown()
+	largest.unknown()
+	list := &core.NodeList{}
+	err = r.Client.List(context.TODO(), list)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, node := range list.Items {
+		if node.Spec.Unschedulable || !r.ready(&node) {
+			continue
+		}
+		cpu := node.Status.Allocatable.Cpu().MilliValue()
+		memory := node.Status.Allocatable.Memory().Value()
+		if total.CPU < 0 {
+			total.CPU = 0
+			total.Memory = 0
+		}
+		total.CPU += cpu
+		total.Memory += memory
+		if cpu > largest.CPU {
+			largest.CPU = cpu
+		}
+		if memory > largest.Memory {
+			largest.Memory = memory
+		}
+	}
+
+	return
+}
+
+//
+// Determine if the node is ready.
+func (r *Preflight) ready(node *core.Node) bool {
+	for _, cnd := range node.Status.Conditions {
+		if cnd.Type == core.NodeReady {
+			return cnd.Status == core.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+//
+// Get the target namespace.
+// Default to `plan` namespace when not specified
+// in the plan spec.
+func (r *Preflight) namespace() (ns string) {
+	ns = r.Plan.Spec.TargetNamespace
+	if ns == "" {
+		ns = r.Plan.Namespace
+	}
+
+	return
+}
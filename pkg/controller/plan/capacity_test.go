@@ -0,0 +1,352 @@
+package plan
+
+import (
+	"context"
+	libcnd "github.com/konveyor/controller/pkg/condition"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1/mapped"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1/ref"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	storage "k8s.io/api/storage/v1alpha1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"testing"
+)
+
+const GiB = 1024 * 1024 * 1024
+
+func TestPreflightStorage(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	inventory := &Inventory{
+		vms: []*vsphere.VM{
+			vm("vm-1", 1, 1024, disk("ds-1", 15*GiB)),
+			vm("vm-2", 1, 1024, disk("ds-1", 15*GiB)),
+			vm("vm-3", 1, 1024, disk("ds-2", 15*GiB)),
+		},
+	}
+	// Largest segment (not the sum).
+	preflight := &Preflight{
+		Client: fakeClient(
+			g,
+			ready(node("node-1", "8", "16Gi")),
+			segment("segment-1", "gold", "10Gi"),
+			segment("segment-2", "gold", "20Gi")),
+		Inventory: inventory,
+		Plan: buildPlan(
+			inventory,
+			map[string]string{
+				"ds-1": "gold",
+				"ds-2": "silver",
+			}),
+	}
+	result, err := preflight.Validate()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(items(result, InsufficientStorage)).To(gomega.Equal([]string{"vm-2"}))
+	g.Expect(result.HasCondition(StorageQuotaExceeded)).To(gomega.BeFalse())
+	// Mapped class without reported capacity.
+	g.Expect(items(result, CapacityNotValidated)).To(gomega.Equal([]string{"storage class: silver"}))
+
+	// Quota.
+	preflight.Client = fakeClient(
+		g,
+		ready(node("node-1", "8", "16Gi")),
+		quota(
+			preflight.Plan.Namespace,
+			core.ResourceList{
+				core.ResourceRequestsStorage:                    resource.MustParse("40Gi"),
+				core.ResourceName("silver" + StorageClassQuota): resource.MustParse("10Gi"),
+			},
+			core.ResourceList{
+				core.ResourceRequestsStorage: resource.MustParse("5Gi"),
+			}))
+	result, err = preflight.Validate()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(items(result, StorageQuotaExceeded)).To(gomega.Equal([]string{"vm-3"}))
+	g.Expect(result.HasCondition(InsufficientStorage)).To(gomega.BeFalse())
+	g.Expect(items(result, CapacityNotValidated)).To(
+		gomega.Equal(
+			[]string{
+				"storage class: gold",
+				"storage class: silver",
+			}))
+
+	// Shared disk class.
+	preflight.Plan.Spec.Map.SharedDisks = &mapped.SharedDisks{StorageClass: "shared"}
+	preflight.Client = fakeClient(
+		g,
+		ready(node("node-1", "8", "16Gi")),
+		segment("segment-1", "gold", "40Gi"),
+		segment("segment-2", "silver", "40Gi"))
+	result, err = preflight.Validate()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(items(result, CapacityNotValidated)).To(gomega.Equal([]string{"storage class: shared"}))
+	preflight.Plan.Spec.Map.SharedDisks = nil
+
+	// Started VMs are not included.
+	preflight.Plan.Status.Migration.VMs = []*plan.VMStatus{
+		{VM: plan.VM{Ref: ref.Ref{ID: "vm-1"}}},
+	}
+	preflight.Plan.Status.Migration.VMs[0].MarkStarted()
+	preflight.Client = fakeClient(
+		g,
+		ready(node("node-1", "8", "16Gi")),
+		segment("segment-1", "gold", "20Gi"))
+	result, err = preflight.Validate()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(result.HasCondition(InsufficientStorage)).To(gomega.BeFalse())
+}
+
+func TestPreflightCompute(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	inventory := &Inventory{
+		vms: []*vsphere.VM{
+			vm("vm-1", 2, 4096),
+			vm("vm-2", 2, 4096),
+			vm("vm-3", 2, 4096),
+			vm("vm-4", 16, 1024),
+		},
+	}
+	preflight := &Preflight{
+		Client: fakeClient(
+			g,
+			ready(node("node-1", "4", "8Gi")),
+			ready(node("node-2", "1", "8Gi")),
+			node("node-3", "64", "64Gi"),
+			quota(
+				"ns",
+				core.ResourceList{
+					core.ResourceRequestsCPU: resource.MustParse("5"),
+				},
+				core.ResourceList{
+					core.ResourceRequestsCPU: resource.MustParse("500m"),
+				})),
+		Inventory: inventory,
+		Plan:      buildPlan(inventory, nil),
+	}
+	result, err := preflight.Validate()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(items(result, ComputeQuotaExceeded)).To(gomega.Equal([]string{"vm-3", "vm-4"}))
+	g.Expect(items(result, InsufficientCompute)).To(gomega.Equal([]string{"vm-3"}))
+	g.Expect(items(result, VMNotSchedulable)).To(gomega.Equal([]string{"vm-4"}))
+	g.Expect(result.FindCondition(InsufficientCompute).Category).To(gomega.Equal(Warn))
+}
+
+func TestPreflightNotValidated(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	inventory := &Inventory{
+		vms: []*vsphere.VM{
+			vm("vm-1", 64, 1024, disk("ds-1", 15*GiB)),
+		},
+	}
+	preflight := &Preflight{
+		Client: &Forbidden{
+			Client: fakeClient(g, segment("segment-1", "gold", "10Gi")),
+			kinds: map[string]bool{
+				"NodeList":          true,
+				"ResourceQuotaList": true,
+			},
+		},
+		Inventory: inventory,
+		Plan: buildPlan(
+			inventory,
+			map[string]string{
+				"ds-1": "gold",
+			}),
+	}
+	result, err := preflight.Validate()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	cnd := result.FindCondition(CapacityNotValidated)
+	g.Expect(cnd).NotTo(gomega.BeNil())
+	g.Expect(cnd.Category).To(gomega.Equal(Advisory))
+	g.Expect(cnd.Items).To(gomega.Equal([]string{"resource quota", "node capacity"}))
+	// Known capacity is still validated.
+	g.Expect(items(result, InsufficientStorage)).To(gomega.Equal([]string{"vm-1"}))
+	g.Expect(result.HasCondition(VMNotSchedulable)).To(gomega.BeFalse())
+}
+
+//
+// Condition items.
+func items(conditions libcnd.Conditions, cndType string) []string {
+	cnd := conditions.FindCondition(cndType)
+	if cnd == nil {
+		return nil
+	}
+
+	return cnd.Items
+}
+
+//
+// Build the plan.
+func buildPlan(inventory *Inventory, storageMap map[string]string) *api.Plan {
+	p := &api.Plan{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "ns",
+			Name:      "plan",
+		},
+	}
+	for _, vm := range inventory.vms {
+		p.Spec.VMs = append(p.Spec.VMs, plan.VM{Ref: ref.Ref{ID: vm.ID}})
+	}
+	for id, class := range storageMap {
+		p.Spec.Map.Datastores = append(
+			p.Spec.Map.Datastores,
+			mapped.StoragePair{
+				Source: ref.Ref{ID: id},
+				Destination: mapped.DestinationStorage{
+					StorageClass: class,
+				},
+			})
+	}
+
+	return p
+}
+
+//
+// Build a VM.
+func vm(id string, cpu, memoryMB int32, disks ...model.Disk) *vsphere.VM {
+	m := &vsphere.VM{
+		CpuCount: cpu,
+		MemoryMB: memoryMB,
+		Disks:    disks,
+	}
+	m.ID = id
+	m.Name = id
+
+	return m
+}
+
+//
+// Build a disk.
+func disk(ds string, capacity int64) model.Disk {
+	return model.Disk{
+		Datastore: model.Ref{ID: ds},
+		Capacity:  capacity,
+	}
+}
+
+//
+// Build a node.
+func node(name, cpu, memory string) *core.Node {
+	return &core.Node{
+		ObjectMeta: meta.ObjectMeta{Name: name},
+		Status: core.NodeStatus{
+			Allocatable: core.ResourceList{
+				core.ResourceCPU:    resource.MustParse(cpu),
+				core.ResourceMemory: resource.MustParse(memory),
+			},
+		},
+	}
+}
+
+//
+// Mark the node ready.
+func ready(node *core.Node) *core.Node {
+	node.Status.Conditions = append(
+		node.Status.Conditions,
+		core.NodeCondition{
+			Type:   core.NodeReady,
+			Status: core.ConditionTrue,
+		})
+
+	return node
+}
+
+//
+// Build a resource quota.
+func quota(namespace string, hard, used core.ResourceList) *core.ResourceQuota {
+	return &core.ResourceQuota{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: namespace,
+			Name:      "quota",
+		},
+		Status: core.ResourceQuotaStatus{
+			Hard: hard,
+			Used: used,
+		},
+	}
+}
+
+//
+// Build a CSI storage capacity (segment).
+func segment(name, class, capacity string) *storage.CSIStorageCapacity {
+	n := resource.MustParse(capacity)
+	return &storage.CSIStorageCapacity{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "kube-system",
+			Name:      name,
+		},
+		StorageClassName: class,
+		Capacity:         &n,
+	}
+}
+
+//
+// Build the (fake) client.
+func fakeClient(g *gomega.GomegaWithT, objects ...runtime.Object) client.Client {
+	s := runtime.NewScheme()
+	g.Expect(scheme.AddToScheme(s)).NotTo(gomega.HaveOccurred())
+	return fake.NewFakeClientWithScheme(s, objects...)
+}
+
+//
+// Client that forbids listing the specified kinds.
+type Forbidden struct {
+	client.Client
+	kinds map[string]bool
+}
+
+//
+// List resources.
+func (r *Forbidden) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	kinds, _, _ := scheme.Scheme.ObjectKinds(list)
+	for _, kind := range kinds {
+		if r.kinds[kind.Kind] {
+			return k8serr.NewForbidden(schema.GroupResource{Resource: kind.Kind}, "", nil)
+		}
+	}
+
+	return r.Client.List(ctx, list, opts...)
+}
+
+//
+// Source (vSphere) inventory.
+// Only VMs and datastores are supported.
+type Inventory struct {
+	web.Client
+	vms []*vsphere.VM
+}
+
+//
+// Find a VM.
+func (r *Inventory) VM(vmRef *ref.Ref) (object interface{}, err error) {
+	for _, vm := range r.vms {
+		if vm.ID == vmRef.ID {
+			object = vm
+			return
+		}
+	}
+	err = web.NotFoundError{}
+
+	return
+}
+
+//
+// Find a datastore.
+func (r *Inventory) Storage(dsRef *ref.Ref) (object interface{}, err error) {
+	ds := &vsphere.Datastore{}
+	ds.ID = dsRef.ID
+	object = ds
+
+	return
+}
@@ -0,0 +1,191 @@
+package context
+
+import (
+	"context"
+	liberr "github.com/konveyor/controller/pkg/error"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sync"
+	"time"
+)
+
+//
+// CRD names.
+const (
+	VmImportCRD   = "virtualmachineimports.v2v.kubevirt.io"
+	DataVolumeCRD = "datavolumes.cdi.kubevirt.io"
+)
+
+//
+// Path (within the VirtualMachineImport CRD schema) to the
+// storage mapping item properties.
+var storageMappingPath = []string{
+	"properties", "spec",
+	"properties", "source",
+	"properties", "vmware",
+	"properties", "mappings",
+	"properties", "storageMappings",
+	"items", "properties",
+}
+
+//
+// Detected capabilities are cached (per provider) for
+// at most CapabilitiesTTL so that operators installed (or
+// upgraded) on the destination are eventually detected.
+var CapabilitiesTTL = time.Minute * 10
+
+//
+// Capabilities cache.
+// Keyed by destination provider UID.
+var capabilities = struct {
+	sync.Mutex
+	entries map[types.UID]cachedCapabilities
+}{
+	entries: map[types.UID]cachedCapabilities{},
+}
+
+//
+// Cached capabilities.
+type cachedCapabilities struct {
+	Capabilities
+	// Provider generation.
+	generation int64
+	// Detected timestamp.
+	detected time.Time
+}
+
+//
+// Destination cluster capabilities.
+// Detected by inspecting the installed CRDs which requires
+// the controller service account be granted `get` on the
+// `customresourcedefinitions` (apiextensions.k8s.io) resource
+// on the destination cluster.
+type Capabilities struct {
+	// VMIO installed.
+	VMIO bool
+	// CDI installed.
+	CDI bool
+	// The VMIO storage mapping supports `accessMode`.
+	AccessMode bool
+}
+
+//
+// Find cached capabilities for the destination provider.
+// Entries are matched by provider generation and expire
+// after CapabilitiesTTL.
+func (r *Capabilities) Cached(provider *api.Provider) (found bool) {
+	capabilities.Lock()
+	defer capabilities.Unlock()
+	now := time.Now()
+	for uid, entry := range capabilities.entries {
+		if now.Sub(entry.detected) > CapabilitiesTTL {
+			delete(capabilities.entries, uid)
+		}
+	}
+	entry, found := capabilities.entries[provider.UID]
+	if found && entry.generation == provider.Generation {
+		*r = entry.Capabilities
+	} else {
+		found = false
+	}
+
+	return
+}
+
+//
+// Detect capabilities of the destination provider cluster.
+// Cached results are used when found.
+func (r *Capabilities) Detect(provider *api.Provider, client k8sclient.Client) (err error) {
+	if r.Cached(provider) {
+		return
+	}
+	err = r.detect(client)
+	if err != nil {
+		return
+	}
+	capabilities.Lock()
+	defer capabilities.Unlock()
+	capabilities.entries[provider.UID] = cachedCapabilities{
+		Capabilities: *r,
+		generation:   provider.Generation,
+		detected:     time.Now(),
+	}
+
+	return
+}
+
+//
+// Detect capabilities by inspecting the CRDs.
+func (r *Capabilities) detect(client k8sclient.Client) (err error) {
+	vmImport, found, err := r.crd(client, VmImportCRD)
+	if err != nil {
+		return
+	}
+	if found {
+		r.VMIO = true
+		r.AccessMode = r.hasProperty(
+			vmImport,
+			append(storageMappingPath, "accessMode"))
+	}
+	_, found, err = r.crd(client, DataVolumeCRD)
+	if err != nil {
+		return
+	}
+	r.CDI = found
+
+	return
+}
+
+//
+// Get a CRD by name.
+func (r *Capabilities) crd(client k8sclient.Client, name string) (crd *unstructured.Unstructured, found bool, err error) {
+	crd = &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(
+		schema.GroupVersionKind{
+			Group:   "apiextensions.k8s.io",
+			Version: "v1",
+			Kind:    "CustomResourceDefinition",
+		})
+	err = client.Get(
+		context.TODO(),
+		k8sclient.ObjectKey{
+			Name: name,
+		},
+		crd)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+
+	found = true
+
+	return
+}
+
+//
+// Determine if the property is defined in the
+// schema of any of the CRD versions.
+func (r *Capabilities) hasProperty(crd *unstructured.Unstructured, path []string) bool {
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range versions {
+		version, cast := v.(map[string]interface{})
+		if !cast {
+			continue
+		}
+		fields := append([]string{"schema", "openAPIV3Schema"}, path...)
+		_, found, _ := unstructured.NestedFieldNoCopy(version, fields...)
+		if found {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,126 @@
+package context
+
+import (
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	"github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"testing"
+)
+
+func TestCapabilities(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Nothing installed.
+	capabilities := Capabilities{}
+	g.Expect(capabilities.detect(fakeClient())).NotTo(gomega.HaveOccurred())
+	g.Expect(capabilities).To(gomega.Equal(Capabilities{}))
+
+	// VMIO without access mode.
+	capabilities = Capabilities{}
+	g.Expect(capabilities.detect(fakeClient(crd(VmImportCRD, false)))).NotTo(gomega.HaveOccurred())
+	g.Expect(capabilities).To(gomega.Equal(Capabilities{VMIO: true}))
+
+	// VMIO with access mode; CDI.
+	capabilities = Capabilities{}
+	client := fakeClient(
+		crd(VmImportCRD, true),
+		crd(DataVolumeCRD, false))
+	g.Expect(capabilities.detect(client)).NotTo(gomega.HaveOccurred())
+	g.Expect(capabilities).To(
+		gomega.Equal(
+			Capabilities{
+				VMIO:       true,
+				CDI:        true,
+				AccessMode: true,
+			}))
+}
+
+func TestCapabilitiesCached(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	provider := &api.Provider{
+		ObjectMeta: meta.ObjectMeta{
+			UID:        "cached",
+			Generation: 1,
+		},
+	}
+
+	// Detected and cached.
+	capabilities := Capabilities{}
+	g.Expect(capabilities.Cached(provider)).To(gomega.BeFalse())
+	g.Expect(capabilities.Detect(provider, fakeClient(crd(VmImportCRD, true)))).NotTo(gomega.HaveOccurred())
+	capabilities = Capabilities{}
+	g.Expect(capabilities.Cached(provider)).To(gomega.BeTrue())
+	g.Expect(capabilities).To(gomega.Equal(Capabilities{VMIO: true, AccessMode: true}))
+
+	// Cached result used.
+	capabilities = Capabilities{}
+	g.Expect(capabilities.Detect(provider, fakeClient())).NotTo(gomega.HaveOccurred())
+	g.Expect(capabilities.VMIO).To(gomega.BeTrue())
+
+	// Generation changed.
+	provider.Generation++
+	capabilities = Capabilities{}
+	g.Expect(capabilities.Cached(provider)).To(gomega.BeFalse())
+	g.Expect(capabilities.Detect(provider, fakeClient())).NotTo(gomega.HaveOccurred())
+	g.Expect(capabilities).To(gomega.Equal(Capabilities{}))
+
+	// Expired.
+	ttl := CapabilitiesTTL
+	defer func() {
+		CapabilitiesTTL = ttl
+	}()
+	CapabilitiesTTL = 0
+	capabilities = Capabilities{}
+	g.Expect(capabilities.Cached(provider)).To(gomega.BeFalse())
+}
+
+//
+// Build a fake client.
+func fakeClient(objects ...runtime.Object) k8sclient.Client {
+	return fake.NewFakeClientWithScheme(runtime.NewScheme(), objects...)
+}
+
+//
+// Build a CRD.
+// The storage mapping `accessMode` property is defined as specified.
+func crd(name string, accessMode bool) *unstructured.Unstructured {
+	properties := map[string]interface{}{
+		"volumeMode": map[string]interface{}{
+			"type": "string",
+		},
+	}
+	if accessMode {
+		properties["accessMode"] = map[string]interface{}{
+			"type": "string",
+		}
+	}
+	definition := map[string]interface{}{}
+	path := append([]string{"openAPIV3Schema"}, storageMappingPath...)
+	_ = unstructured.SetNestedMap(definition, properties, path...)
+	object := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"versions": []interface{}{
+					map[string]interface{}{
+						"name":   "v1beta1",
+						"schema": definition,
+					},
+				},
+			},
+		},
+	}
+	object.SetGroupVersionKind(
+		schema.GroupVersionKind{
+			Group:   "apiextensions.k8s.io",
+			Version: "v1",
+			Kind:    "CustomResourceDefinition",
+		})
+	object.SetName(name)
+
+	return object
+}
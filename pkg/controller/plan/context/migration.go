@@ -110,6 +110,8 @@ type Destination struct {
 	Provider *api.Provider
 	// Provider API client.
 	Inventory web.Client
+	// Cluster capabilities.
+	Capabilities Capabilities
 }
 
 //
@@ -143,6 +145,11 @@ func (r *Destination) build(client k8sclient.Client, sn snapshot.Snapshot) (err
 	} else {
 		r.Client = client
 	}
+	err = r.Capabilities.Detect(r.Provider, r.Client)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
 	r.Inventory, err = web.NewClient(r.Provider)
 	if err != nil {
 		err = liberr.Wrap(err)
@@ -13,9 +13,12 @@ import (
 	core "k8s.io/api/core/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/retry"
 	cdi "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	vmio "kubevirt.io/vm-import-operator/pkg/apis/v2v/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -31,6 +34,10 @@ const (
 	kPlan = "plan"
 	// VM label (value=vmID)
 	kVM = "vmID"
+	// shared disk label (value=true)
+	kShared = "sharedDisk"
+	// placeholder label (value=true)
+	kPlaceholder = "placeholder"
 )
 
 //
@@ -64,7 +71,8 @@ func (r *KubeVirt) ImportMap() (mp ImportMap, err error) {
 //
 // List import CRs.
 // Each VmImport represents a VMIO VirtualMachineImport
-// with associated DataVolumes.
+// with associated DataVolumes. The shared disk DataVolumes
+// are associated with each import.
 func (r *KubeVirt) ListImports() ([]VmImport, error) {
 	selector := labels.SelectorFromSet(
 		map[string]string{
@@ -107,7 +115,7 @@ func (r *KubeVirt) ListImports() ([]VmImport, error) {
 		vmImport := &list[i]
 		for i := range dvList.Items {
 			dv := &dvList.Items[i]
-			if vmImport.Owner(dv) {
+			if vmImport.Owner(dv) || r.sharedDisk(dv) {
 				vmImport.DataVolumes = append(
 					vmImport.DataVolumes,
 					DataVolume{
@@ -122,20 +130,217 @@ func (r *KubeVirt) ListImports() ([]VmImport, error) {
 
 //
 // Create the VMIO CR on the destination.
+// The access modes are applied when supported by
+// the installed VMIO. The shared disk DataVolumes
+// are created first.
 func (r *KubeVirt) EnsureImport(vm *plan.VMStatus) (err error) {
-	newImport, err := r.buildImport(vm)
+	newImport, mp, err := r.buildImport(vm)
 	if err != nil {
 		return
 	}
-	err = r.ensureObject(newImport)
+	err = r.ensureSharedDisks(vm, mp, &newImport.Spec)
 	if err != nil {
 		err = liberr.Wrap(err)
 		return
 	}
+	var object runtime.Object = newImport
+	if r.Destination.Capabilities.AccessMode {
+		modes, mErr := r.Builder.AccessModes(vm.Ref, mp)
+		if mErr != nil {
+			err = liberr.Wrap(mErr)
+			return
+		}
+		object, err = r.withAccessModes(newImport, modes)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	err = r.ensureObject(object)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
+//
+// Update the KubeVirt VM created by the import.
+// The update is retried on conflict. The shared disk
+// placeholders are deleted.
+func (r *KubeVirt) EnsureVM(vm *plan.VMStatus, imp *VmImport) (err error) {
+	err = retry.RetryOnConflict(
+		retry.DefaultRetry,
+		func() error {
+			return r.updateVM(vm, imp)
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = r.deletePlaceholders(vm)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
+//
+// Get, build and update the KubeVirt VM.
+func (r *KubeVirt) updateVM(vm *plan.VMStatus, imp *VmImport) (err error) {
+	name := imp.Status.TargetVMName
+	if name == "" && imp.Spec.TargetVMName != nil {
+		name = *imp.Spec.TargetVMName
+	}
+	sn := snapshot.New(r.Migration)
+	mp := &plan.Map{}
+	err = sn.Get(api.MapSnapshot, mp)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	object := &unstructured.Unstructured{}
+	object.SetGroupVersionKind(
+		schema.GroupVersionKind{
+			Group:   "kubevirt.io",
+			Version: "v1alpha3",
+			Kind:    "VirtualMachine",
+		})
+	err = r.Destination.Client.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: r.namespace(),
+			Name:      name,
+		},
+		object)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if r.Plan.Spec.Template != nil {
+		template := VmTemplate{
+			Client: r.Destination.Client,
+			Ref:    r.Plan.Spec.Template,
+		}
+		err = template.Apply(object)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	dataVolumes := []cdi.DataVolume{}
+	for _, dv := range imp.DataVolumes {
+		dataVolumes = append(dataVolumes, *dv.DataVolume)
+	}
+	err = r.Builder.VirtualMachine(vm.Ref, mp, object, dataVolumes)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = r.Destination.Client.Update(context.TODO(), object)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
+//
+// Ensure the shared disk DataVolumes exist.
+// Each shared disk DataVolume is created once (per migration)
+// and not replaced. The placeholders are created so the VMIO
+// does not import the shared disks. See: Builder.SharedDisks().
+func (r *KubeVirt) ensureSharedDisks(vm *plan.VMStatus, mp *plan.Map, spec *vmio.VirtualMachineImportSpec) (err error) {
+	shared, placeholders, err := r.Builder.SharedDisks(vm.Ref, mp, spec)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range shared {
+		dv := &shared[i]
+		dv.Namespace = r.namespace()
+		dv.Labels = map[string]string{
+			kMigration: string(r.Plan.Status.Migration.Active),
+			kPlan:      string(r.Plan.UID),
+			kShared:    "true",
+		}
+		err = r.Destination.Client.Create(context.TODO(), dv)
+		if err != nil {
+			if k8serr.IsAlreadyExists(err) {
+				err = nil
+			} else {
+				err = liberr.Wrap(err)
+				return
+			}
+		}
+	}
+	for i := range placeholders {
+		dv := &placeholders[i]
+		dv.Namespace = r.namespace()
+		dv.Labels = map[string]string{
+			kMigration:   string(r.Plan.Status.Migration.Active),
+			kPlan:        string(r.Plan.UID),
+			kVM:          vm.ID,
+			kPlaceholder: "true",
+		}
+		err = r.ensureObject(dv)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
 
 	return
 }
 
+//
+// Delete the shared disk placeholders for the VM.
+// Called after the VM has been updated so that the
+// placeholders are no longer referenced by the VM.
+func (r *KubeVirt) deletePlaceholders(vm *plan.VMStatus) (err error) {
+	selector := labels.SelectorFromSet(
+		map[string]string{
+			kMigration:   string(r.Plan.Status.Migration.Active),
+			kPlan:        string(r.Plan.UID),
+			kVM:          vm.ID,
+			kPlaceholder: "true",
+		})
+	list := &cdi.DataVolumeList{}
+	err = r.Destination.Client.List(
+		context.TODO(),
+		list,
+		&client.ListOptions{
+			Namespace:     r.namespace(),
+			LabelSelector: selector,
+		},
+	)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range list.Items {
+		err = r.deleteObject(&list.Items[i])
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+
+	return
+}
+
+//
+// The DataVolume is a shared disk for the active migration.
+func (r *KubeVirt) sharedDisk(dv *cdi.DataVolume) bool {
+	return dv.Labels[kShared] == "true" &&
+		dv.Labels[kPlan] == string(r.Plan.UID) &&
+		dv.Labels[kMigration] == string(r.Plan.Status.Migration.Active)
+}
+
 //
 // Set VMIO secret owner references.
 func (r *KubeVirt) SetSecretOwner(vm *plan.VMStatus) (err error) {
@@ -219,10 +424,12 @@ func (r *KubeVirt) EnsureSecret(vmRef ref.Ref) (err error) {
 
 //
 // Build the VMIO CR.
-func (r *KubeVirt) buildImport(vm *plan.VMStatus) (object *vmio.VirtualMachineImport, err error) {
+// Returns the map with the volume and access modes
+// defaulted by the builder.
+func (r *KubeVirt) buildImport(vm *plan.VMStatus) (object *vmio.VirtualMachineImport, mp *plan.Map, err error) {
 	namespace := r.namespace()
 	sn := snapshot.New(r.Migration)
-	mp := &plan.Map{}
+	mp = &plan.Map{}
 	err = sn.Get(api.MapSnapshot, mp)
 	if err != nil {
 		err = liberr.Wrap(err)
@@ -261,6 +468,44 @@ func (r *KubeVirt) buildImport(vm *plan.VMStatus) (object *vmio.VirtualMachineIm
 	return
 }
 
+//
+// Build the (unstructured) VMIO CR with the access mode set
+// on each storage mapping. The access mode is not supported
+// by the VMIO (v0.2.5) API types. The storage mappings are
+// matched by (source) datastore ID.
+func (r *KubeVirt) withAccessModes(object *vmio.VirtualMachineImport, modes map[string]core.PersistentVolumeAccessMode) (u *unstructured.Unstructured, err error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	u = &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(vmio.SchemeGroupVersion.WithKind("VirtualMachineImport"))
+	fields := []string{"spec", "source", "vmware", "mappings", "storageMappings"}
+	list, found, err := unstructured.NestedSlice(u.Object, fields...)
+	if err != nil || !found {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, item := range list {
+		item, cast := item.(map[string]interface{})
+		if !cast {
+			continue
+		}
+		id, _, _ := unstructured.NestedString(item, "source", "id")
+		if mode, found := modes[id]; found {
+			item["accessMode"] = string(mode)
+		}
+	}
+	err = unstructured.SetNestedSlice(u.Object, list, fields...)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
 //
 // Build the VMIO secret.
 func (r *KubeVirt) buildSecret(vmRef ref.Ref) (object *core.Secret, err error) {
@@ -0,0 +1,69 @@
+package plan
+
+import (
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	vmio "kubevirt.io/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"testing"
+)
+
+func TestAccessModes(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	kubevirt := &KubeVirt{}
+	// Two datastores mapped to the same storage class.
+	ds1 := "ds-1"
+	ds2 := "ds-2"
+	ds3 := "ds-3"
+	object := &vmio.VirtualMachineImport{
+		Spec: vmio.VirtualMachineImportSpec{
+			Source: vmio.VirtualMachineImportSourceSpec{
+				Vmware: &vmio.VirtualMachineImportVmwareSourceSpec{
+					Mappings: &vmio.VmwareMappings{
+						StorageMappings: &[]vmio.StorageResourceMappingItem{
+							{
+								Source: vmio.Source{ID: &ds1},
+								Target: vmio.ObjectIdentifier{Name: "gold"},
+							},
+							{
+								Source: vmio.Source{ID: &ds2},
+								Target: vmio.ObjectIdentifier{Name: "gold"},
+							},
+							{
+								Source: vmio.Source{ID: &ds3},
+								Target: vmio.ObjectIdentifier{Name: "gold"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	u, err := kubevirt.withAccessModes(
+		object,
+		map[string]core.PersistentVolumeAccessMode{
+			ds1: core.ReadWriteOnce,
+			ds2: core.ReadWriteMany,
+		})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(u.GetKind()).To(gomega.Equal("VirtualMachineImport"))
+	list, _, _ := unstructured.NestedSlice(
+		u.Object,
+		"spec", "source", "vmware", "mappings", "storageMappings")
+	g.Expect(list).To(gomega.HaveLen(3))
+	modes := map[string]string{}
+	for _, item := range list {
+		item := item.(map[string]interface{})
+		id, _, _ := unstructured.NestedString(item, "source", "id")
+		mode, found, _ := unstructured.NestedString(item, "accessMode")
+		if found {
+			modes[id] = mode
+		}
+	}
+	g.Expect(modes).To(
+		gomega.Equal(
+			map[string]string{
+				ds1: string(core.ReadWriteOnce),
+				ds2: string(core.ReadWriteMany),
+			}))
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/controller/plan/builder"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"net"
 	"time"
 )
 
@@ -428,6 +430,17 @@ func (r *Migration) updateVM(vm *plan.VMStatus) (completed bool, failed bool, er
 		if cnd.Status != True {
 			vm.AddError(cnd.Message)
 			failed = true
+			return
+		}
+		vErr := r.kubevirt.EnsureVM(vm, &imp)
+		if vErr != nil {
+			if transient(vErr) {
+				log.Trace(vErr)
+				completed = false
+				return
+			}
+			vm.AddError(vErr.Error())
+			failed = true
 		}
 	}
 
@@ -526,3 +539,21 @@ func (r *Predicate) Evaluate(flag libitr.Flag) (allowed bool, err error) {
 
 	return
 }
+
+//
+// The error is transient.
+// The operation is retried on the next reconcile.
+func transient(err error) bool {
+	switch {
+	case errors.As(err, &web.ProviderNotReadyError{}),
+		k8serr.IsConflict(err),
+		k8serr.IsInternalError(err),
+		k8serr.IsServerTimeout(err),
+		k8serr.IsServiceUnavailable(err),
+		k8serr.IsTimeout(err),
+		k8serr.IsTooManyRequests(err):
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,237 @@
+package plan
+
+import (
+	"context"
+	"encoding/json"
+	liberr "github.com/konveyor/controller/pkg/error"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1/plan"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+	"strings"
+)
+
+//
+// Target VM template.
+// Provides the VirtualMachine skeleton merged with
+// each VM created by the import.
+type VmTemplate struct {
+	// Destination client.
+	client.Client
+	// Template reference.
+	Ref *plan.Template
+}
+
+//
+// Load the VirtualMachine skeleton.
+func (r *VmTemplate) Load() (skeleton map[string]interface{}, err error) {
+	switch r.Ref.Kind {
+	case plan.ConfigMapKind:
+		skeleton, err = r.configMap()
+	case plan.TemplateKind:
+		skeleton, err = r.template()
+	default:
+		err = liberr.New("VM template: kind not supported.")
+	}
+
+	return
+}
+
+//
+// Apply the template.
+// The skeleton `spec`, labels and annotations are merged
+// with the VM. Values defined by the template take precedence.
+// The firmware, disk bus and run strategy are (subsequently)
+// set by the builder. See: Builder.VirtualMachine().
+func (r *VmTemplate) Apply(object *unstructured.Unstructured) (err error) {
+	skeleton, err := r.Load()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if spec, found := skeleton["spec"]; found {
+		object.Object["spec"] = merge(object.Object["spec"], spec)
+	}
+	for _, key := range []string{"labels", "annotations"} {
+		in, found, _ := unstructured.NestedFieldNoCopy(skeleton, "metadata", key)
+		if !found {
+			continue
+		}
+		current, _, _ := unstructured.NestedFieldNoCopy(object.Object, "metadata", key)
+		err = unstructured.SetNestedField(
+			object.Object,
+			merge(current, in),
+			"metadata",
+			key)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+
+	return
+}
+
+//
+// Load the skeleton from a ConfigMap.
+func (r *VmTemplate) configMap() (skeleton map[string]interface{}, err error) {
+	cm := &core.ConfigMap{}
+	err = r.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: r.Ref.Namespace,
+			Name:      r.Ref.Name,
+		},
+		cm)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	content, found := cm.Data[plan.TemplateKey]
+	if !found {
+		err = liberr.New("VM template: key `" + plan.TemplateKey + "` not found.")
+		return
+	}
+	skeleton = map[string]interface{}{}
+	err = yaml.Unmarshal([]byte(content), &skeleton)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
+//
+// Load the skeleton from an (OpenShift) Template.
+// Parameters are substituted using the parameter value.
+func (r *VmTemplate) template() (skeleton map[string]interface{}, err error) {
+	template := &unstructured.Unstructured{}
+	template.SetGroupVersionKind(
+		schema.GroupVersionKind{
+			Group:   "template.openshift.io",
+			Version: "v1",
+			Kind:    "Template",
+		})
+	err = r.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: r.Ref.Namespace,
+			Name:      r.Ref.Name,
+		},
+		template)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	objects, _, _ := unstructured.NestedSlice(template.Object, "objects")
+	for _, object := range objects {
+		object, cast := object.(map[string]interface{})
+		if !cast {
+			continue
+		}
+		if object["kind"] == "VirtualMachine" {
+			skeleton = object
+			break
+		}
+	}
+	if skeleton == nil {
+		err = liberr.New("VM template: VirtualMachine not found.")
+		return
+	}
+	content, err := json.Marshal(skeleton)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	s := string(content)
+	parameters, _, _ := unstructured.NestedSlice(template.Object, "parameters")
+	for _, p := range parameters {
+		p, cast := p.(map[string]interface{})
+		if !cast {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(p, "name")
+		value, _, _ := unstructured.NestedString(p, "value")
+		if name != "" {
+			s = strings.ReplaceAll(s, "${"+name+"}", value)
+		}
+	}
+	skeleton = map[string]interface{}{}
+	err = json.Unmarshal([]byte(s), &skeleton)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
+//
+// Merge `in` into `base`.
+// Values in `in` take precedence. Maps are merged recursively.
+// Lists of named objects (disks, interfaces, networks, volumes)
+// are merged by name.
+func merge(base, in interface{}) interface{} {
+	switch in := in.(type) {
+	case map[string]interface{}:
+		bMap, cast := base.(map[string]interface{})
+		if !cast {
+			return in
+		}
+		out := map[string]interface{}{}
+		for k, v := range bMap {
+			out[k] = v
+		}
+		for k, v := range in {
+			if b, found := out[k]; found {
+				out[k] = merge(b, v)
+			} else {
+				out[k] = v
+			}
+		}
+		return out
+	case []interface{}:
+		bList, cast := base.([]interface{})
+		if !cast || !named(bList) || !named(in) {
+			return in
+		}
+		out := []interface{}{}
+		index := map[interface{}]int{}
+		for _, v := range bList {
+			index[v.(map[string]interface{})["name"]] = len(out)
+			out = append(out, v)
+		}
+		for _, v := range in {
+			name := v.(map[string]interface{})["name"]
+			if i, found := index[name]; found {
+				out[i] = merge(out[i], v)
+			} else {
+				out = append(out, v)
+			}
+		}
+		return out
+	case nil:
+		return base
+	default:
+		return in
+	}
+}
+
+//
+// All list items are objects with a name.
+func named(list []interface{}) bool {
+	for _, v := range list {
+		m, cast := v.(map[string]interface{})
+		if !cast {
+			return false
+		}
+		if _, cast := m["name"].(string); !cast {
+			return false
+		}
+	}
+
+	return true
+}
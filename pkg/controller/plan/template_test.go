@@ -0,0 +1,301 @@
+package plan
+
+import (
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1/plan"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	disk := func(name, bus string) map[string]interface{} {
+		return map[string]interface{}{
+			"name": name,
+			"disk": map[string]interface{}{
+				"bus": bus,
+			},
+		}
+	}
+	cases := []struct {
+		name     string
+		base     interface{}
+		in       interface{}
+		expected interface{}
+	}{
+		{
+			name:     "value",
+			base:     "a",
+			in:       "b",
+			expected: "b",
+		},
+		{
+			name:     "nil",
+			base:     "a",
+			in:       nil,
+			expected: "a",
+		},
+		{
+			name: "map",
+			base: map[string]interface{}{
+				"a": "1",
+				"b": map[string]interface{}{
+					"c": "2",
+					"d": "3",
+				},
+			},
+			in: map[string]interface{}{
+				"b": map[string]interface{}{
+					"d": "4",
+				},
+				"e": "5",
+			},
+			expected: map[string]interface{}{
+				"a": "1",
+				"b": map[string]interface{}{
+					"c": "2",
+					"d": "4",
+				},
+				"e": "5",
+			},
+		},
+		{
+			name: "map replaces value",
+			base: "a",
+			in: map[string]interface{}{
+				"b": "1",
+			},
+			expected: map[string]interface{}{
+				"b": "1",
+			},
+		},
+		{
+			name: "named list",
+			base: []interface{}{
+				disk("disk0", "virtio"),
+				disk("disk1", "virtio"),
+			},
+			in: []interface{}{
+				disk("disk1", "sata"),
+				disk("disk2", "scsi"),
+			},
+			expected: []interface{}{
+				disk("disk0", "virtio"),
+				disk("disk1", "sata"),
+				disk("disk2", "scsi"),
+			},
+		},
+		{
+			name:     "list",
+			base:     []interface{}{"a", "b"},
+			in:       []interface{}{"c"},
+			expected: []interface{}{"c"},
+		},
+	}
+	for _, c := range cases {
+		g.Expect(merge(c.base, c.in)).To(gomega.Equal(c.expected), c.name)
+	}
+}
+
+func TestNamed(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	cases := []struct {
+		name     string
+		list     []interface{}
+		expected bool
+	}{
+		{
+			name:     "empty",
+			list:     []interface{}{},
+			expected: true,
+		},
+		{
+			name: "named",
+			list: []interface{}{
+				map[string]interface{}{"name": "a"},
+				map[string]interface{}{"name": "b"},
+			},
+			expected: true,
+		},
+		{
+			name: "not named",
+			list: []interface{}{
+				map[string]interface{}{"name": "a"},
+				map[string]interface{}{"key": "b"},
+			},
+		},
+		{
+			name: "name not string",
+			list: []interface{}{
+				map[string]interface{}{"name": 1},
+			},
+		},
+		{
+			name: "not objects",
+			list: []interface{}{"a"},
+		},
+	}
+	for _, c := range cases {
+		g.Expect(named(c.list)).To(gomega.Equal(c.expected), c.name)
+	}
+}
+
+func TestTemplateApply(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	skeleton := `
+metadata:
+  labels:
+    app: ${APP}
+    tier: gold
+spec:
+  template:
+    spec:
+      evictionStrategy: LiveMigrate
+      domain:
+        cpu:
+          model: host-passthrough
+`
+	template := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"objects": []interface{}{
+				map[string]interface{}{
+					"kind": "Service",
+				},
+				map[string]interface{}{
+					"kind": "VirtualMachine",
+					"metadata": map[string]interface{}{
+						"labels": map[string]interface{}{
+							"app":  "${APP}",
+							"tier": "gold",
+						},
+					},
+					"spec": map[string]interface{}{
+						"template": map[string]interface{}{
+							"spec": map[string]interface{}{
+								"evictionStrategy": "LiveMigrate",
+								"domain": map[string]interface{}{
+									"cpu": map[string]interface{}{
+										"model": "host-passthrough",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"parameters": []interface{}{
+				map[string]interface{}{
+					"name":  "APP",
+					"value": "web",
+				},
+			},
+		},
+	}
+	template.SetGroupVersionKind(
+		schema.GroupVersionKind{
+			Group:   "template.openshift.io",
+			Version: "v1",
+			Kind:    "Template",
+		})
+	template.SetNamespace("test")
+	template.SetName("template")
+	cm := &core.ConfigMap{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "test",
+			Name:      "skeleton",
+		},
+		Data: map[string]string{
+			plan.TemplateKey: skeleton,
+		},
+	}
+	client := fake.NewFakeClientWithScheme(scheme.Scheme, template, cm)
+	cases := []struct {
+		ref *plan.Template
+		app string
+	}{
+		{
+			ref: &plan.Template{
+				Kind:      plan.TemplateKind,
+				Namespace: "test",
+				Name:      "template",
+			},
+			app: "web",
+		},
+		{
+			ref: &plan.Template{
+				Kind:      plan.ConfigMapKind,
+				Namespace: "test",
+				Name:      "skeleton",
+			},
+			app: "${APP}",
+		},
+	}
+	for _, c := range cases {
+		vm := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"running": false,
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"evictionStrategy": "None",
+							"domain": map[string]interface{}{
+								"cpu": map[string]interface{}{
+									"cores": int64(2),
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		vm.SetLabels(
+			map[string]string{
+				"app":  "imported",
+				"name": "vm",
+			})
+		r := VmTemplate{Client: client, Ref: c.ref}
+		g.Expect(r.Apply(vm)).NotTo(gomega.HaveOccurred(), c.ref.Kind)
+		// Template values take precedence.
+		g.Expect(vm.GetLabels()).To(
+			gomega.Equal(
+				map[string]string{
+					"app":  c.app,
+					"name": "vm",
+					"tier": "gold",
+				}),
+			c.ref.Kind)
+		spec := []string{"spec", "template", "spec"}
+		eviction, _, _ := unstructured.NestedString(vm.Object, append(spec, "evictionStrategy")...)
+		g.Expect(eviction).To(gomega.Equal("LiveMigrate"), c.ref.Kind)
+		model, _, _ := unstructured.NestedString(vm.Object, append(spec, "domain", "cpu", "model")...)
+		g.Expect(model).To(gomega.Equal("host-passthrough"), c.ref.Kind)
+		// VM values not defined by the template are preserved.
+		cores, _, _ := unstructured.NestedInt64(vm.Object, append(spec, "domain", "cpu", "cores")...)
+		g.Expect(cores).To(gomega.Equal(int64(2)), c.ref.Kind)
+		running, found, _ := unstructured.NestedBool(vm.Object, "spec", "running")
+		g.Expect(found).To(gomega.BeTrue(), c.ref.Kind)
+		g.Expect(running).To(gomega.BeFalse(), c.ref.Kind)
+	}
+
+	// Not found.
+	r := VmTemplate{
+		Client: client,
+		Ref: &plan.Template{
+			Kind:      plan.ConfigMapKind,
+			Namespace: "test",
+			Name:      "missing",
+		},
+	}
+	_, err := r.Load()
+	g.Expect(err).To(gomega.HaveOccurred())
+
+	// Kind not supported.
+	r.Ref.Kind = "Secret"
+	_, err = r.Load()
+	g.Expect(err).To(gomega.HaveOccurred())
+}
@@ -6,20 +6,35 @@ import (
 	liberr "github.com/konveyor/controller/pkg/error"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
 	"github.com/konveyor/forklift-controller/pkg/controller/validation"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 )
 
 //
 // Types
 const (
-	VMRefNotValid = "VMRefNotValid"
-	VMNotFound    = "VMNotFound"
-	DuplicateVM   = "DuplicateVM"
-	NameNotValid  = "TargetNameNotValid"
-	Executing     = "Executing"
-	Succeeded     = "Succeeded"
-	Failed        = "Failed"
+	VMRefNotValid         = "VMRefNotValid"
+	VMNotFound            = "VMNotFound"
+	DuplicateVM           = "DuplicateVM"
+	NameNotValid          = "TargetNameNotValid"
+	VMEncrypted           = "VMEncrypted"
+	VMTpm                 = "VMTpmNotSupported"
+	VMRdm                 = "VMRdmNotSupported"
+	VMSharedDisk          = "VMSharedDisk"
+	VMSharedDiskNotInPlan = "VMSharedDiskNotInPlan"
+	TemplateNotValid      = "TemplateNotValid"
+	// Destination capacity.
+	InsufficientStorage  = "InsufficientStorage"
+	StorageQuotaExceeded = "StorageQuotaExceeded"
+	ComputeQuotaExceeded = "ComputeQuotaExceeded"
+	InsufficientCompute  = "InsufficientCompute"
+	VMNotSchedulable     = "VMNotSchedulable"
+	CapacityNotValidated = "CapacityNotValidated"
+	Executing            = "Executing"
+	Succeeded            = "Succeeded"
+	Failed               = "Failed"
 )
 
 //
@@ -35,11 +50,13 @@ const (
 //
 // Reasons
 const (
-	NotSet    = "NotSet"
-	NotFound  = "NotFound"
-	NotUnique = "NotUnique"
-	Ambiguous = "Ambiguous"
-	NotValid  = "NotValid"
+	NotSet       = "NotSet"
+	NotFound     = "NotFound"
+	NotUnique    = "NotUnique"
+	Ambiguous    = "Ambiguous"
+	NotValid     = "NotValid"
+	NotSupported = "NotSupported"
+	NotValidated = "NotValidated"
 )
 
 //
@@ -62,7 +79,9 @@ func (r *Reconciler) validate(plan *api.Plan) error {
 		return liberr.Wrap(err)
 	}
 	plan.Status.SetCondition(conditions.List...)
-	if plan.Status.HasCondition(validation.SourceProviderNotReady) {
+	if plan.Status.HasAnyCondition(
+		validation.SourceProviderNotValid,
+		validation.SourceProviderNotReady) {
 		return nil
 	}
 	plan.Referenced.Provider.Source = provider.Referenced.Source
@@ -75,7 +94,11 @@ func (r *Reconciler) validate(plan *api.Plan) error {
 		return liberr.Wrap(err)
 	}
 	plan.Status.UpdateConditions(conditions)
-	storage := validation.StoragePair{Client: r, Provider: provider.Referenced}
+	storage := validation.StoragePair{
+		Client:      r,
+		Provider:    provider.Referenced,
+		SharedDisks: plan.Spec.Map.SharedDisks,
+	}
 	conditions, err = storage.Validate(plan.Spec.Map.Datastores)
 	if err != nil {
 		return liberr.Wrap(err)
@@ -87,6 +110,18 @@ func (r *Reconciler) validate(plan *api.Plan) error {
 	if err != nil {
 		return liberr.Wrap(err)
 	}
+	//
+	// Template.
+	err = r.validateTemplate(plan)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	//
+	// Destination capacity.
+	err = r.validateCapacity(plan)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
 
 	return nil
 }
@@ -134,7 +169,49 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 		Message:  "Target VM name not valid.",
 		Items:    []string{},
 	}
+	encrypted := libcnd.Condition{
+		Type:     VMEncrypted,
+		Status:   True,
+		Reason:   NotSupported,
+		Category: Critical,
+		Message:  "Encrypted VM not supported.",
+		Items:    []string{},
+	}
+	tpm := libcnd.Condition{
+		Type:     VMTpm,
+		Status:   True,
+		Reason:   NotSupported,
+		Category: Warn,
+		Message:  "VM vTPM not supported; guests depending on it may not boot.",
+		Items:    []string{},
+	}
+	rdm := libcnd.Condition{
+		Type:     VMRdm,
+		Status:   True,
+		Reason:   NotSupported,
+		Category: Critical,
+		Message:  "VM RDM disks not supported.",
+		Items:    []string{},
+	}
+	shared := libcnd.Condition{
+		Type:     VMSharedDisk,
+		Status:   True,
+		Reason:   NotSupported,
+		Category: Critical,
+		Message:  "VM shared (multi-writer) disks require the shared disk mapping.",
+		Items:    []string{},
+	}
+	notInPlan := libcnd.Condition{
+		Type:     VMSharedDiskNotInPlan,
+		Status:   True,
+		Reason:   NotValid,
+		Category: Warn,
+		Message:  "VM shared (multi-writer) disks are also used by VMs not in the plan.",
+		Items:    []string{},
+	}
 	setOf := map[string]bool{}
+	// Shared disk (backing file) used by listed VMs.
+	sharedBy := map[string][]string{}
 	//
 	// Referenced VMs.
 	for _, vm := range plan.Spec.VMs {
@@ -149,7 +226,7 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 			})
 			continue
 		}
-		_, pErr := inventory.VM(&ref)
+		object, pErr := inventory.VM(&ref)
 		if pErr != nil {
 			if errors.As(pErr, &web.NotFoundError{}) {
 				notFound.Items = append(notFound.Items, ref.String())
@@ -164,12 +241,45 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 		if len(k8svalidation.IsQualifiedName(ref.Name)) > 0 {
 			nameNotValid.Items = append(nameNotValid.Items, ref.String())
 		}
+		if vm, cast := object.(*vsphere.VM); cast {
+			if vm.Encrypted {
+				encrypted.Items = append(encrypted.Items, ref.String())
+			}
+			if vm.TpmEnabled {
+				tpm.Items = append(tpm.Items, ref.String())
+			}
+			for _, disk := range vm.Disks {
+				if disk.RDM {
+					rdm.Items = append(rdm.Items, ref.String())
+					break
+				}
+			}
+			if plan.Spec.Map.SharedDisks == nil {
+				for _, disk := range vm.Disks {
+					if disk.Shared {
+						shared.Items = append(shared.Items, ref.String())
+						break
+					}
+				}
+			}
+			for _, disk := range vm.Disks {
+				if disk.Shared && !disk.RDM {
+					sharedBy[disk.File] = append(sharedBy[disk.File], ref.String())
+				}
+			}
+		}
 		if _, found := setOf[ref.ID]; found {
 			notUnique.Items = append(notUnique.Items, ref.String())
 		} else {
 			setOf[ref.ID] = true
 		}
 	}
+	if len(sharedBy) > 0 {
+		notInPlan.Items, pErr = r.sharedNotInPlan(inventory, setOf, sharedBy)
+		if pErr != nil {
+			return liberr.Wrap(pErr)
+		}
+	}
 	if len(notFound.Items) > 0 {
 		plan.Status.SetCondition(notFound)
 	}
@@ -182,6 +292,134 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 	if len(ambiguous.Items) > 0 {
 		plan.Status.SetCondition(ambiguous)
 	}
+	if len(encrypted.Items) > 0 {
+		plan.Status.SetCondition(encrypted)
+	}
+	if len(tpm.Items) > 0 {
+		plan.Status.SetCondition(tpm)
+	}
+	if len(rdm.Items) > 0 {
+		plan.Status.SetCondition(rdm)
+	}
+	if len(shared.Items) > 0 {
+		plan.Status.SetCondition(shared)
+	}
+	if len(notInPlan.Items) > 0 {
+		plan.Status.SetCondition(notInPlan)
+	}
+
+	return nil
+}
+
+//
+// Find the listed VMs with shared disks that are also
+// used by VMs not listed in the plan. The shared disks
+// are matched by backing file.
+func (r *Reconciler) sharedNotInPlan(inventory web.Client, listed map[string]bool, sharedBy map[string][]string) (items []string, err error) {
+	list := []vsphere.VM{}
+	err = inventory.List(
+		&list,
+		web.Param{
+			Key:   vsphere.DetailParam,
+			Value: "1",
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	setOf := map[string]bool{}
+	for _, vm := range list {
+		if listed[vm.ID] {
+			continue
+		}
+		for _, disk := range vm.Disks {
+			if !disk.Shared {
+				continue
+			}
+			for _, ref := range sharedBy[disk.File] {
+				if !setOf[ref] {
+					setOf[ref] = true
+					items = append(items, ref)
+				}
+			}
+		}
+	}
+
+	return
+}
+
+//
+// Validate the (target) VM template.
+func (r *Reconciler) validateTemplate(plan *api.Plan) error {
+	ref := plan.Spec.Template
+	provider := plan.Referenced.Provider.Destination
+	if ref == nil || provider == nil {
+		return nil
+	}
+	destination, err := validation.ProviderClient(r, provider)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	template := VmTemplate{
+		Client: destination,
+		Ref:    ref,
+	}
+	_, err = template.Load()
+	if err != nil {
+		newCnd := libcnd.Condition{
+			Type:     TemplateNotValid,
+			Status:   True,
+			Reason:   NotValid,
+			Category: Critical,
+			Message:  "VM template not valid: " + err.Error(),
+		}
+		if k8serr.IsNotFound(err) {
+			newCnd.Reason = NotFound
+			newCnd.Message = "VM template not found."
+		}
+		plan.Status.SetCondition(newCnd)
+	}
+
+	return nil
+}
+
+//
+// Validate the destination has the capacity
+// (storage, CPU, memory) required by the listed VMs.
+func (r *Reconciler) validateCapacity(plan *api.Plan) error {
+	source := plan.Referenced.Provider.Source
+	destination := plan.Referenced.Provider.Destination
+	if source == nil || destination == nil {
+		return nil
+	}
+	inventory, err := web.NewClient(source)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	destClient, err := validation.ProviderClient(r, destination)
+	if err != nil {
+		log.Trace(err)
+		plan.Status.SetCondition(
+			libcnd.Condition{
+				Type:     CapacityNotValidated,
+				Status:   True,
+				Reason:   NotValidated,
+				Category: Advisory,
+				Message:  "Destination capacity could not be determined.",
+				Items:    []string{"destination client"},
+			})
+		return nil
+	}
+	preflight := Preflight{
+		Client:    destClient,
+		Inventory: inventory,
+		Plan:      plan,
+	}
+	conditions, err := preflight.Validate()
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	plan.Status.UpdateConditions(conditions)
 
 	return nil
 }
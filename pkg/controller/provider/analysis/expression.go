@@ -0,0 +1,808 @@
+package analysis
+
+import (
+	"fmt"
+	liberr "github.com/konveyor/controller/pkg/error"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//
+// Token kinds.
+const (
+	tEnd = iota
+	tIdent
+	tNumber
+	tString
+	tOperator
+)
+
+//
+// Expression token.
+type token struct {
+	kind  int
+	value string
+}
+
+//
+// Split the expression into tokens.
+func tokenize(in string) (tokens []token, err error) {
+	operators := []string{
+		"||", "&&", "==", "!=", "<=", ">=", "=~",
+		"<", ">", "!", "(", ")", "[", "]", ",",
+	}
+	runes := []rune(in)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) &&
+				(unicode.IsLetter(runes[i]) ||
+					unicode.IsDigit(runes[i]) ||
+					runes[i] == '_' ||
+					runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tIdent, value: string(runes[start:i])})
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tNumber, value: string(runes[start:i])})
+		case r == '"' || r == '\'':
+			quote := r
+			i++
+			s := []rune{}
+			for ; i < len(runes) && runes[i] != quote; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				s = append(s, runes[i])
+			}
+			if i == len(runes) {
+				err = liberr.New("unterminated string.")
+				return
+			}
+			i++
+			tokens = append(tokens, token{kind: tString, value: string(s)})
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					tokens = append(tokens, token{kind: tOperator, value: op})
+					i += len([]rune(op))
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				err = liberr.New(fmt.Sprintf("unexpected character: '%c'.", r))
+				return
+			}
+		}
+	}
+
+	tokens = append(tokens, token{kind: tEnd})
+
+	return
+}
+
+//
+// Evaluation environment.
+// Maps a (root) name to a value.
+type Env map[string]interface{}
+
+//
+// Compiled expression node.
+type node interface {
+	eval(env Env) (interface{}, error)
+}
+
+//
+// Literal value.
+type literal struct {
+	value interface{}
+}
+
+func (n *literal) eval(env Env) (interface{}, error) {
+	return n.value, nil
+}
+
+//
+// Dotted path (vm.cpuCount) resolved in the env.
+type path struct {
+	parts []string
+}
+
+func (n *path) eval(env Env) (interface{}, error) {
+	var v interface{} = map[string]interface{}(env)
+	for _, part := range n.parts {
+		v = field(v, part)
+		if v == nil {
+			break
+		}
+	}
+
+	return v, nil
+}
+
+//
+// List ([a, b]).
+type list struct {
+	items []node
+}
+
+func (n *list) eval(env Env) (interface{}, error) {
+	v := []interface{}{}
+	for _, item := range n.items {
+		iv, err := item.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		v = append(v, iv)
+	}
+
+	return v, nil
+}
+
+//
+// Unary `!`.
+type not struct {
+	operand node
+}
+
+func (n *not) eval(env Env) (interface{}, error) {
+	v, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	return !truthy(v), nil
+}
+
+//
+// Binary operator.
+type binary struct {
+	op    string
+	left  node
+	right node
+}
+
+func (n *binary) eval(env Env) (v interface{}, err error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return
+	}
+	// Short circuit.
+	switch n.op {
+	case "&&":
+		if !truthy(left) {
+			v = false
+			return
+		}
+	case "||":
+		if truthy(left) {
+			v = true
+			return
+		}
+	}
+	right, err := n.right.eval(env)
+	if err != nil {
+		return
+	}
+	switch n.op {
+	case "&&", "||":
+		v = truthy(right)
+	case "==":
+		v = equals(left, right)
+	case "!=":
+		v = !equals(left, right)
+	case "<", "<=", ">", ">=":
+		v, err = compare(n.op, left, right)
+	case "=~":
+		var re *regexp.Regexp
+		re, err = regexp.Compile(fmt.Sprint(right))
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		v = left != nil && re.MatchString(fmt.Sprint(left))
+	case "in":
+		v, err = member(left, right)
+	}
+
+	return
+}
+
+//
+// Function call.
+type call struct {
+	name string
+	args []node
+}
+
+func (n *call) eval(env Env) (v interface{}, err error) {
+	args := []interface{}{}
+	for _, a := range n.args {
+		var arg interface{}
+		arg, err = a.eval(env)
+		if err != nil {
+			return
+		}
+		args = append(args, arg)
+	}
+	fn := functions[strings.ToLower(n.name)]
+	v, err = fn(args)
+	return
+}
+
+//
+// Built-in function.
+type function func(args []interface{}) (interface{}, error)
+
+//
+// Built-in functions.
+//   len(x) - length of a list, map or string.
+//   any(list, field) - any item has a truthy field.
+//   all(list, field) - all items have a truthy field.
+//   count(list, field) - number of items with a truthy field.
+//   contains(s, sub) - string contains (case insensitive).
+//   lower(s) - lower case string.
+//   has(list, value) - list contains the value.
+//   has(list, field, value) - list contains an item with the field value.
+//   like(s, pattern) - SQL LIKE (% and _) match (case insensitive).
+var functions = map[string]function{
+	"len": func(args []interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, liberr.New("len(): 1 argument expected.")
+		}
+		if args[0] == nil {
+			return float64(0), nil
+		}
+		rv := reflect.ValueOf(args[0])
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Map, reflect.String:
+			return float64(rv.Len()), nil
+		}
+		return nil, liberr.New("len(): list, map or string expected.")
+	},
+	"any": func(args []interface{}) (interface{}, error) {
+		n, err := countItems("any", args)
+		return n > 0, err
+	},
+	"all": func(args []interface{}) (interface{}, error) {
+		n, err := countItems("all", args)
+		if err != nil {
+			return nil, err
+		}
+		list, _ := args[0].([]interface{})
+		return n == len(list), nil
+	},
+	"count": func(args []interface{}) (interface{}, error) {
+		n, err := countItems("count", args)
+		return float64(n), err
+	},
+	"contains": func(args []interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, liberr.New("contains(): 2 arguments expected.")
+		}
+		if args[0] == nil {
+			return false, nil
+		}
+		return strings.Contains(
+			strings.ToLower(fmt.Sprint(args[0])),
+			strings.ToLower(fmt.Sprint(args[1]))), nil
+	},
+	"lower": func(args []interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, liberr.New("lower(): 1 argument expected.")
+		}
+		if args[0] == nil {
+			return "", nil
+		}
+		return strings.ToLower(fmt.Sprint(args[0])), nil
+	},
+	"has": func(args []interface{}) (interface{}, error) {
+		if len(args) < 2 || len(args) > 3 {
+			return nil, liberr.New("has(): 2 or 3 arguments expected.")
+		}
+		list, _ := args[0].([]interface{})
+		want := args[len(args)-1]
+		for _, item := range list {
+			v := item
+			if len(args) == 3 {
+				for _, part := range strings.Split(fmt.Sprint(args[1]), ".") {
+					v = field(v, part)
+				}
+			}
+			if equals(v, want) {
+				return true, nil
+			}
+		}
+		return false, nil
+	},
+	"like": func(args []interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, liberr.New("like(): 2 arguments expected.")
+		}
+		if args[0] == nil {
+			return false, nil
+		}
+		pattern := regexp.QuoteMeta(fmt.Sprint(args[1]))
+		pattern = strings.ReplaceAll(pattern, "%", ".*")
+		pattern = strings.ReplaceAll(pattern, "_", ".")
+		re, err := regexp.Compile("(?is)^" + pattern + "$")
+		if err != nil {
+			return nil, liberr.Wrap(err)
+		}
+		return re.MatchString(fmt.Sprint(args[0])), nil
+	},
+}
+
+//
+// Count the list items with a truthy field.
+// When the field is omitted, the item itself is tested.
+func countItems(name string, args []interface{}) (n int, err error) {
+	if len(args) < 1 || len(args) > 2 {
+		err = liberr.New(name + "(): 1 or 2 arguments expected.")
+		return
+	}
+	list, _ := args[0].([]interface{})
+	for _, item := range list {
+		v := item
+		if len(args) == 2 {
+			v = field(item, fmt.Sprint(args[1]))
+		}
+		if truthy(v) {
+			n++
+		}
+	}
+
+	return
+}
+
+//
+// Expression parser.
+// Grammar:
+//   or      := and ('||' and)*
+//   and     := unary ('&&' unary)*
+//   unary   := '!' unary | compare
+//   compare := term (('=='|'!='|'<'|'<='|'>'|'>='|'=~'|'in') term)?
+//   term    := literal | path | call | list | '(' or ')'
+//   list    := '[' (or (',' or)*)? ']'
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+//
+// Compiled (boolean) expression.
+// The expression language is implemented here rather
+// than by embedding a general purpose engine. The same compiled
+// expressions are used by the inventory REST filters which need
+// the parsed expression to push field comparisons down to the DB.
+// See: Expression.Comparisons(). Paths and string comparisons are
+// case insensitive and `null`, `in` (list, map and string) and
+// the built-in functions operate on the generic (JSON) models.
+// The engines considered either do not support these semantics
+// (govaluate: struct field accessors only) or add a large dependency
+// tree (CEL: protobuf and antlr; Rego) for a small grammar.
+type Expression struct {
+	// Expression (source).
+	Source string
+	// Root node.
+	root node
+}
+
+//
+// Compile an expression.
+func Compile(source string) (compiled *Expression, err error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.or()
+	if err != nil {
+		return
+	}
+	if p.peek().kind != tEnd {
+		err = liberr.New("unexpected: '" + p.peek().value + "'.")
+		return
+	}
+
+	compiled = &Expression{
+		Source: source,
+		root:   root,
+	}
+
+	return
+}
+
+//
+// Field comparison.
+type Comparison struct {
+	// Field name.
+	Field string
+	// Operator.
+	Op string
+	// Literal value.
+	Value interface{}
+}
+
+//
+// Comparisons of a (top-level) field with a literal
+// joined by `&&`. The expression is `exact` when it is
+// composed entirely of the returned comparisons.
+func (r *Expression) Comparisons() (list []Comparison, exact bool) {
+	exact = true
+	pending := []node{r.root}
+	for len(pending) > 0 {
+		n := pending[0]
+		pending = pending[1:]
+		b, cast := n.(*binary)
+		if !cast {
+			exact = false
+			continue
+		}
+		switch b.op {
+		case "&&":
+			pending = append(pending, b.left, b.right)
+			continue
+		case "==", "!=", "<", "<=", ">", ">=":
+		default:
+			exact = false
+			continue
+		}
+		p, isPath := b.left.(*path)
+		v, isLiteral := b.right.(*literal)
+		if !isPath || !isLiteral || len(p.parts) != 1 {
+			exact = false
+			continue
+		}
+		list = append(
+			list,
+			Comparison{
+				Field: p.parts[0],
+				Op:    b.op,
+				Value: v.value,
+			})
+	}
+
+	return
+}
+
+//
+// Evaluate the expression.
+func (r *Expression) Eval(env Env) (matched bool, err error) {
+	v, err := r.root.eval(env)
+	if err != nil {
+		return
+	}
+
+	matched = truthy(v)
+
+	return
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tEnd {
+		p.pos++
+	}
+
+	return t
+}
+
+func (p *parser) accept(op string) bool {
+	t := p.peek()
+	if t.kind == tOperator && t.value == op {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func (p *parser) or() (n node, err error) {
+	n, err = p.and()
+	for err == nil && p.accept("||") {
+		var right node
+		right, err = p.and()
+		n = &binary{op: "||", left: n, right: right}
+	}
+
+	return
+}
+
+func (p *parser) and() (n node, err error) {
+	n, err = p.unary()
+	for err == nil && p.accept("&&") {
+		var right node
+		right, err = p.unary()
+		n = &binary{op: "&&", left: n, right: right}
+	}
+
+	return
+}
+
+func (p *parser) unary() (n node, err error) {
+	if p.accept("!") {
+		var operand node
+		operand, err = p.unary()
+		n = &not{operand: operand}
+		return
+	}
+
+	return p.compare()
+}
+
+func (p *parser) compare() (n node, err error) {
+	n, err = p.term()
+	if err != nil {
+		return
+	}
+	if t := p.peek(); t.kind == tIdent && t.value == "in" {
+		p.pos++
+		var right node
+		right, err = p.term()
+		n = &binary{op: "in", left: n, right: right}
+		return
+	}
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">", "=~"} {
+		if p.accept(op) {
+			var right node
+			right, err = p.term()
+			n = &binary{op: op, left: n, right: right}
+			break
+		}
+	}
+
+	return
+}
+
+func (p *parser) term() (n node, err error) {
+	t := p.next()
+	switch t.kind {
+	case tNumber:
+		var f float64
+		f, err = strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			err = liberr.New("invalid number: '" + t.value + "'.")
+			return
+		}
+		n = &literal{value: f}
+	case tString:
+		n = &literal{value: t.value}
+	case tIdent:
+		switch t.value {
+		case "true":
+			n = &literal{value: true}
+			return
+		case "false":
+			n = &literal{value: false}
+			return
+		case "null", "nil":
+			n = &literal{value: nil}
+			return
+		}
+		if p.accept("(") {
+			n, err = p.call(t.value)
+			return
+		}
+		n = &path{parts: strings.Split(t.value, ".")}
+	case tOperator:
+		if t.value == "(" {
+			n, err = p.or()
+			if err != nil {
+				return
+			}
+			if !p.accept(")") {
+				err = liberr.New("')' expected.")
+			}
+			return
+		}
+		if t.value == "[" {
+			n, err = p.list()
+			return
+		}
+		err = liberr.New("unexpected: '" + t.value + "'.")
+	default:
+		err = liberr.New("unexpected end of expression.")
+	}
+
+	return
+}
+
+func (p *parser) call(name string) (n node, err error) {
+	if _, found := functions[strings.ToLower(name)]; !found {
+		err = liberr.New("function: '" + name + "' not found.")
+		return
+	}
+	fn := &call{name: name}
+	if !p.accept(")") {
+		for {
+			var arg node
+			arg, err = p.or()
+			if err != nil {
+				return
+			}
+			fn.args = append(fn.args, arg)
+			if p.accept(",") {
+				continue
+			}
+			if p.accept(")") {
+				break
+			}
+			err = liberr.New("')' expected.")
+			return
+		}
+	}
+
+	n = fn
+
+	return
+}
+
+func (p *parser) list() (n node, err error) {
+	l := &list{}
+	if !p.accept("]") {
+		for {
+			var item node
+			item, err = p.or()
+			if err != nil {
+				return
+			}
+			l.items = append(l.items, item)
+			if p.accept(",") {
+				continue
+			}
+			if p.accept("]") {
+				break
+			}
+			err = liberr.New("']' expected.")
+			return
+		}
+	}
+
+	n = l
+
+	return
+}
+
+//
+// Get a field (case insensitive) of a map.
+func field(v interface{}, name string) interface{} {
+	m, cast := v.(map[string]interface{})
+	if !cast {
+		return nil
+	}
+	if fv, found := m[name]; found {
+		return fv
+	}
+	for k, fv := range m {
+		if strings.EqualFold(k, name) {
+			return fv
+		}
+	}
+
+	return nil
+}
+
+//
+// Determine if the value is truthy.
+func truthy(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return len(v) > 0
+	}
+
+	return true
+}
+
+//
+// Equality.
+// Strings are compared case insensitive.
+func equals(left, right interface{}) bool {
+	if ls, cast := left.(string); cast {
+		if rs, cast := right.(string); cast {
+			return strings.EqualFold(ls, rs)
+		}
+	}
+
+	return reflect.DeepEqual(left, right)
+}
+
+//
+// Ordered comparison of numbers or strings.
+func compare(op string, left, right interface{}) (v bool, err error) {
+	var n int
+	lf, lNum := left.(float64)
+	rf, rNum := right.(float64)
+	ls, lStr := left.(string)
+	rs, rStr := right.(string)
+	switch {
+	case lNum && rNum:
+		switch {
+		case lf < rf:
+			n = -1
+		case lf > rf:
+			n = 1
+		}
+	case lStr && rStr:
+		n = strings.Compare(ls, rs)
+	case left == nil || right == nil:
+		return
+	default:
+		err = liberr.New(
+			fmt.Sprintf(
+				"'%s' not supported for: %T, %T.",
+				op,
+				left,
+				right))
+		return
+	}
+	switch op {
+	case "<":
+		v = n < 0
+	case "<=":
+		v = n <= 0
+	case ">":
+		v = n > 0
+	case ">=":
+		v = n >= 0
+	}
+
+	return
+}
+
+//
+// Membership (in).
+// The value is an item of a list, a key of a map or
+// a substring (case insensitive) of a string.
+func member(left, right interface{}) (v bool, err error) {
+	switch right := right.(type) {
+	case nil:
+	case []interface{}:
+		for _, item := range right {
+			if equals(left, item) {
+				v = true
+				break
+			}
+		}
+	case map[string]interface{}:
+		_, v = right[fmt.Sprint(left)]
+	case string:
+		v = left != nil && strings.Contains(
+			strings.ToLower(right),
+			strings.ToLower(fmt.Sprint(left)))
+	default:
+		err = liberr.New(
+			fmt.Sprintf(
+				"'in' not supported for: %T.",
+				right))
+	}
+
+	return
+}
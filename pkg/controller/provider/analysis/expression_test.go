@@ -0,0 +1,131 @@
+package analysis
+
+import (
+	"github.com/onsi/gomega"
+	"testing"
+)
+
+func TestExpression(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	env := Env{}
+	vm, err := Object(
+		map[string]interface{}{
+			"name":       "rhel-8",
+			"cpuCount":   4,
+			"memoryMB":   8192,
+			"guestName":  "Red Hat Enterprise Linux 8 (64-bit)",
+			"powerState": "poweredOn",
+			"encrypted":  false,
+			"tpmEnabled": true,
+			"disks": []interface{}{
+				map[string]interface{}{"file": "[ds0] a.vmdk", "shared": false},
+				map[string]interface{}{"file": "[ds0] b.vmdk", "shared": true},
+			},
+			"tags": []interface{}{"prod", "db"},
+			"attributes": map[string]interface{}{
+				"owner": "dba",
+			},
+		})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	env["vm"] = vm
+	cases := []struct {
+		when     string
+		expected bool
+	}{
+		// Types.
+		{when: "vm.cpuCount == 4", expected: true},
+		{when: "vm.cpuCount == 4.0", expected: true},
+		{when: "vm.cpuCount == '4'", expected: false},
+		{when: "vm.name == 'RHEL-8'", expected: true},
+		{when: "vm.encrypted == false", expected: true},
+		{when: "vm.tpmEnabled", expected: true},
+		{when: "vm.missing == null", expected: true},
+		{when: "vm.missing", expected: false},
+		{when: "vm.disks", expected: true},
+		{when: "vm.memoryMB >= 8192", expected: true},
+		{when: "vm.memoryMB > 8192", expected: false},
+		{when: "vm.name < 's'", expected: true},
+		{when: "vm.missing > 1", expected: false},
+		// Paths are case insensitive.
+		{when: "VM.CPUCOUNT == 4", expected: true},
+		// Precedence.
+		{when: "true || false && false", expected: true},
+		{when: "(true || false) && false", expected: false},
+		{when: "!false && false", expected: false},
+		{when: "!(false && false)", expected: true},
+		{when: "!vm.encrypted && vm.cpuCount > 2 || vm.memoryMB < 1", expected: true},
+		{when: "vm.cpuCount > 2 && vm.memoryMB < 1 || vm.tpmEnabled", expected: true},
+		{when: "vm.cpuCount > 2 && (vm.memoryMB < 1 || vm.encrypted)", expected: false},
+		// In.
+		{when: "'prod' in vm.tags", expected: true},
+		{when: "'PROD' in vm.tags", expected: true},
+		{when: "'test' in vm.tags", expected: false},
+		{when: "vm.cpuCount in [2, 4, 8]", expected: true},
+		{when: "vm.powerState in ['poweredOff', 'suspended']", expected: false},
+		{when: "'owner' in vm.attributes", expected: true},
+		{when: "'linux' in vm.guestName", expected: true},
+		{when: "'x' in vm.missing", expected: false},
+		{when: "!('test' in vm.tags)", expected: true},
+		// Regex.
+		{when: "vm.guestName =~ 'Linux [0-9]+'", expected: true},
+		{when: "vm.guestName =~ '^Windows'", expected: false},
+		{when: "vm.missing =~ '.*'", expected: false},
+		// Functions.
+		{when: "len(vm.disks) == 2", expected: true},
+		{when: "any(vm.disks, 'shared')", expected: true},
+		{when: "all(vm.disks, 'shared')", expected: false},
+		{when: "count(vm.disks, 'shared') == 1", expected: true},
+		{when: "has(vm.disks, 'file', '[ds0] b.vmdk')", expected: true},
+		{when: "has(vm.tags, 'db')", expected: true},
+		{when: "contains(vm.guestName, 'red hat')", expected: true},
+		{when: "like(vm.name, 'rhel-_')", expected: true},
+		{when: "lower(vm.powerState) == 'poweredon'", expected: true},
+	}
+	for _, c := range cases {
+		expression, err := Compile(c.when)
+		g.Expect(err).NotTo(gomega.HaveOccurred(), c.when)
+		matched, err := expression.Eval(env)
+		g.Expect(err).NotTo(gomega.HaveOccurred(), c.when)
+		g.Expect(matched).To(gomega.Equal(c.expected), c.when)
+	}
+}
+
+func TestExpressionErrors(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	env := Env{
+		"vm": map[string]interface{}{
+			"name":     "rhel-8",
+			"cpuCount": float64(4),
+		},
+	}
+	// Compile.
+	for _, when := range []string{
+		"",
+		"vm.name ==",
+		"(vm.cpuCount > 1",
+		"vm.cpuCount > 1)",
+		"vm.cpuCount in [1, 2",
+		"vm.name == 'rhel",
+		"vm.name # 1",
+		"1.2.3 == 1",
+		"nope(vm.name)",
+		"vm.name == vm.name vm.name",
+	} {
+		_, err := Compile(when)
+		g.Expect(err).To(gomega.HaveOccurred(), when)
+	}
+	// Eval.
+	for _, when := range []string{
+		"vm.name > 1",
+		"vm.cpuCount in 4",
+		"vm.name =~ '['",
+		"len(vm.cpuCount) > 0",
+		"len(vm.name, 1) > 0",
+		"contains(vm.name) ",
+	} {
+		expression, err := Compile(when)
+		g.Expect(err).NotTo(gomega.HaveOccurred(), when)
+		_, err = expression.Eval(env)
+		g.Expect(err).To(gomega.HaveOccurred(), when)
+	}
+}
@@ -0,0 +1,312 @@
+package analysis
+
+import (
+	"encoding/json"
+	liberr "github.com/konveyor/controller/pkg/error"
+	"io/ioutil"
+	core "k8s.io/api/core/v1"
+	"os"
+	"sigs.k8s.io/yaml"
+	"sync"
+	"time"
+)
+
+//
+// Rule severity.
+const (
+	Advisory = "Advisory"
+	Warning  = "Warning"
+	Critical = "Critical"
+)
+
+//
+// Analysis rule.
+// Example:
+//   name: CPU hot-add not supported.
+//   severity: Warning
+//   when: vm.cpuHotAddEnabled && cluster.drsEnabled
+type Rule struct {
+	// Name (concern).
+	Name string `json:"name"`
+	// Severity.
+	Severity string `json:"severity"`
+	// Expression.
+	When string `json:"when"`
+	// Compiled expression.
+	expression *Expression
+}
+
+//
+// Compile the rule.
+func (r *Rule) Compile() (err error) {
+	if r.Name == "" {
+		err = liberr.New("rule: name required.")
+		return
+	}
+	switch r.Severity {
+	case Advisory, Warning, Critical:
+	default:
+		err = liberr.New("rule: '" + r.Name + "' severity not valid.")
+		return
+	}
+	r.expression, err = Compile(r.When)
+	if err != nil {
+		err = liberr.New("rule: '" + r.Name + "' " + err.Error())
+		return
+	}
+
+	return
+}
+
+//
+// Evaluate the rule.
+func (r *Rule) Eval(env Env) (matched bool, err error) {
+	if r.expression == nil {
+		err = r.Compile()
+		if err != nil {
+			return
+		}
+	}
+	matched, err = r.expression.Eval(env)
+	if err != nil {
+		err = liberr.New("rule: '" + r.Name + "' " + err.Error())
+	}
+
+	return
+}
+
+//
+// Rule set.
+type RuleSet struct {
+	Rules []Rule `json:"rules"`
+}
+
+//
+// Parse (YAML) and compile the rule set.
+func (r *RuleSet) Parse(content []byte) (err error) {
+	err = yaml.Unmarshal(content, r)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range r.Rules {
+		err = r.Rules[i].Compile()
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+//
+// Evaluate the rule set.
+// Returns the matched rules. Rules that fail evaluation
+// are skipped and the errors returned.
+func (r *RuleSet) Eval(env Env) (matched []Rule, errList []error) {
+	matched = []Rule{}
+	for i := range r.Rules {
+		rule := &r.Rules[i]
+		hit, err := rule.Eval(env)
+		if err != nil {
+			errList = append(errList, err)
+			continue
+		}
+		if hit {
+			matched = append(matched, *rule)
+		}
+	}
+
+	return
+}
+
+//
+// Build an env entry for a model.
+// The model is converted (using JSON) into a tree of generic
+// maps and lists used to resolve paths within expressions.
+func Object(model interface{}) (object interface{}, err error) {
+	if model == nil {
+		return
+	}
+	b, err := json.Marshal(model)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = json.Unmarshal(b, &object)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
+//
+// Rule set source.
+type RuleSource interface {
+	// Get the rule set.
+	RuleSet() (*RuleSet, error)
+	// The rule set revision.
+	Revision() (int, error)
+}
+
+//
+// Rule set file.
+// Typically, a key within a mounted ConfigMap. The file is
+// (re)loaded when modified so that rules may be updated
+// without restarting the controller.
+type RuleFile struct {
+	// File path.
+	Path string
+	// Mutex.
+	mutex sync.Mutex
+	// Last modified.
+	modified time.Time
+	// Loaded rule set.
+	ruleSet *RuleSet
+	// Incremented when the rule set is (re)loaded or removed.
+	revision int
+}
+
+//
+// Get the rule set.
+// An empty rule set is returned when the path is not
+// defined or the file does not exist.
+func (r *RuleFile) RuleSet() (ruleSet *RuleSet, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	ruleSet = &RuleSet{}
+	if r.Path == "" {
+		return
+	}
+	st, err := os.Stat(r.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+			if r.ruleSet != nil {
+				r.ruleSet = nil
+				r.revision++
+			}
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	if r.ruleSet != nil && st.ModTime().Equal(r.modified) {
+		ruleSet = r.ruleSet
+		return
+	}
+	content, err := ioutil.ReadFile(r.Path)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	loaded := &RuleSet{}
+	err = loaded.Parse(content)
+	if err != nil {
+		return
+	}
+
+	r.modified = st.ModTime()
+	r.ruleSet = loaded
+	r.revision++
+	ruleSet = loaded
+
+	return
+}
+
+//
+// The rule set revision.
+// The rule file is (re)loaded as needed.
+func (r *RuleFile) Revision() (revision int, err error) {
+	_, err = r.RuleSet()
+	if err != nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	revision = r.revision
+
+	return
+}
+
+//
+// ConfigMap key containing the rule set (YAML).
+const RulesKey = "rules"
+
+//
+// Shared rule set ConfigMap.
+// Updated by the provider controller. See: RuleConfigMap.Update().
+var ConfigMapRules = &RuleConfigMap{}
+
+//
+// Rule set ConfigMap.
+// The rule set is (re)loaded when the watched ConfigMap is
+// created, updated or deleted so that rules may be updated
+// without restarting the controller.
+type RuleConfigMap struct {
+	// Mutex.
+	mutex sync.Mutex
+	// Loaded resource version.
+	resourceVersion string
+	// Loaded rule set.
+	ruleSet *RuleSet
+	// Incremented when the rule set is (re)loaded or removed.
+	revision int
+}
+
+//
+// Update the rule set.
+// A nil ConfigMap (deleted) removes the rule set. The
+// loaded rule set is kept when the content is not valid.
+func (r *RuleConfigMap) Update(cm *core.ConfigMap) (err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if cm == nil {
+		if r.ruleSet != nil {
+			r.resourceVersion = ""
+			r.ruleSet = nil
+			r.revision++
+		}
+		return
+	}
+	if r.ruleSet != nil && cm.ResourceVersion == r.resourceVersion {
+		return
+	}
+	loaded := &RuleSet{}
+	err = loaded.Parse([]byte(cm.Data[RulesKey]))
+	if err != nil {
+		return
+	}
+
+	r.resourceVersion = cm.ResourceVersion
+	r.ruleSet = loaded
+	r.revision++
+
+	return
+}
+
+//
+// Get the rule set.
+// An empty rule set is returned when not loaded.
+func (r *RuleConfigMap) RuleSet() (ruleSet *RuleSet, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	ruleSet = r.ruleSet
+	if ruleSet == nil {
+		ruleSet = &RuleSet{}
+	}
+
+	return
+}
+
+//
+// The rule set revision.
+func (r *RuleConfigMap) Revision() (revision int, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	revision = r.revision
+
+	return
+}
@@ -0,0 +1,134 @@
+package analysis
+
+import (
+	"github.com/onsi/gomega"
+	"io/ioutil"
+	core "k8s.io/api/core/v1"
+	"os"
+	pathlib "path"
+	"testing"
+	"time"
+)
+
+func TestRuleSetEval(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	ruleSet := &RuleSet{}
+	err := ruleSet.Parse([]byte(`
+rules:
+- name: Many CPUs.
+  severity: Warning
+  when: vm.cpuCount > 2
+- name: Broken.
+  severity: Advisory
+  when: vm.name > 1
+- name: Named.
+  severity: Critical
+  when: vm.name == 'rhel-8'
+`))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	env := Env{
+		"vm": map[string]interface{}{
+			"name":     "rhel-8",
+			"cpuCount": float64(4),
+		},
+	}
+	matched, errList := ruleSet.Eval(env)
+	g.Expect(errList).To(gomega.HaveLen(1))
+	g.Expect(matched).To(gomega.HaveLen(2))
+	g.Expect(matched[0].Name).To(gomega.Equal("Many CPUs."))
+	g.Expect(matched[1].Name).To(gomega.Equal("Named."))
+	// Not valid.
+	err = ruleSet.Parse([]byte(`
+rules:
+- name: Bad.
+  severity: Unknown
+  when: true
+`))
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+func TestRuleFile(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	dir, err := ioutil.TempDir("", "rules")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	defer os.RemoveAll(dir)
+	ruleFile := &RuleFile{Path: pathlib.Join(dir, "rules.yaml")}
+	// Not found.
+	revision, err := ruleFile.Revision()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(revision).To(gomega.Equal(0))
+	// Loaded.
+	write := func(content string, modified time.Time) {
+		g.Expect(ioutil.WriteFile(ruleFile.Path, []byte(content), 0644)).NotTo(gomega.HaveOccurred())
+		g.Expect(os.Chtimes(ruleFile.Path, modified, modified)).NotTo(gomega.HaveOccurred())
+	}
+	mark := time.Now()
+	write("rules:\n- name: A.\n  severity: Warning\n  when: true\n", mark)
+	ruleSet, err := ruleFile.RuleSet()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(ruleSet.Rules).To(gomega.HaveLen(1))
+	revision, err = ruleFile.Revision()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(revision).To(gomega.Equal(1))
+	// Not modified.
+	revision, err = ruleFile.Revision()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(revision).To(gomega.Equal(1))
+	// Reloaded.
+	write("rules: []\n", mark.Add(time.Second))
+	revision, err = ruleFile.Revision()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(revision).To(gomega.Equal(2))
+	// Removed.
+	g.Expect(os.Remove(ruleFile.Path)).NotTo(gomega.HaveOccurred())
+	revision, err = ruleFile.Revision()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(revision).To(gomega.Equal(3))
+}
+
+func TestRuleConfigMap(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	rules := &RuleConfigMap{}
+	// Not loaded.
+	ruleSet, err := rules.RuleSet()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(ruleSet.Rules).To(gomega.BeEmpty())
+	revision, _ := rules.Revision()
+	g.Expect(revision).To(gomega.Equal(0))
+	// Loaded.
+	cm := &core.ConfigMap{
+		Data: map[string]string{
+			RulesKey: "rules:\n- name: A.\n  severity: Warning\n  when: true\n",
+		},
+	}
+	cm.ResourceVersion = "1"
+	g.Expect(rules.Update(cm)).NotTo(gomega.HaveOccurred())
+	ruleSet, _ = rules.RuleSet()
+	g.Expect(ruleSet.Rules).To(gomega.HaveLen(1))
+	revision, _ = rules.Revision()
+	g.Expect(revision).To(gomega.Equal(1))
+	// Not modified.
+	g.Expect(rules.Update(cm)).NotTo(gomega.HaveOccurred())
+	revision, _ = rules.Revision()
+	g.Expect(revision).To(gomega.Equal(1))
+	// Not valid; previous rule set kept.
+	cm.ResourceVersion = "2"
+	cm.Data[RulesKey] = "rules:\n- name: B.\n  severity: Unknown\n  when: true\n"
+	g.Expect(rules.Update(cm)).To(gomega.HaveOccurred())
+	ruleSet, _ = rules.RuleSet()
+	g.Expect(ruleSet.Rules[0].Name).To(gomega.Equal("A."))
+	revision, _ = rules.Revision()
+	g.Expect(revision).To(gomega.Equal(1))
+	// Reloaded.
+	cm.ResourceVersion = "3"
+	cm.Data[RulesKey] = "rules: []\n"
+	g.Expect(rules.Update(cm)).NotTo(gomega.HaveOccurred())
+	ruleSet, _ = rules.RuleSet()
+	g.Expect(ruleSet.Rules).To(gomega.BeEmpty())
+	revision, _ = rules.Revision()
+	g.Expect(revision).To(gomega.Equal(2))
+	// Deleted.
+	g.Expect(rules.Update(nil)).NotTo(gomega.HaveOccurred())
+	revision, _ = rules.Revision()
+	g.Expect(revision).To(gomega.Equal(3))
+}
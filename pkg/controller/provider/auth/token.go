@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	liberr "github.com/konveyor/controller/pkg/error"
+	auth "k8s.io/api/authentication/v1"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+//
+// Requested service account token lifetime.
+var TokenExpiration = time.Hour
+
+//
+// Token sources keyed by provider (namespace/name).
+var tokenSources = struct {
+	sync.Mutex
+	m map[string]*TokenSource
+}{
+	m: map[string]*TokenSource{},
+}
+
+//
+// Build k8s REST configuration using short-lived service account
+// tokens. The `cfg` (credentials) is used to request the tokens.
+// The token source is shared by configurations built for the same
+// provider (UID), URL, secret (version) and service account.
+func RestCfg(provider meta.Object, secret *core.Secret, cfg *rest.Config, sa string) (tokenCfg *rest.Config, err error) {
+	namespace, name, err := SplitServiceAccount(sa)
+	if err != nil {
+		return
+	}
+	key := path.Join(provider.GetNamespace(), provider.GetName())
+	version := strings.Join(
+		[]string{
+			string(provider.GetUID()),
+			cfg.Host,
+			string(secret.UID),
+			secret.ResourceVersion,
+			sa,
+		},
+		"/")
+	tokenSources.Lock()
+	source, found := tokenSources.m[key]
+	if !found || source.version != version {
+		source = &TokenSource{
+			cfg:       cfg,
+			namespace: namespace,
+			name:      name,
+			version:   version,
+		}
+		tokenSources.m[key] = source
+	}
+	tokenSources.Unlock()
+	tokenCfg = rest.AnonymousClientConfig(cfg)
+	tokenCfg.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+		return &tokenTransport{
+			source: source,
+			next:   rt,
+		}
+	}
+
+	return
+}
+
+//
+// Delete the token source for the provider.
+// Called when the provider has been deleted.
+func Delete(provider meta.Object) {
+	tokenSources.Lock()
+	defer tokenSources.Unlock()
+	delete(
+		tokenSources.m,
+		path.Join(provider.GetNamespace(), provider.GetName()))
+}
+
+//
+// Split the service account reference.
+// Format: namespace/name.
+func SplitServiceAccount(sa string) (namespace, name string, err error) {
+	part := strings.Split(sa, "/")
+	if len(part) != 2 || part[0] == "" || part[1] == "" {
+		err = liberr.New(
+			fmt.Sprintf(
+				"Service account: '%s' must be: namespace/name.",
+				sa))
+		return
+	}
+	namespace = part[0]
+	name = part[1]
+
+	return
+}
+
+//
+// Short-lived service account token source.
+// Tokens are requested using the TokenRequest API and renewed
+// when 80% of the lifetime has elapsed.
+type TokenSource struct {
+	// REST configuration (credentials) used to request tokens.
+	cfg *rest.Config
+	// Service account namespace.
+	namespace string
+	// Service account name.
+	name string
+	// Provider, secret and service account version.
+	version string
+	// Protect the token.
+	mutex sync.Mutex
+	// The current token.
+	token string
+	// When the token should be renewed.
+	renew time.Time
+}
+
+//
+// Get the current token.
+// A new token is requested as needed.
+func (r *TokenSource) Token() (token string, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.token != "" && time.Now().Before(r.renew) {
+		token = r.token
+		return
+	}
+	clientset, err := kubernetes.NewForConfig(r.cfg)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	seconds := int64(TokenExpiration.Seconds())
+	request := &auth.TokenRequest{
+		Spec: auth.TokenRequestSpec{
+			ExpirationSeconds: &seconds,
+		},
+	}
+	mark := time.Now()
+	request, err = clientset.CoreV1().ServiceAccounts(r.namespace).CreateToken(
+		context.TODO(),
+		r.name,
+		request,
+		meta.CreateOptions{})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	lifetime := request.Status.ExpirationTimestamp.Sub(mark)
+	r.token = request.Status.Token
+	r.renew = mark.Add(lifetime * 8 / 10)
+	token = r.token
+
+	return
+}
+
+//
+// Transport (round tripper) used to set the
+// bearer token on each request.
+type tokenTransport struct {
+	// Token source.
+	source *TokenSource
+	// Wrapped transport.
+	next http.RoundTripper
+}
+
+//
+// Set the bearer token and send the request.
+func (r *tokenTransport) RoundTrip(request *http.Request) (response *http.Response, err error) {
+	token, err := r.source.Token()
+	if err != nil {
+		return
+	}
+	request = request.Clone(request.Context())
+	request.Header.Set("Authorization", "Bearer "+token)
+	response, err = r.next.RoundTrip(request)
+
+	return
+}
@@ -0,0 +1,220 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/onsi/gomega"
+	auth "k8s.io/api/authentication/v1"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+//
+// Fake api-server.
+// Issues service account tokens using the TokenRequest API
+// and records the authorization (header) of other requests.
+type Server struct {
+	*httptest.Server
+	// Issued token lifetime.
+	Lifetime time.Duration
+	// Service accounts for which tokens have been requested.
+	Requested []string
+	// Credentials used to request tokens.
+	Credentials []string
+	// Authorization of other requests.
+	Authorization []string
+	// Protect the server.
+	mutex sync.Mutex
+}
+
+func (r *Server) Start() {
+	r.Server = httptest.NewServer(http.HandlerFunc(r.handle))
+}
+
+func (r *Server) handle(w http.ResponseWriter, request *http.Request) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	authorization := request.Header.Get("Authorization")
+	if !strings.HasSuffix(request.URL.Path, "/token") {
+		r.Authorization = append(r.Authorization, authorization)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"kind":"NamespaceList","apiVersion":"v1","items":[]}`))
+		return
+	}
+	part := strings.Split(request.URL.Path, "/")
+	r.Requested = append(r.Requested, part[4]+"/"+part[6])
+	r.Credentials = append(r.Credentials, authorization)
+	tr := &auth.TokenRequest{
+		TypeMeta: meta.TypeMeta{
+			Kind:       "TokenRequest",
+			APIVersion: "authentication.k8s.io/v1",
+		},
+		Status: auth.TokenRequestStatus{
+			Token:               fmt.Sprintf("token-%d", len(r.Requested)),
+			ExpirationTimestamp: meta.NewTime(time.Now().Add(r.Lifetime)),
+		},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(tr)
+}
+
+func (r *Server) requested() (n int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	n = len(r.Requested)
+	return
+}
+
+func TestTokenSource(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	server := &Server{Lifetime: time.Hour}
+	server.Start()
+	defer server.Close()
+	source := &TokenSource{
+		cfg: &rest.Config{
+			Host:        server.URL,
+			BearerToken: "admin",
+		},
+		namespace: "ns",
+		name:      "sa",
+	}
+
+	// Requested.
+	token, err := source.Token()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(token).To(gomega.Equal("token-1"))
+	g.Expect(server.Requested).To(gomega.Equal([]string{"ns/sa"}))
+	g.Expect(server.Credentials).To(gomega.Equal([]string{"Bearer admin"}))
+
+	// Cached.
+	token, err = source.Token()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(token).To(gomega.Equal("token-1"))
+	g.Expect(server.requested()).To(gomega.Equal(1))
+
+	// Renewed when 80% of the lifetime has elapsed.
+	// The expiration is reported in (whole) seconds.
+	server.Lifetime = time.Second * 10
+	source.renew = time.Now()
+	token, err = source.Token()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(token).To(gomega.Equal("token-2"))
+	g.Expect(source.renew).To(
+		gomega.BeTemporally("~", time.Now().Add(time.Second*8), time.Second))
+	token, _ = source.Token()
+	g.Expect(token).To(gomega.Equal("token-2"))
+	server.Lifetime = time.Second
+	source.renew = time.Now()
+	token, _ = source.Token()
+	g.Expect(token).To(gomega.Equal("token-3"))
+	g.Eventually(
+		func() string {
+			token, _ := source.Token()
+			return token
+		},
+		time.Second*5,
+		time.Millisecond*100).Should(gomega.Equal("token-4"))
+
+	// Failed.
+	server.Close()
+	source.renew = time.Now()
+	_, err = source.Token()
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+func TestRestCfg(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	server := &Server{Lifetime: time.Hour}
+	server.Start()
+	defer server.Close()
+	provider := &meta.ObjectMeta{
+		Namespace: "test",
+		Name:      "ocp",
+		UID:       "1",
+	}
+	secret := &core.Secret{
+		ObjectMeta: meta.ObjectMeta{
+			UID:             "2",
+			ResourceVersion: "1",
+		},
+	}
+	cfg := &rest.Config{
+		Host:        server.URL,
+		BearerToken: "admin",
+	}
+	source := func() *TokenSource {
+		tokenSources.Lock()
+		defer tokenSources.Unlock()
+		return tokenSources.m["test/ocp"]
+	}
+	defer Delete(provider)
+
+	// Not valid.
+	_, err := RestCfg(provider, secret, cfg, "sa")
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(source()).To(gomega.BeNil())
+
+	// Bearer token set on each request.
+	tokenCfg, err := RestCfg(provider, secret, cfg, "ns/sa")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(tokenCfg.BearerToken).To(gomega.BeEmpty())
+	first := source()
+	g.Expect(first).NotTo(gomega.BeNil())
+	rt, err := rest.TransportFor(tokenCfg)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	client := &http.Client{Transport: rt}
+	for i := 0; i < 2; i++ {
+		response, err := client.Get(server.URL + "/api/v1/namespaces")
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		_ = response.Body.Close()
+	}
+	g.Expect(server.Authorization).To(
+		gomega.Equal(
+			[]string{
+				"Bearer token-1",
+				"Bearer token-1",
+			}))
+	g.Expect(server.Credentials).To(gomega.Equal([]string{"Bearer admin"}))
+
+	// Shared.
+	_, err = RestCfg(provider, secret, cfg, "ns/sa")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(source()).To(gomega.BeIdenticalTo(first))
+
+	// Replaced when the secret has been updated.
+	secret.ResourceVersion = "2"
+	_, err = RestCfg(provider, secret, cfg, "ns/sa")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(source()).NotTo(gomega.BeIdenticalTo(first))
+
+	// Replaced when the provider has been re-created.
+	second := source()
+	provider.UID = "3"
+	_, err = RestCfg(provider, secret, cfg, "ns/sa")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(source()).NotTo(gomega.BeIdenticalTo(second))
+
+	// Deleted.
+	Delete(provider)
+	g.Expect(source()).To(gomega.BeNil())
+}
+
+func TestSplitServiceAccount(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	namespace, name, err := SplitServiceAccount("ns/sa")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(namespace).To(gomega.Equal("ns"))
+	g.Expect(name).To(gomega.Equal("sa"))
+	for _, sa := range []string{"", "sa", "/sa", "ns/", "ns/sa/x"} {
+		_, _, err = SplitServiceAccount(sa)
+		g.Expect(err).To(gomega.HaveOccurred(), sa)
+	}
+}
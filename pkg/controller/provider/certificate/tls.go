@@ -0,0 +1,117 @@
+package certificate
+
+import (
+	"crypto/sha1"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	liberr "github.com/konveyor/controller/pkg/error"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	"github.com/vmware/govmomi/vim25/soap"
+	core "k8s.io/api/core/v1"
+	"strings"
+)
+
+//
+// The server certificate does not match
+// the (pinned) thumbprint.
+type ThumbprintError struct {
+	// Expected (pinned) thumbprint.
+	Expected string
+	// Thumbprint reported by the server.
+	Actual string
+}
+
+func (e ThumbprintError) Error() string {
+	return fmt.Sprintf(
+		"The certificate thumbprint: %s does not match: %s.",
+		e.Actual,
+		e.Expected)
+}
+
+//
+// Configure TLS (certificate verification) using the secret.
+//   insecureSkipVerify: "true" - certificates are not verified.
+//   cacert: certificates are verified using the CA bundle.
+//   thumbprint: the server certificate must match the thumbprint.
+// When the CA bundle is not specified, the certificate is pinned
+// (thumbprint) and the chain is not verified. Certificates are
+// verified using the system CA bundle when neither is specified.
+func TLSConfig(client *soap.Client, secret *core.Secret) (err error) {
+	cfg := client.DefaultTransport().TLSClientConfig
+	if cfg == nil {
+		cfg = &tls.Config{}
+		client.DefaultTransport().TLSClientConfig = cfg
+	}
+	if api.InsecureSkipVerify(secret) {
+		cfg.InsecureSkipVerify = true
+		return
+	}
+	if ca, found := secret.Data[api.CACert]; found {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			err = liberr.New("The CA certificate (bundle) is not valid.")
+			return
+		}
+		cfg.RootCAs = pool
+	}
+	expected := string(secret.Data[api.Thumbprint])
+	if expected == "" {
+		return
+	}
+	if cfg.RootCAs == nil {
+		cfg.InsecureSkipVerify = true
+	}
+	cfg.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) (err error) {
+		if len(rawCerts) == 0 {
+			err = ThumbprintError{Expected: expected}
+			return
+		}
+		actual := Thumbprint(rawCerts[0])
+		if NormalizedThumbprint(actual) != NormalizedThumbprint(expected) {
+			err = ThumbprintError{
+				Expected: expected,
+				Actual:   actual,
+			}
+		}
+		return
+	}
+
+	return
+}
+
+//
+// Certificate SHA-1 fingerprint (thumbprint).
+// Formatted as colon separated (upper case) hex bytes.
+func Thumbprint(der []byte) string {
+	sum := sha1.Sum(der)
+	hex := make([]string, len(sum))
+	for i, b := range sum {
+		hex[i] = fmt.Sprintf("%02X", b)
+	}
+
+	return strings.Join(hex, ":")
+}
+
+//
+// Thumbprint normalized for comparison.
+func NormalizedThumbprint(thumbprint string) string {
+	return strings.ToUpper(strings.ReplaceAll(thumbprint, ":", ""))
+}
+
+//
+// Validate the thumbprint format.
+// Must be a SHA-1 fingerprint (20 bytes) formatted as hex.
+func ValidThumbprint(thumbprint string) bool {
+	n := NormalizedThumbprint(thumbprint)
+	if len(n) != sha1.Size*2 {
+		return false
+	}
+	for _, c := range n {
+		if !strings.ContainsRune("0123456789ABCDEF", c) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,212 @@
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	"github.com/onsi/gomega"
+	"github.com/vmware/govmomi/vim25/soap"
+	"io/ioutil"
+	core "k8s.io/api/core/v1"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	liburl "net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTLSConfig(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	server := httptest.NewUnstartedServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {}))
+	server.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+	other := certificate(g)
+	thumbprint := Thumbprint(server.Certificate().Raw)
+	mismatched := Thumbprint(other)
+	ca := pem.EncodeToMemory(
+		&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: server.Certificate().Raw,
+		})
+	otherCA := pem.EncodeToMemory(
+		&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: other,
+		})
+	cases := []struct {
+		name       string
+		data       map[string][]byte
+		verified   bool
+		thumbprint bool
+	}{
+		{
+			name: "system CA",
+		},
+		{
+			name: "insecure",
+			data: map[string][]byte{
+				api.Insecure: []byte("true"),
+			},
+			verified: true,
+		},
+		{
+			name: "insecure not opted-in",
+			data: map[string][]byte{
+				api.Insecure: []byte("false"),
+			},
+		},
+		{
+			name: "insecure malformed",
+			data: map[string][]byte{
+				api.Insecure: []byte("yes"),
+			},
+		},
+		{
+			name: "thumbprint",
+			data: map[string][]byte{
+				api.Thumbprint: []byte(thumbprint),
+			},
+			verified: true,
+		},
+		{
+			name: "thumbprint normalized",
+			data: map[string][]byte{
+				api.Thumbprint: []byte(
+					strings.ToLower(
+						strings.ReplaceAll(thumbprint, ":", ""))),
+			},
+			verified: true,
+		},
+		{
+			name: "thumbprint mismatched",
+			data: map[string][]byte{
+				api.Thumbprint: []byte(mismatched),
+			},
+			thumbprint: true,
+		},
+		{
+			name: "CA",
+			data: map[string][]byte{
+				api.CACert: ca,
+			},
+			verified: true,
+		},
+		{
+			name: "CA not matched",
+			data: map[string][]byte{
+				api.CACert: otherCA,
+			},
+		},
+		{
+			name: "CA and thumbprint",
+			data: map[string][]byte{
+				api.CACert:     ca,
+				api.Thumbprint: []byte(thumbprint),
+			},
+			verified: true,
+		},
+		{
+			name: "CA and thumbprint mismatched",
+			data: map[string][]byte{
+				api.CACert:     ca,
+				api.Thumbprint: []byte(mismatched),
+			},
+			thumbprint: true,
+		},
+		{
+			name: "CA not matched and thumbprint",
+			data: map[string][]byte{
+				api.CACert:     otherCA,
+				api.Thumbprint: []byte(thumbprint),
+			},
+		},
+	}
+	for _, c := range cases {
+		client := soapClient(g, server.URL)
+		err := TLSConfig(client, &core.Secret{Data: c.data})
+		g.Expect(err).NotTo(gomega.HaveOccurred(), c.name)
+		err = get(client, server.URL)
+		if c.verified {
+			g.Expect(err).NotTo(gomega.HaveOccurred(), c.name)
+			continue
+		}
+		g.Expect(err).To(gomega.HaveOccurred(), c.name)
+		g.Expect(errors.As(err, &ThumbprintError{})).To(
+			gomega.Equal(c.thumbprint),
+			c.name)
+	}
+
+	// Invalid CA.
+	client := soapClient(g, server.URL)
+	err := TLSConfig(
+		client,
+		&core.Secret{
+			Data: map[string][]byte{
+				api.CACert:     []byte("not a certificate"),
+				api.Thumbprint: []byte(thumbprint),
+			},
+		})
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+func TestThumbprint(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	thumbprint := Thumbprint([]byte("certificate"))
+	g.Expect(thumbprint).To(gomega.MatchRegexp("^([0-9A-F]{2}:){19}[0-9A-F]{2}$"))
+	g.Expect(ValidThumbprint(thumbprint)).To(gomega.BeTrue())
+	g.Expect(ValidThumbprint(strings.ToLower(thumbprint))).To(gomega.BeTrue())
+	g.Expect(ValidThumbprint(strings.ReplaceAll(thumbprint, ":", ""))).To(gomega.BeTrue())
+	g.Expect(ValidThumbprint(thumbprint[3:])).To(gomega.BeFalse())
+	g.Expect(ValidThumbprint(strings.Replace(thumbprint, thumbprint[:1], "G", 1))).To(gomega.BeFalse())
+	g.Expect(ValidThumbprint("")).To(gomega.BeFalse())
+}
+
+//
+// Build a (DER encoded) self-signed CA certificate.
+func certificate(g *gomega.GomegaWithT) (der []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "other"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err = x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	return
+}
+
+//
+// Build a SOAP client.
+func soapClient(g *gomega.GomegaWithT, url string) *soap.Client {
+	u, err := liburl.Parse(url)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	return soap.NewClient(u, false)
+}
+
+//
+// Get the URL using the client transport.
+func get(client *soap.Client, url string) (err error) {
+	hc := &http.Client{Transport: client.DefaultTransport()}
+	response, err := hc.Get(url)
+	if err == nil {
+		_ = response.Body.Close()
+	}
+
+	return
+}
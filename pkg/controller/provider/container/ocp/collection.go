@@ -9,10 +9,36 @@ import (
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/ocp"
 	core "k8s.io/api/core/v1"
 	storage "k8s.io/api/storage/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	cdi "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 )
 
+//
+// KubeVirt API.
+// The KubeVirt resources are collected as unstructured.
+const (
+	KubeVirtGroup              = "kubevirt.io"
+	KubeVirtVersion            = "v1alpha3"
+	VirtualMachineKind         = "VirtualMachine"
+	VirtualMachineInstanceKind = "VirtualMachineInstance"
+)
+
+//
+// Optional collection.
+// The kind may not be served by the cluster or may
+// not be listed with the provider token. Registered
+// only when available. See: available().
+type Optional interface {
+	libocp.Collection
+	// Build an empty list of the kind.
+	List() runtime.Object
+}
+
 //
 // StorageClass
 type StorageClass struct {
@@ -321,3 +347,929 @@ func (r *Namespace) Delete(e event.DeleteEvent) bool {
 func (r *Namespace) Generic(e event.GenericEvent) bool {
 	return false
 }
+
+//
+// VM
+type VM struct {
+	libocp.BaseCollection
+}
+
+//
+// Get the kubernetes object being collected.
+func (r *VM) Object() runtime.Object {
+	return kubeVirt(VirtualMachineKind)
+}
+
+//
+// Build an empty list of the kind.
+func (r *VM) List() runtime.Object {
+	return kubeVirtList(VirtualMachineKind)
+}
+
+//
+// Reconcile.
+// Achieve initial consistency.
+func (r *VM) Reconcile(ctx context.Context) (err error) {
+	pClient := r.Reconciler.Client()
+	list := kubeVirtList(VirtualMachineKind)
+	err = pClient.List(context.TODO(), list)
+	if err != nil {
+		if unavailable(err) {
+			Log.Info("Collection not available.", "kind", libref.ToKind(r), "reason", err.Error())
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	db := r.Reconciler.DB()
+	tx, err := db.Begin()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer tx.End()
+	for _, resource := range list.Items {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+		m := &model.VM{}
+		m.With(&resource)
+		r.Reconciler.UpdateThreshold(m)
+		Log.Info("Create", libref.ToKind(m), m.String())
+		err = tx.Insert(m)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
+//
+// Resource created watch event.
+func (r *VM) Create(e event.CreateEvent) bool {
+	object, cast := e.Object.(*unstructured.Unstructured)
+	if !cast || object.GetKind() != VirtualMachineKind {
+		return false
+	}
+	m := &model.VM{}
+	m.With(object)
+	r.Reconciler.Create(m)
+
+	return false
+}
+
+//
+// Resource updated watch event.
+func (r *VM) Update(e event.UpdateEvent) bool {
+	object, cast := e.ObjectNew.(*unstructured.Unstructured)
+	if !cast || object.GetKind() != VirtualMachineKind {
+		return false
+	}
+	m := &model.VM{}
+	m.With(object)
+	r.Reconciler.Update(m)
+
+	return false
+}
+
+//
+// Resource deleted watch event.
+func (r *VM) Delete(e event.DeleteEvent) bool {
+	object, cast := e.Object.(*unstructured.Unstructured)
+	if !cast || object.GetKind() != VirtualMachineKind {
+		return false
+	}
+	m := &model.VM{}
+	m.With(object)
+	r.Reconciler.Delete(m)
+
+	return false
+}
+
+//
+// Ignored.
+func (r *VM) Generic(e event.GenericEvent) bool {
+	return false
+}
+
+//
+// VMI
+type VMI struct {
+	libocp.BaseCollection
+}
+
+//
+// Get the kubernetes object being collected.
+func (r *VMI) Object() runtime.Object {
+	return kubeVirt(VirtualMachineInstanceKind)
+}
+
+//
+// Build an empty list of the kind.
+func (r *VMI) List() runtime.Object {
+	return kubeVirtList(VirtualMachineInstanceKind)
+}
+
+//
+// Reconcile.
+// Achieve initial consistency.
+func (r *VMI) Reconcile(ctx context.Context) (err error) {
+	pClient := r.Reconciler.Client()
+	list := kubeVirtList(VirtualMachineInstanceKind)
+	err = pClient.List(context.TODO(), list)
+	if err != nil {
+		if unavailable(err) {
+			Log.Info("Collection not available.", "kind", libref.ToKind(r), "reason", err.Error())
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	db := r.Reconciler.DB()
+	tx, err := db.Begin()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer tx.End()
+	for _, resource := range list.Items {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+		m := &model.VMI{}
+		m.With(&resource)
+		r.Reconciler.UpdateThreshold(m)
+		Log.Info("Create", libref.ToKind(m), m.String())
+		err = tx.Insert(m)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
+//
+// Resource created watch event.
+func (r *VMI) Create(e event.CreateEvent) bool {
+	object, cast := e.Object.(*unstructured.Unstructured)
+	if !cast || object.GetKind() != VirtualMachineInstanceKind {
+		return false
+	}
+	m := &model.VMI{}
+	m.With(object)
+	r.Reconciler.Create(m)
+
+	return false
+}
+
+//
+// Resource updated watch event.
+func (r *VMI) Update(e event.UpdateEvent) bool {
+	object, cast := e.ObjectNew.(*unstructured.Unstructured)
+	if !cast || object.GetKind() != VirtualMachineInstanceKind {
+		return false
+	}
+	m := &model.VMI{}
+	m.With(object)
+	r.Reconciler.Update(m)
+
+	return false
+}
+
+//
+// Resource deleted watch event.
+func (r *VMI) Delete(e event.DeleteEvent) bool {
+	object, cast := e.Object.(*unstructured.Unstructured)
+	if !cast || object.GetKind() != VirtualMachineInstanceKind {
+		return false
+	}
+	m := &model.VMI{}
+	m.With(object)
+	r.Reconciler.Delete(m)
+
+	return false
+}
+
+//
+// Ignored.
+func (r *VMI) Generic(e event.GenericEvent) bool {
+	return false
+}
+
+//
+// PersistentVolumeClaim
+type PersistentVolumeClaim struct {
+	libocp.BaseCollection
+}
+
+//
+// Get the kubernetes object being collected.
+func (r *PersistentVolumeClaim) Object() runtime.Object {
+	return &core.PersistentVolumeClaim{}
+}
+
+//
+// Reconcile.
+// Achieve initial consistency.
+func (r *PersistentVolumeClaim) Reconcile(ctx context.Context) (err error) {
+	pClient := r.Reconciler.Client()
+	list := &core.PersistentVolumeClaimList{}
+	err = pClient.List(context.TODO(), list)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	db := r.Reconciler.DB()
+	tx, err := db.Begin()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer tx.End()
+	for _, resource := range list.Items {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+		m := &model.PersistentVolumeClaim{}
+		m.With(&resource)
+		r.Reconciler.UpdateThreshold(m)
+		Log.Info("Create", libref.ToKind(m), m.String())
+		err = tx.Insert(m)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
+//
+// Resource created watch event.
+func (r *PersistentVolumeClaim) Create(e event.CreateEvent) bool {
+	object, cast := e.Object.(*core.PersistentVolumeClaim)
+	if !cast {
+		return false
+	}
+	m := &model.PersistentVolumeClaim{}
+	m.With(object)
+	r.Reconciler.Create(m)
+
+	return false
+}
+
+//
+// Resource updated watch event.
+func (r *PersistentVolumeClaim) Update(e event.UpdateEvent) bool {
+	object, cast := e.ObjectNew.(*core.PersistentVolumeClaim)
+	if !cast {
+		return false
+	}
+	m := &model.PersistentVolumeClaim{}
+	m.With(object)
+	r.Reconciler.Update(m)
+
+	return false
+}
+
+//
+// Resource deleted watch event.
+func (r *PersistentVolumeClaim) Delete(e event.DeleteEvent) bool {
+	object, cast := e.Object.(*core.PersistentVolumeClaim)
+	if !cast {
+		return false
+	}
+	m := &model.PersistentVolumeClaim{}
+	m.With(object)
+	r.Reconciler.Delete(m)
+
+	return false
+}
+
+//
+// Ignored.
+func (r *PersistentVolumeClaim) Generic(e event.GenericEvent) bool {
+	return false
+}
+
+//
+// PersistentVolume
+type PersistentVolume struct {
+	libocp.BaseCollection
+}
+
+//
+// Get the kubernetes object being collected.
+func (r *PersistentVolume) Object() runtime.Object {
+	return &core.PersistentVolume{}
+}
+
+//
+// Reconcile.
+// Achieve initial consistency.
+func (r *PersistentVolume) Reconcile(ctx context.Context) (err error) {
+	pClient := r.Reconciler.Client()
+	list := &core.PersistentVolumeList{}
+	err = pClient.List(context.TODO(), list)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	db := r.Reconciler.DB()
+	tx, err := db.Begin()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer tx.End()
+	for _, resource := range list.Items {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+		m := &model.PersistentVolume{}
+		m.With(&resource)
+		r.Reconciler.UpdateThreshold(m)
+		Log.Info("Create", libref.ToKind(m), m.String())
+		err = tx.Insert(m)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
+//
+// Resource created watch event.
+func (r *PersistentVolume) Create(e event.CreateEvent) bool {
+	object, cast := e.Object.(*core.PersistentVolume)
+	if !cast {
+		return false
+	}
+	m := &model.PersistentVolume{}
+	m.With(object)
+	r.Reconciler.Create(m)
+
+	return false
+}
+
+//
+// Resource updated watch event.
+func (r *PersistentVolume) Update(e event.UpdateEvent) bool {
+	object, cast := e.ObjectNew.(*core.PersistentVolume)
+	if !cast {
+		return false
+	}
+	m := &model.PersistentVolume{}
+	m.With(object)
+	r.Reconciler.Update(m)
+
+	return false
+}
+
+//
+// Resource deleted watch event.
+func (r *PersistentVolume) Delete(e event.DeleteEvent) bool {
+	object, cast := e.Object.(*core.PersistentVolume)
+	if !cast {
+		return false
+	}
+	m := &model.PersistentVolume{}
+	m.With(object)
+	r.Reconciler.Delete(m)
+
+	return false
+}
+
+//
+// Ignored.
+func (r *PersistentVolume) Generic(e event.GenericEvent) bool {
+	return false
+}
+
+//
+// DataVolume
+type DataVolume struct {
+	libocp.BaseCollection
+}
+
+//
+// Get the kubernetes object being collected.
+func (r *DataVolume) Object() runtime.Object {
+	return &cdi.DataVolume{}
+}
+
+//
+// Build an empty list of the kind.
+func (r *DataVolume) List() runtime.Object {
+	return &cdi.DataVolumeList{}
+}
+
+//
+// Reconcile.
+// Achieve initial consistency.
+func (r *DataVolume) Reconcile(ctx context.Context) (err error) {
+	pClient := r.Reconciler.Client()
+	list := &cdi.DataVolumeList{}
+	err = pClient.List(context.TODO(), list)
+	if err != nil {
+		if unavailable(err) {
+			Log.Info("Collection not available.", "kind", libref.ToKind(r), "reason", err.Error())
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	db := r.Reconciler.DB()
+	tx, err := db.Begin()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer tx.End()
+	for _, resource := range list.Items {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+		m := &model.DataVolume{}
+		m.With(&resource)
+		r.Reconciler.UpdateThreshold(m)
+		Log.Info("Create", libref.ToKind(m), m.String())
+		err = tx.Insert(m)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
+//
+// Resource created watch event.
+func (r *DataVolume) Create(e event.CreateEvent) bool {
+	object, cast := e.Object.(*cdi.DataVolume)
+	if !cast {
+		return false
+	}
+	m := &model.DataVolume{}
+	m.With(object)
+	r.Reconciler.Create(m)
+
+	return false
+}
+
+//
+// Resource updated watch event.
+func (r *DataVolume) Update(e event.UpdateEvent) bool {
+	object, cast := e.ObjectNew.(*cdi.DataVolume)
+	if !cast {
+		return false
+	}
+	m := &model.DataVolume{}
+	m.With(object)
+	r.Reconciler.Update(m)
+
+	return false
+}
+
+//
+// Resource deleted watch event.
+func (r *DataVolume) Delete(e event.DeleteEvent) bool {
+	object, cast := e.Object.(*cdi.DataVolume)
+	if !cast {
+		return false
+	}
+	m := &model.DataVolume{}
+	m.With(object)
+	r.Reconciler.Delete(m)
+
+	return false
+}
+
+//
+// Ignored.
+func (r *DataVolume) Generic(e event.GenericEvent) bool {
+	return false
+}
+
+//
+// Node
+type Node struct {
+	libocp.BaseCollection
+}
+
+//
+// Get the kubernetes object being collected.
+func (r *Node) Object() runtime.Object {
+	return &core.Node{}
+}
+
+//
+// Build an empty list of the kind.
+func (r *Node) List() runtime.Object {
+	return &core.NodeList{}
+}
+
+//
+// Reconcile.
+// Achieve initial consistency.
+func (r *Node) Reconcile(ctx context.Context) (err error) {
+	pClient := r.Reconciler.Client()
+	list := &core.NodeList{}
+	err = pClient.List(context.TODO(), list)
+	if err != nil {
+		if unavailable(err) {
+			Log.Info("Collection not available.", "kind", libref.ToKind(r), "reason", err.Error())
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	db := r.Reconciler.DB()
+	tx, err := db.Begin()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer tx.End()
+	for _, resource := range list.Items {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+		m := &model.Node{}
+		m.With(&resource)
+		r.Reconciler.UpdateThreshold(m)
+		Log.Info("Create", libref.ToKind(m), m.String())
+		err = tx.Insert(m)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
+//
+// Resource created watch event.
+func (r *Node) Create(e event.CreateEvent) bool {
+	object, cast := e.Object.(*core.Node)
+	if !cast {
+		return false
+	}
+	m := &model.Node{}
+	m.With(object)
+	r.Reconciler.Create(m)
+
+	return false
+}
+
+//
+// Resource updated watch event.
+func (r *Node) Update(e event.UpdateEvent) bool {
+	object, cast := e.ObjectNew.(*core.Node)
+	if !cast {
+		return false
+	}
+	m := &model.Node{}
+	m.With(object)
+	r.Reconciler.Update(m)
+
+	return false
+}
+
+//
+// Resource deleted watch event.
+func (r *Node) Delete(e event.DeleteEvent) bool {
+	object, cast := e.Object.(*core.Node)
+	if !cast {
+		return false
+	}
+	m := &model.Node{}
+	m.With(object)
+	r.Reconciler.Delete(m)
+
+	return false
+}
+
+//
+// Ignored.
+func (r *Node) Generic(e event.GenericEvent) bool {
+	return false
+}
+
+//
+// ResourceQuota
+type ResourceQuota struct {
+	libocp.BaseCollection
+}
+
+//
+// Get the kubernetes object being collected.
+func (r *ResourceQuota) Object() runtime.Object {
+	return &core.ResourceQuota{}
+}
+
+//
+// Build an empty list of the kind.
+func (r *ResourceQuota) List() runtime.Object {
+	return &core.ResourceQuotaList{}
+}
+
+//
+// Reconcile.
+// Achieve initial consistency.
+func (r *ResourceQuota) Reconcile(ctx context.Context) (err error) {
+	pClient := r.Reconciler.Client()
+	list := &core.ResourceQuotaList{}
+	err = pClient.List(context.TODO(), list)
+	if err != nil {
+		if unavailable(err) {
+			Log.Info("Collection not available.", "kind", libref.ToKind(r), "reason", err.Error())
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	db := r.Reconciler.DB()
+	tx, err := db.Begin()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer tx.End()
+	for _, resource := range list.Items {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+		m := &model.ResourceQuota{}
+		m.With(&resource)
+		r.Reconciler.UpdateThreshold(m)
+		Log.Info("Create", libref.ToKind(m), m.String())
+		err = tx.Insert(m)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
+//
+// Resource created watch event.
+func (r *ResourceQuota) Create(e event.CreateEvent) bool {
+	object, cast := e.Object.(*core.ResourceQuota)
+	if !cast {
+		return false
+	}
+	m := &model.ResourceQuota{}
+	m.With(object)
+	r.Reconciler.Create(m)
+
+	return false
+}
+
+//
+// Resource updated watch event.
+func (r *ResourceQuota) Update(e event.UpdateEvent) bool {
+	object, cast := e.ObjectNew.(*core.ResourceQuota)
+	if !cast {
+		return false
+	}
+	m := &model.ResourceQuota{}
+	m.With(object)
+	r.Reconciler.Update(m)
+
+	return false
+}
+
+//
+// Resource deleted watch event.
+func (r *ResourceQuota) Delete(e event.DeleteEvent) bool {
+	object, cast := e.Object.(*core.ResourceQuota)
+	if !cast {
+		return false
+	}
+	m := &model.ResourceQuota{}
+	m.With(object)
+	r.Reconciler.Delete(m)
+
+	return false
+}
+
+//
+// Ignored.
+func (r *ResourceQuota) Generic(e event.GenericEvent) bool {
+	return false
+}
+
+//
+// LimitRange
+type LimitRange struct {
+	libocp.BaseCollection
+}
+
+//
+// Get the kubernetes object being collected.
+func (r *LimitRange) Object() runtime.Object {
+	return &core.LimitRange{}
+}
+
+//
+// Build an empty list of the kind.
+func (r *LimitRange) List() runtime.Object {
+	return &core.LimitRangeList{}
+}
+
+//
+// Reconcile.
+// Achieve initial consistency.
+func (r *LimitRange) Reconcile(ctx context.Context) (err error) {
+	pClient := r.Reconciler.Client()
+	list := &core.LimitRangeList{}
+	err = pClient.List(context.TODO(), list)
+	if err != nil {
+		if unavailable(err) {
+			Log.Info("Collection not available.", "kind", libref.ToKind(r), "reason", err.Error())
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	db := r.Reconciler.DB()
+	tx, err := db.Begin()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer tx.End()
+	for _, resource := range list.Items {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+		m := &model.LimitRange{}
+		m.With(&resource)
+		r.Reconciler.UpdateThreshold(m)
+		Log.Info("Create", libref.ToKind(m), m.String())
+		err = tx.Insert(m)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
+//
+// Resource created watch event.
+func (r *LimitRange) Create(e event.CreateEvent) bool {
+	object, cast := e.Object.(*core.LimitRange)
+	if !cast {
+		return false
+	}
+	m := &model.LimitRange{}
+	m.With(object)
+	r.Reconciler.Create(m)
+
+	return false
+}
+
+//
+// Resource updated watch event.
+func (r *LimitRange) Update(e event.UpdateEvent) bool {
+	object, cast := e.ObjectNew.(*core.LimitRange)
+	if !cast {
+		return false
+	}
+	m := &model.LimitRange{}
+	m.With(object)
+	r.Reconciler.Update(m)
+
+	return false
+}
+
+//
+// Resource deleted watch event.
+func (r *LimitRange) Delete(e event.DeleteEvent) bool {
+	object, cast := e.Object.(*core.LimitRange)
+	if !cast {
+		return false
+	}
+	m := &model.LimitRange{}
+	m.With(object)
+	r.Reconciler.Delete(m)
+
+	return false
+}
+
+//
+// Ignored.
+func (r *LimitRange) Generic(e event.GenericEvent) bool {
+	return false
+}
+
+//
+// Build an (unstructured) KubeVirt resource.
+func kubeVirt(kind string) (object *unstructured.Unstructured) {
+	object = &unstructured.Unstructured{}
+	object.SetGroupVersionKind(
+		schema.GroupVersionKind{
+			Group:   KubeVirtGroup,
+			Version: KubeVirtVersion,
+			Kind:    kind,
+		})
+
+	return
+}
+
+//
+// Build an (unstructured) KubeVirt resource list.
+func kubeVirtList(kind string) (list *unstructured.UnstructuredList) {
+	list = &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(
+		schema.GroupVersionKind{
+			Group:   KubeVirtGroup,
+			Version: KubeVirtVersion,
+			Kind:    kind + "List",
+		})
+
+	return
+}
+
+//
+// The kind is not served by the cluster or may not
+// be listed with the provider token. The collection
+// is treated as empty.
+func unavailable(err error) bool {
+	return meta.IsNoMatchError(err) || k8serr.IsForbidden(err)
+}
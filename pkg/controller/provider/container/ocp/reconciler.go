@@ -1,36 +1,134 @@
 package ocp
 
 import (
+	"context"
+	liberr "github.com/konveyor/controller/pkg/error"
 	libcontainer "github.com/konveyor/controller/pkg/inventory/container"
 	libocp "github.com/konveyor/controller/pkg/inventory/container/ocp"
 	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	libref "github.com/konveyor/controller/pkg/ref"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
 	core "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
+)
+
+//
+// Connection test timeout.
+const (
+	TestTimeout = 10 * time.Second
 )
 
 //
 // New reconciler.
+// The KubeVirt, CDI, node, quota and limit range collections
+// are optional and registered only when available.
 func New(db libmodel.DB, provider *api.Provider, secret *core.Secret) libcontainer.Reconciler {
+	collections := []libocp.Collection{
+		&Namespace{},
+		&NetworkAttachmentDefinition{},
+		&StorageClass{},
+		&PersistentVolumeClaim{},
+		&PersistentVolume{},
+	}
+	collections = append(
+		collections,
+		available(
+			provider,
+			secret,
+			&VM{},
+			&VMI{},
+			&DataVolume{},
+			&Node{},
+			&ResourceQuota{},
+			&LimitRange{})...)
 	return &Reconciler{
 		Reconciler: libocp.New(
 			db,
 			provider,
 			secret,
-			&Namespace{},
-			&NetworkAttachmentDefinition{},
-			&StorageClass{}),
+			collections...),
+		provider: provider,
+		secret:   secret,
 	}
 }
 
+//
+// Determine the available (optional) collections.
+// The kind must be served by the cluster (discovery) and
+// listed with the provider token. The collections are not
+// available when the cluster cannot be reached.
+func available(provider *api.Provider, secret *core.Secret, optional ...Optional) (list []libocp.Collection) {
+	cfg, err := provider.BuildRestCfg(secret)
+	if err != nil {
+		Log.Trace(err)
+		return
+	}
+	cfg.Timeout = TestTimeout
+	pClient, err := client.New(
+		cfg,
+		client.Options{
+			Scheme: scheme.Scheme,
+		})
+	if err != nil {
+		Log.Trace(err)
+		return
+	}
+	for _, collection := range optional {
+		ctx, cancel := context.WithTimeout(context.Background(), TestTimeout)
+		err = pClient.List(ctx, collection.List(), client.Limit(1))
+		cancel()
+		if err != nil {
+			Log.Info(
+				"Collection not available.",
+				"kind",
+				libref.ToKind(collection),
+				"reason",
+				err.Error())
+			continue
+		}
+		list = append(list, collection)
+	}
+
+	return
+}
+
 //
 // OCP reconciler.
 type Reconciler struct {
 	*libocp.Reconciler
+	// The provider.
+	provider *api.Provider
+	// The provider secret.
+	secret *core.Secret
 }
 
 //
 // Test api-server URL and token.
-func (r *Reconciler) Test() error {
+// The server certificate is verified as configured
+// by the provider secret.
+func (r *Reconciler) Test() (err error) {
 	// TODO: SAR check the token has access to kubevirt.
-	return nil
+	cfg, err := r.provider.BuildRestCfg(r.secret)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	cfg.Timeout = TestTimeout
+	pClient, err := client.New(
+		cfg,
+		client.Options{})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), TestTimeout)
+	defer cancel()
+	err = pClient.List(ctx, &core.NamespaceList{}, client.Limit(1))
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
 }
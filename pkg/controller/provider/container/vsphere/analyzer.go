@@ -0,0 +1,268 @@
+package vsphere
+
+import (
+	"context"
+	"errors"
+	liberr "github.com/konveyor/controller/pkg/error"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/analysis"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"github.com/konveyor/forklift-controller/pkg/settings"
+	"time"
+)
+
+//
+// Application settings.
+var Settings = &settings.Settings
+
+//
+// VM analyzer.
+// Reports migration concerns for a VM.
+type Analyzer interface {
+	// Analyze the VM.
+	Analyze(db libmodel.DB, vm *model.VM) ([]model.Concern, error)
+}
+
+//
+// Build the VM analyzers.
+func analyzers(rules analysis.RuleSource) []Analyzer {
+	return []Analyzer{
+		&BuiltinAnalyzer{},
+		&RuleAnalyzer{
+			Rules: rules,
+		},
+	}
+}
+
+//
+// Built-in analyzer.
+// Reports concerns for features known to be
+// unsupported by the migration.
+type BuiltinAnalyzer struct {
+}
+
+//
+// Analyze the VM.
+func (r *BuiltinAnalyzer) Analyze(db libmodel.DB, vm *model.VM) (list []model.Concern, err error) {
+	list = r.firmwareConcerns(vm)
+	list = append(list, r.diskConcerns(vm)...)
+	return
+}
+
+//
+// Concerns for firmware (and related) features that
+// cannot be reproduced by KubeVirt.
+func (r *BuiltinAnalyzer) firmwareConcerns(vm *model.VM) (list []model.Concern) {
+	list = []model.Concern{}
+	if vm.TpmEnabled {
+		list = append(
+			list,
+			model.Concern{
+				Name:     "vTPM not supported; guests depending on it may not boot.",
+				Severity: model.Warning,
+			})
+	}
+	if vm.Encrypted {
+		list = append(
+			list,
+			model.Concern{
+				Name:     "Encrypted VM not supported.",
+				Severity: model.Critical,
+			})
+	}
+
+	return
+}
+
+//
+// Concerns for disks that cannot be migrated
+// as-is by the VMIO.
+func (r *BuiltinAnalyzer) diskConcerns(vm *model.VM) (list []model.Concern) {
+	list = []model.Concern{}
+	rdm := false
+	shared := false
+	for _, disk := range vm.Disks {
+		if disk.RDM {
+			rdm = true
+		}
+		if disk.Shared {
+			shared = true
+		}
+	}
+	if rdm {
+		list = append(
+			list,
+			model.Concern{
+				Name:     "RDM disk not supported.",
+				Severity: model.Critical,
+			})
+	}
+	if shared {
+		list = append(
+			list,
+			model.Concern{
+				Name:     "Shared (multi-writer) disk; requires shared disk mapping.",
+				Severity: model.Critical,
+			})
+	}
+
+	return
+}
+
+//
+// Rule analyzer.
+// Evaluates the configured rule set. Rule expressions
+// reference the `vm`, `host` and `cluster` models.
+// Example:
+//   rules:
+//   - name: CPU affinity not supported.
+//     severity: Warning
+//     when: len(vm.cpuAffinity) > 0
+//   - name: Host in maintenance mode.
+//     severity: Advisory
+//     when: host.inMaintenanceMode
+type RuleAnalyzer struct {
+	// Rule set source.
+	Rules analysis.RuleSource
+}
+
+//
+// Analyze the VM.
+// Rules that fail evaluation are logged and skipped.
+func (r *RuleAnalyzer) Analyze(db libmodel.DB, vm *model.VM) (list []model.Concern, err error) {
+	list = []model.Concern{}
+	ruleSet, err := r.Rules.RuleSet()
+	if err != nil {
+		return
+	}
+	if len(ruleSet.Rules) == 0 {
+		return
+	}
+	env, err := r.env(db, vm)
+	if err != nil {
+		return
+	}
+	matched, errList := ruleSet.Eval(env)
+	for _, rErr := range errList {
+		Log.Trace(rErr)
+	}
+	for _, rule := range matched {
+		list = append(
+			list,
+			model.Concern{
+				Name:     rule.Name,
+				Severity: rule.Severity,
+			})
+	}
+
+	return
+}
+
+//
+// Build the evaluation env.
+// The host and cluster are omitted when not found.
+func (r *RuleAnalyzer) env(db libmodel.DB, vm *model.VM) (env analysis.Env, err error) {
+	env = analysis.Env{}
+	env["vm"], err = analysis.Object(vm)
+	if err != nil {
+		return
+	}
+	host := &model.Host{}
+	host.WithRef(vm.Host)
+	err = db.Get(host)
+	if err == nil {
+		env["host"], err = analysis.Object(host)
+		if err != nil {
+			return
+		}
+	} else {
+		if errors.Is(err, model.NotFound) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	cluster, err := vm.Cluster(db)
+	if err == nil {
+		env["cluster"], err = analysis.Object(cluster)
+		if err != nil {
+			return
+		}
+	} else {
+		if errors.Is(err, model.NotFound) {
+			err = nil
+		} else {
+			return
+		}
+	}
+
+	return
+}
+
+//
+// Periodically check the analysis rule set revision.
+// All VMs are (re)analyzed when the rule set is (re)loaded.
+func (r *Reconciler) watchRules(ctx context.Context) {
+	revision, err := r.rules.Revision()
+	if err != nil {
+		r.log.Trace(err)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(RuleCheckInterval):
+		}
+		current, err := r.rules.Revision()
+		if err != nil {
+			r.log.Trace(err)
+			continue
+		}
+		if current == revision {
+			continue
+		}
+		err = r.reanalyze()
+		if err != nil {
+			r.log.Trace(err)
+			continue
+		}
+		revision = current
+	}
+}
+
+//
+// Mark all VMs to be (re)analyzed.
+// See: WatchVM.
+func (r *Reconciler) reanalyze() (err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer tx.End()
+	list := []model.VM{}
+	err = tx.List(&list, libmodel.ListOptions{})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range list {
+		vm := &list[i]
+		vm.RevisionAnalyzed = 0
+		err = tx.Update(vm)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	r.log.Info("Analysis rules loaded.", "vms", len(list))
+
+	return
+}
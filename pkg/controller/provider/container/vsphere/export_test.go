@@ -0,0 +1,42 @@
+package vsphere
+
+import (
+	"context"
+	"github.com/vmware/govmomi/vim25"
+	"time"
+)
+
+//
+// Collect performance statistics.
+// Exported for tests.
+func (r *Reconciler) CollectPerf(ctx context.Context, client *vim25.Client, interval time.Duration) error {
+	return r.collectPerf(ctx, client, interval)
+}
+
+//
+// Resolve the scope paths.
+// Exported for tests.
+func (r *Scope) Resolve(ctx context.Context, client *vim25.Client) error {
+	return r.resolve(ctx, client)
+}
+
+//
+// Refresh tags.
+// Exported for tests.
+func (r *Reconciler) RefreshTags(ctx context.Context, client *vim25.Client) error {
+	return r.refreshTags(ctx, client)
+}
+
+//
+// Set the health (serving the cached inventory).
+// Exported for tests.
+func (r *Reconciler) Stale(stale bool) {
+	r.health.stale(stale)
+}
+
+//
+// Set the health (failed).
+// Exported for tests.
+func (r *Reconciler) Failed(err error) {
+	r.health.failed(err)
+}
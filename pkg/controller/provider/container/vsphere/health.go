@@ -0,0 +1,146 @@
+package vsphere
+
+import (
+	inventory "github.com/konveyor/forklift-controller/pkg/controller/provider/model"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+//
+// Collector health.
+type Health struct {
+	inventory.Health
+	// Protect the health.
+	mutex sync.Mutex
+	// Called when the reported health has changed.
+	changed func()
+}
+
+//
+// Get a copy of the health.
+func (r *Health) Get() (h inventory.Health) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	h = r.Health
+	return
+}
+
+//
+// Connected.
+func (r *Health) connected() {
+	r.update(func(h *inventory.Health) {
+		h.Connected = true
+		h.NextRetry = nil
+	})
+}
+
+//
+// Disconnected.
+func (r *Health) disconnected() {
+	r.update(func(h *inventory.Health) {
+		h.Connected = false
+	})
+}
+
+//
+// Serving the cached inventory.
+func (r *Health) stale(stale bool) {
+	r.update(func(h *inventory.Health) {
+		h.Stale = stale
+	})
+}
+
+//
+// Scope paths not found.
+func (r *Health) scope(notFound []string) {
+	r.update(func(h *inventory.Health) {
+		h.ScopeNotFound = notFound
+	})
+}
+
+//
+// Updates applied.
+// Resets consecutive failures.
+func (r *Health) updated() {
+	r.update(func(h *inventory.Health) {
+		now := time.Now()
+		h.Connected = true
+		h.LastUpdate = &now
+		h.Failures = 0
+	})
+}
+
+//
+// Failed.
+// Returns the delay before the next retry.
+func (r *Health) failed(err error) (delay time.Duration) {
+	r.update(func(h *inventory.Health) {
+		now := time.Now()
+		h.Connected = false
+		h.LastError = err.Error()
+		h.LastErrorTime = &now
+		h.Failures++
+		delay = backoff(h.Failures)
+		next := now.Add(delay)
+		h.NextRetry = &next
+	})
+
+	return
+}
+
+//
+// Update the health.
+// Calls changed() when the reported health has changed.
+func (r *Health) update(fn func(h *inventory.Health)) {
+	r.mutex.Lock()
+	before := r.reported()
+	fn(&r.Health)
+	changed := r.reported() != before
+	r.mutex.Unlock()
+	if changed && r.changed != nil {
+		r.changed()
+	}
+}
+
+//
+// The health reported by the provider conditions.
+// See: provider collectorHealth().
+func (r *Health) reported() (reported reportedHealth) {
+	reported.stale = r.Stale
+	reported.scope = strings.Join(r.ScopeNotFound, ",")
+	if !r.Connected && r.Failures > 0 {
+		reported.notConnected = r.LastError
+	}
+
+	return
+}
+
+//
+// Health reported by the provider conditions.
+type reportedHealth struct {
+	// Serving the cached inventory.
+	stale bool
+	// Scope paths not found.
+	scope string
+	// Not connected (the last error).
+	notConnected string
+}
+
+//
+// Exponential backoff with jitter.
+// The delay is RetryDelay doubled for each consecutive failure
+// (capped at MaxRetryDelay) reduced by up to half at random.
+func backoff(failures int) (delay time.Duration) {
+	delay = RetryDelay
+	for n := 1; n < failures && delay < MaxRetryDelay; n++ {
+		delay *= 2
+	}
+	if delay > MaxRetryDelay {
+		delay = MaxRetryDelay
+	}
+	delay -= time.Duration(rand.Int63n(int64(delay / 2)))
+
+	return
+}
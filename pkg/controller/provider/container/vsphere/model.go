@@ -5,6 +5,7 @@ import (
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
 	"github.com/vmware/govmomi/vim25/types"
 	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -21,6 +22,9 @@ type Adapter interface {
 //
 // Base adapter.
 type Base struct {
+	// Custom field definitions.
+	// Maps field key to name.
+	fields map[int32]string
 }
 
 //
@@ -54,13 +58,19 @@ func (b *Base) Ref(in types.AnyType) (ref model.Ref) {
 		case Cluster:
 			ref.Kind = model.ClusterKind
 		case Network,
-			DVPortGroup,
-			DVSwitch:
+			DVPortGroup:
 			ref.Kind = model.NetKind
+		case DVSwitch,
+			DVSwitchBase:
+			ref.Kind = model.DVSwitchKind
 		case Datastore:
 			ref.Kind = model.DsKind
 		case Host:
 			ref.Kind = model.HostKind
+		case ResourcePool:
+			ref.Kind = model.PoolKind
+		case VirtualApp:
+			ref.Kind = model.VAppKind
 		case VirtualMachine:
 			ref.Kind = model.VmKind
 		default:
@@ -83,6 +93,47 @@ func (b *Base) RefList(in types.AnyType) (list []model.Ref) {
 	return
 }
 
+//
+// Build the allocation.
+func (b *Base) Allocation(in types.ResourceAllocationInfo) (a model.Allocation) {
+	a.Limit = -1
+	if in.Reservation != nil {
+		a.Reservation = *in.Reservation
+	}
+	if in.Limit != nil {
+		a.Limit = *in.Limit
+	}
+	if in.ExpandableReservation != nil {
+		a.Expandable = *in.ExpandableReservation
+	}
+	if in.Shares != nil {
+		a.Shares = in.Shares.Shares
+		a.SharesLevel = string(in.Shares.Level)
+	}
+
+	return
+}
+
+//
+// Build custom attributes.
+// Values for undefined fields are keyed by the field key.
+func (b *Base) CustomAttributes(in types.AnyType) (attributes model.Attributes) {
+	attributes = model.Attributes{}
+	if a, cast := in.(types.ArrayOfCustomFieldValue); cast {
+		for _, v := range a.CustomFieldValue {
+			if sv, cast := v.(*types.CustomFieldStringValue); cast {
+				name, found := b.fields[sv.Key]
+				if !found {
+					name = strconv.Itoa(int(sv.Key))
+				}
+				attributes[name] = sv.Value
+			}
+		}
+	}
+
+	return
+}
+
 //
 // Folder model adapter.
 type FolderAdapter struct {
@@ -197,6 +248,13 @@ func (v *ClusterAdapter) Apply(u types.ObjectUpdate) {
 					}
 				}
 				v.model.DrsVms = refList
+			case fConfigEx:
+				if cfg, cast := p.Val.(types.ClusterConfigInfoEx); cast {
+					v.model.DrsRules = v.drsRules(cfg.Rule)
+					v.model.VmGroups = v.vmGroups(cfg.Group)
+				}
+			case fResourcePool:
+				v.model.ResourcePool = v.Ref(p.Val)
 			case fDrsVmBehavior:
 				if b, cast := p.Val.(types.DrsBehavior); cast {
 					v.model.DrsBehavior = string(b)
@@ -206,6 +264,71 @@ func (v *ClusterAdapter) Apply(u types.ObjectUpdate) {
 	}
 }
 
+//
+// Build DRS rules.
+func (v *ClusterAdapter) drsRules(in []types.BaseClusterRuleInfo) (list []model.DrsRule) {
+	list = []model.DrsRule{}
+	for _, r := range in {
+		info := r.GetClusterRuleInfo()
+		rule := model.DrsRule{
+			Name:      info.Name,
+			Enabled:   info.Enabled != nil && *info.Enabled,
+			Mandatory: info.Mandatory != nil && *info.Mandatory,
+		}
+		switch r := r.(type) {
+		case *types.ClusterAffinityRuleSpec:
+			rule.Kind = model.AffinityRule
+			rule.Vms = v.vmRefs(r.Vm)
+		case *types.ClusterAntiAffinityRuleSpec:
+			rule.Kind = model.AntiAffinityRule
+			rule.Vms = v.vmRefs(r.Vm)
+		case *types.ClusterVmHostRuleInfo:
+			rule.Kind = model.VmHostRule
+			rule.VmGroup = r.VmGroupName
+			rule.AffineHostGroup = r.AffineHostGroupName
+			rule.AntiAffineHostGroup = r.AntiAffineHostGroupName
+		case *types.ClusterDependencyRuleInfo:
+			rule.Kind = model.DependencyRule
+			rule.VmGroup = r.VmGroup
+			rule.DependsOnVmGroup = r.DependsOnVmGroup
+		default:
+			continue
+		}
+		list = append(list, rule)
+	}
+
+	return
+}
+
+//
+// Build VM references.
+func (v *ClusterAdapter) vmRefs(in []types.ManagedObjectReference) (list []model.Ref) {
+	list = []model.Ref{}
+	for _, ref := range in {
+		list = append(list, v.Ref(ref))
+	}
+
+	return
+}
+
+//
+// Build DRS VM groups.
+func (v *ClusterAdapter) vmGroups(in []types.BaseClusterGroupInfo) (list []model.VmGroup) {
+	list = []model.VmGroup{}
+	for _, g := range in {
+		if group, cast := g.(*types.ClusterVmGroup); cast {
+			list = append(
+				list,
+				model.VmGroup{
+					Name: group.Name,
+					Vms:  v.vmRefs(group.Vm),
+				})
+		}
+	}
+
+	return
+}
+
 //
 // Host model adapter.
 type HostAdapter struct {
@@ -230,6 +353,8 @@ func (v *HostAdapter) Apply(u types.ObjectUpdate) {
 				if b, cast := p.Val.(bool); cast {
 					v.model.InMaintenanceMode = b
 				}
+			case fCustomValue:
+				v.model.CustomAttributes = v.CustomAttributes(p.Val)
 			case fThumbprint:
 				if s, cast := p.Val.(string); cast {
 					v.model.Thumbprint = s
@@ -242,6 +367,10 @@ func (v *HostAdapter) Apply(u types.ObjectUpdate) {
 				if b, cast := p.Val.(int16); cast {
 					v.model.CpuCores = b
 				}
+			case fCpuMhz:
+				if n, cast := p.Val.(int32); cast {
+					v.model.CpuMhz = n
+				}
 			case fVm:
 				v.model.Vms = v.RefList(p.Val)
 			case fProductName:
@@ -280,6 +409,7 @@ func (v *HostAdapter) Apply(u types.ObjectUpdate) {
 								Key:    portGroup.Key,
 								Name:   portGroup.Spec.Name,
 								Switch: portGroup.Vswitch,
+								VlanID: portGroup.Spec.VlanId,
 							})
 					}
 				}
@@ -364,11 +494,43 @@ func (v *NetworkAdapter) Apply(u types.ObjectUpdate) {
 				}
 			case fDVSwitch:
 				v.model.DVSwitch = v.Ref(p.Val)
+			case fDefaultPortCfg:
+				if setting, cast := p.Val.(types.VMwareDVSPortSetting); cast {
+					v.model.VLAN = v.vlan(setting.Vlan)
+				}
 			}
 		}
 	}
 }
 
+//
+// Build the VLAN configuration.
+func (v *NetworkAdapter) vlan(spec types.BaseVmwareDistributedVirtualSwitchVlanSpec) (vlan model.VLAN) {
+	vlan.Type = model.VlanNone
+	switch spec := spec.(type) {
+	case *types.VmwareDistributedVirtualSwitchVlanIdSpec:
+		if spec.VlanId > 0 {
+			vlan.Type = model.VlanID
+			vlan.ID = spec.VlanId
+		}
+	case *types.VmwareDistributedVirtualSwitchTrunkVlanSpec:
+		vlan.Type = model.VlanTrunk
+		for _, r := range spec.VlanId {
+			vlan.Ranges = append(
+				vlan.Ranges,
+				model.VLANRange{
+					Start: r.Start,
+					End:   r.End,
+				})
+		}
+	case *types.VmwareDistributedVirtualSwitchPvlanSpec:
+		vlan.Type = model.VlanPVLAN
+		vlan.ID = spec.PvlanId
+	}
+
+	return
+}
+
 //
 // DVSwitch model adapter.
 type DVSwitchAdapter struct {
@@ -395,6 +557,16 @@ func (v *DVSwitchAdapter) Apply(u types.ObjectUpdate) {
 				if array, cast := p.Val.(types.ArrayOfDistributedVirtualSwitchHostMember); cast {
 					v.addHost(array)
 				}
+			case fDVSUplinks:
+				if policy, cast := p.Val.(types.DVSNameArrayUplinkPortPolicy); cast {
+					v.model.Uplinks = policy.UplinkPortName
+				}
+			case fDVSMtu:
+				if n, cast := p.Val.(int32); cast {
+					v.model.MTU = n
+				}
+			case fDVSPortGroup:
+				v.model.PortGroups = v.RefList(p.Val)
 			}
 		}
 	}
@@ -422,6 +594,74 @@ func (v *DVSwitchAdapter) addHost(array types.ArrayOfDistributedVirtualSwitchHos
 	v.model.Host = list
 }
 
+//
+// Resource pool model adapter.
+type ResourcePoolAdapter struct {
+	Base
+	// The adapter model.
+	model model.ResourcePool
+}
+
+//
+// The adapter model.
+func (v *ResourcePoolAdapter) Model() model.Model {
+	return &v.model
+}
+
+//
+// Apply the update to the model.
+func (v *ResourcePoolAdapter) Apply(u types.ObjectUpdate) {
+	v.Base.Apply(&v.model.Base, u)
+	v.applyPool(&v.model, u)
+}
+
+//
+// Apply the update to the pool.
+func (v *ResourcePoolAdapter) applyPool(m *model.ResourcePool, u types.ObjectUpdate) {
+	for _, p := range u.ChangeSet {
+		switch p.Op {
+		case Assign:
+			switch p.Name {
+			case fOwner:
+				m.Owner = v.Ref(p.Val)
+			case fCpuAlloc:
+				if a, cast := p.Val.(types.ResourceAllocationInfo); cast {
+					m.CpuAllocation = v.Allocation(a)
+				}
+			case fMemoryAlloc:
+				if a, cast := p.Val.(types.ResourceAllocationInfo); cast {
+					m.MemoryAllocation = v.Allocation(a)
+				}
+			case fResourcePool:
+				m.Pools = v.RefList(p.Val)
+			case fVm:
+				m.Vms = v.RefList(p.Val)
+			}
+		}
+	}
+}
+
+//
+// vApp model adapter.
+type VAppAdapter struct {
+	ResourcePoolAdapter
+	// The adapter model.
+	model model.VApp
+}
+
+//
+// The adapter model.
+func (v *VAppAdapter) Model() model.Model {
+	return &v.model
+}
+
+//
+// Apply the update to the model.
+func (v *VAppAdapter) Apply(u types.ObjectUpdate) {
+	v.Base.Apply(&v.model.Base, u)
+	v.applyPool(&v.model.ResourcePool, u)
+}
+
 //
 // Datastore model adapter.
 type DatastoreAdapter struct {
@@ -460,6 +700,8 @@ func (v *DatastoreAdapter) Apply(u types.ObjectUpdate) {
 				if s, cast := p.Val.(string); cast {
 					v.model.MaintenanceMode = s
 				}
+			case fCustomValue:
+				v.model.CustomAttributes = v.CustomAttributes(p.Val)
 			}
 		}
 	}
@@ -495,6 +737,12 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 				if s, cast := p.Val.(string); cast {
 					v.model.Firmware = s
 				}
+			case fSecureBoot:
+				if b, cast := p.Val.(bool); cast {
+					v.model.SecureBoot = b
+				}
+			case fKeyId:
+				_, v.model.Encrypted = p.Val.(types.CryptoKeyId)
 			case fPowerState:
 				if s, cast := p.Val.(types.VirtualMachinePowerState); cast {
 					v.model.PowerState = string(s)
@@ -527,6 +775,20 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 				if n, cast := p.Val.(int32); cast {
 					v.model.MemoryMB = n
 				}
+			case fCpuAlloc:
+				switch a := p.Val.(type) {
+				case types.ResourceAllocationInfo:
+					v.model.CpuAllocation = v.Allocation(a)
+				case *types.ResourceAllocationInfo:
+					v.model.CpuAllocation = v.Allocation(*a)
+				}
+			case fMemoryAlloc:
+				switch a := p.Val.(type) {
+				case types.ResourceAllocationInfo:
+					v.model.MemoryAllocation = v.Allocation(a)
+				case *types.ResourceAllocationInfo:
+					v.model.MemoryAllocation = v.Allocation(*a)
+				}
 			case fStorageUsed:
 				if n, cast := p.Val.(int64); cast {
 					v.model.StorageUsed = n
@@ -539,6 +801,14 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 				if n, cast := p.Val.(int32); cast {
 					v.model.BalloonedMemory = n
 				}
+			case fCustomValue:
+				v.model.CustomAttributes = v.CustomAttributes(p.Val)
+			case fResourcePool:
+				v.model.ResourcePool = v.Ref(p.Val)
+			case fParentVApp:
+				if ref := v.Ref(p.Val); ref.ID != "" {
+					v.model.Parent = ref
+				}
 			case fRuntimeHost:
 				v.model.Host = v.Ref(p.Val)
 			case fVmIpAddress:
@@ -566,8 +836,11 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 			case fDevices:
 				if devArray, cast := p.Val.(types.ArrayOfVirtualDevice); cast {
 					list := []model.Device{}
+					v.model.TpmEnabled = false
 					for _, dev := range devArray.VirtualDevice {
 						switch dev.(type) {
+						case *types.VirtualTPM:
+							v.model.TpmEnabled = true
 						case *types.VirtualSriovEthernetCard,
 							*types.VirtualPCIPassthrough,
 							*types.VirtualSCSIPassthrough,
@@ -590,11 +863,13 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 //
 // Update virtual disk devices.
 func (v *VmAdapter) updateDisks(devArray *types.ArrayOfVirtualDevice) {
+	controllers := v.controllers(devArray)
 	disks := []model.Disk{}
 	for _, dev := range devArray.VirtualDevice {
 		switch dev.(type) {
 		case *types.VirtualDisk:
 			disk := dev.(*types.VirtualDisk)
+			nDisk := len(disks)
 			switch disk.Backing.(type) {
 			case *types.VirtualDiskFlatVer1BackingInfo:
 				backing := disk.Backing.(*types.VirtualDiskFlatVer1BackingInfo)
@@ -641,8 +916,67 @@ func (v *VmAdapter) updateDisks(devArray *types.ArrayOfVirtualDevice) {
 				}
 				disks = append(disks, md)
 			}
+			if len(disks) > nDisk {
+				md := &disks[nDisk]
+				if controller, found := controllers[disk.ControllerKey]; found {
+					md.Bus = controller.bus
+					md.Controller = controller.kind
+					md.BusNumber = controller.busNumber
+				}
+				if disk.UnitNumber != nil {
+					md.UnitNumber = *disk.UnitNumber
+				}
+				if disk.DeviceInfo != nil {
+					md.Label = disk.DeviceInfo.GetDescription().Label
+				}
+				if disk.VDiskId != nil {
+					md.ObjectID = disk.VDiskId.Id
+				} else {
+					md.ObjectID = disk.DiskObjectId
+				}
+			}
 		}
 	}
 
 	v.model.Disks = disks
 }
+
+//
+// Disk controller.
+type diskController struct {
+	// Bus type.
+	bus string
+	// Controller kind.
+	kind string
+	// Bus number.
+	busNumber int32
+}
+
+//
+// Build disk controllers keyed by device key.
+func (v *VmAdapter) controllers(devArray *types.ArrayOfVirtualDevice) (mp map[int32]diskController) {
+	mp = map[int32]diskController{}
+	for _, dev := range devArray.VirtualDevice {
+		var bus string
+		switch dev.(type) {
+		case types.BaseVirtualSCSIController:
+			bus = model.SCSI
+		case types.BaseVirtualSATAController:
+			bus = model.SATA
+		case *types.VirtualIDEController:
+			bus = model.IDE
+		case *types.VirtualNVMEController:
+			bus = model.NVME
+		default:
+			continue
+		}
+		controller := dev.(types.BaseVirtualController).GetVirtualController()
+		mp[controller.Key] = diskController{
+			bus:       bus,
+			kind:      libref.ToKind(dev),
+			busNumber: controller.BusNumber,
+		}
+	}
+
+	return
+}
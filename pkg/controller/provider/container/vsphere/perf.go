@@ -0,0 +1,324 @@
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	liberr "github.com/konveyor/controller/pkg/error"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"github.com/vmware/govmomi/performance"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+	"math"
+	"sort"
+	"time"
+)
+
+//
+// Performance collection.
+const (
+	// Real-time sample interval (seconds).
+	PerfSampleInterval = 20
+	// Max real-time samples (1 hour) retained by vSphere.
+	PerfMaxSamples = 180
+	// Max VMs in each query.
+	PerfBatchSize = 64
+)
+
+//
+// Performance counters mapped to metric names.
+var perfCounters = map[string]string{
+	"cpu.usagemhz.average":             model.CpuUsageMHz,
+	"cpu.usage.average":                model.CpuUsagePercent,
+	"mem.consumed.average":             model.MemConsumedKB,
+	"mem.active.average":               model.MemActiveKB,
+	"disk.numberReadAveraged.average":  model.DiskReadIOPS,
+	"disk.numberWriteAveraged.average": model.DiskWriteIOPS,
+	"disk.read.average":                model.DiskReadKBps,
+	"disk.write.average":               model.DiskWriteKBps,
+	"net.received.average":             model.NetRxKBps,
+	"net.transmitted.average":          model.NetTxKBps,
+}
+
+//
+// Counters reported in hundredths of a percent.
+var perfPercent = map[string]bool{
+	model.CpuUsagePercent: true,
+}
+
+//
+// Periodically collect VM performance statistics.
+// Collection is optional and enabled by settings.
+func (r *Reconciler) watchPerf(ctx context.Context, client *vim25.Client) {
+	settings := Settings.Inventory.Perf
+	if !settings.Enabled {
+		return
+	}
+	interval := time.Duration(settings.Interval) * time.Minute
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+		err := r.collectPerf(ctx, client, interval)
+		if err != nil {
+			r.log.Trace(err)
+		}
+	}
+}
+
+//
+// Collect performance statistics for powered-on VMs and
+// update the rolling aggregates.
+func (r *Reconciler) collectPerf(ctx context.Context, client *vim25.Client, interval time.Duration) (err error) {
+	vmList := []model.VM{}
+	err = r.db.List(
+		&vmList,
+		libmodel.ListOptions{
+			Predicate: libmodel.Eq("PowerState", string(types.VirtualMachinePowerStatePoweredOn)),
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	maxSample := int32(interval.Seconds() / PerfSampleInterval)
+	if maxSample > PerfMaxSamples {
+		maxSample = PerfMaxSamples
+	}
+	if maxSample < 1 {
+		maxSample = 1
+	}
+	counters := []string{}
+	for name := range perfCounters {
+		counters = append(counters, name)
+	}
+	manager := performance.NewManager(client)
+	now := time.Now()
+	samples := map[string]model.MetricSample{}
+	for start := 0; start < len(vmList); start += PerfBatchSize {
+		end := start + PerfBatchSize
+		if end > len(vmList) {
+			end = len(vmList)
+		}
+		refs := []types.ManagedObjectReference{}
+		for _, vm := range vmList[start:end] {
+			refs = append(
+				refs,
+				types.ManagedObjectReference{
+					Type:  VirtualMachine,
+					Value: vm.ID,
+				})
+		}
+		spec := types.PerfQuerySpec{
+			IntervalId: PerfSampleInterval,
+			MaxSample:  maxSample,
+			MetricId: []types.PerfMetricId{
+				{Instance: ""},
+			},
+		}
+		var result []types.BasePerfEntityMetricBase
+		result, err = manager.SampleByName(ctx, spec, counters, refs)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		var series []performance.EntityMetric
+		series, err = manager.ToMetricSeries(ctx, result)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		for _, entity := range series {
+			samples[entity.Entity.Value] = r.perfSample(now, entity)
+		}
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer tx.End()
+	err = r.applyPerf(tx, now, samples)
+	if err != nil {
+		return
+	}
+	err = tx.Commit()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
+//
+// Build a sample (average and peak) for the
+// metric series reported for a VM.
+func (r *Reconciler) perfSample(now time.Time, entity performance.EntityMetric) (sample model.MetricSample) {
+	sample = model.MetricSample{
+		Time:    now,
+		Average: map[string]float64{},
+		Peak:    map[string]float64{},
+	}
+	for _, series := range entity.Value {
+		if series.Instance != "" {
+			continue
+		}
+		name, found := perfCounters[series.Name]
+		if !found {
+			continue
+		}
+		sum := float64(0)
+		peak := float64(0)
+		n := 0
+		for _, v := range series.Value {
+			if v < 0 {
+				continue
+			}
+			f := float64(v)
+			if perfPercent[name] {
+				f = f / 100
+			}
+			sum += f
+			peak = math.Max(peak, f)
+			n++
+		}
+		if n > 0 {
+			sample.Average[name] = sum / float64(n)
+			sample.Peak[name] = peak
+		}
+	}
+
+	return
+}
+
+//
+// Apply samples to the VM metrics.
+// Samples older than the largest window are discarded and
+// the metrics for VMs without samples are deleted.
+func (r *Reconciler) applyPerf(tx *libmodel.Tx, now time.Time, samples map[string]model.MetricSample) (err error) {
+	windows := Settings.Inventory.Perf.Windows
+	maxAge := time.Duration(0)
+	for _, hours := range windows {
+		d := time.Duration(hours) * time.Hour
+		if d > maxAge {
+			maxAge = d
+		}
+	}
+	list := []model.VMMetrics{}
+	err = tx.List(&list, libmodel.ListOptions{Detail: 1})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	current := map[string]*model.VMMetrics{}
+	for i := range list {
+		current[list[i].ID] = &list[i]
+	}
+	for id, sample := range samples {
+		if _, found := current[id]; found {
+			continue
+		}
+		m := &model.VMMetrics{
+			Base: model.Base{
+				ID: id,
+			},
+		}
+		m.Samples = append(m.Samples, sample)
+		m.Windows = r.perfWindows(now, m.Samples, windows)
+		m.Created()
+		err = tx.Insert(m)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	for id, m := range current {
+		if sample, found := samples[id]; found {
+			m.Samples = append(m.Samples, sample)
+		}
+		kept := []model.MetricSample{}
+		for _, sample := range m.Samples {
+			if now.Sub(sample.Time) <= maxAge {
+				kept = append(kept, sample)
+			}
+		}
+		m.Samples = kept
+		if len(m.Samples) == 0 {
+			err = tx.Delete(m)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+			continue
+		}
+		m.Windows = r.perfWindows(now, m.Samples, windows)
+		m.Updated()
+		err = tx.Update(m)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+
+	return
+}
+
+//
+// Aggregate the samples over each window.
+// The percentile is of the interval averages.
+func (r *Reconciler) perfWindows(now time.Time, samples []model.MetricSample, windows []int) (list []model.MetricWindow) {
+	list = []model.MetricWindow{}
+	for _, hours := range windows {
+		window := model.MetricWindow{
+			Window:  fmt.Sprintf("%dh", hours),
+			Metrics: map[string]model.Aggregate{},
+		}
+		age := time.Duration(hours) * time.Hour
+		averages := map[string][]float64{}
+		peaks := map[string]float64{}
+		for _, sample := range samples {
+			if now.Sub(sample.Time) > age {
+				continue
+			}
+			window.Samples++
+			for name, v := range sample.Average {
+				averages[name] = append(averages[name], v)
+			}
+			for name, v := range sample.Peak {
+				peaks[name] = math.Max(peaks[name], v)
+			}
+		}
+		for name, values := range averages {
+			sum := float64(0)
+			for _, v := range values {
+				sum += v
+			}
+			window.Metrics[name] = model.Aggregate{
+				Average:     sum / float64(len(values)),
+				IntervalP95: r.percentile(values, 95),
+				Peak:        peaks[name],
+			}
+		}
+		list = append(list, window)
+	}
+
+	return
+}
+
+//
+// Percentile (nearest rank).
+func (r *Reconciler) percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
@@ -1,22 +1,30 @@
 package vsphere
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	liberr "github.com/konveyor/controller/pkg/error"
 	libmodel "github.com/konveyor/controller/pkg/inventory/model"
 	"github.com/konveyor/controller/pkg/logging"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/analysis"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/certificate"
+	inventory "github.com/konveyor/forklift-controller/pkg/controller/provider/model"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/property"
 	"github.com/vmware/govmomi/session"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/soap"
 	"github.com/vmware/govmomi/vim25/types"
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	liburl "net/url"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"time"
 )
 
@@ -25,8 +33,16 @@ import (
 const (
 	// Connect retry delay.
 	RetryDelay = time.Second * 5
+	// Max connect retry delay (backoff).
+	MaxRetryDelay = time.Minute * 5
+	// Session keepalive (idle) interval.
+	KeepAliveInterval = time.Minute * 5
 	// Max object in each update.
 	MaxObjectUpdates = 10000
+	// Tag refresh interval.
+	TagRefreshInterval = time.Minute * 5
+	// Analysis rule (file) check interval.
+	RuleCheckInterval = time.Second * 30
 )
 
 //
@@ -40,30 +56,41 @@ const (
 	Network        = "Network"
 	DVPortGroup    = "DistributedVirtualPortgroup"
 	DVSwitch       = "VmwareDistributedVirtualSwitch"
+	DVSwitchBase   = "DistributedVirtualSwitch"
 	Datastore      = "Datastore"
+	ComputeRes     = "ComputeResource"
+	ResourcePool   = "ResourcePool"
+	VirtualApp     = "VirtualApp"
+	FieldsManager  = "CustomFieldsManager"
 )
 
 //
 // Fields
 const (
 	// Common
-	fName      = "name"
-	fParent    = "parent"
-	fHost      = "host"
-	fNetwork   = "network"
-	fDatastore = "datastore"
+	fName         = "name"
+	fParent       = "parent"
+	fHost         = "host"
+	fNetwork      = "network"
+	fDatastore    = "datastore"
+	fResourcePool = "resourcePool"
+	// Custom attributes
+	fCustomValue = "customValue"
+	fField       = "field"
 	// Folders
 	fVmFolder    = "vmFolder"
 	fHostFolder  = "hostFolder"
 	fNetFolder   = "networkFolder"
 	fDsFolder    = "datastoreFolder"
 	fChildEntity = "childEntity"
+	fChildType   = "childType"
 	// Cluster
 	fDasEnabled    = "configuration.dasConfig.enabled"
 	fDasVmCfg      = "configuration.dasVmConfig"
 	fDrsEnabled    = "configuration.drsConfig.enabled"
 	fDrsVmBehavior = "configuration.drsConfig.defaultVmBehavior"
 	fDrsVmCfg      = "configuration.drsVmConfig"
+	fConfigEx      = "configurationEx"
 	// Host
 	fVm             = "vm"
 	fProductName    = "config.product.name"
@@ -75,13 +102,22 @@ const (
 	fInMaintMode    = "summary.runtime.inMaintenanceMode"
 	fCpuSockets     = "summary.hardware.numCpuPkgs"
 	fCpuCores       = "summary.hardware.numCpuCores"
+	fCpuMhz         = "summary.hardware.cpuMhz"
 	fThumbprint     = "summary.config.sslThumbprint"
 	// Network
 	fTag = "tag"
 	// PortGroup
-	fDVSwitch = "config.distributedVirtualSwitch"
+	fDVSwitch       = "config.distributedVirtualSwitch"
+	fDefaultPortCfg = "config.defaultPortConfig"
 	// DV Switch
 	fDVSwitchHost = "config.host"
+	fDVSUplinks   = "config.uplinkPortPolicy"
+	fDVSMtu       = "config.maxMtu"
+	fDVSPortGroup = "portgroup"
+	// Resource pool
+	fOwner       = "owner"
+	fCpuAlloc    = "config.cpuAllocation"
+	fMemoryAlloc = "config.memoryAllocation"
 	// Datastore
 	fDsType      = "summary.type"
 	fCapacity    = "summary.capacity"
@@ -90,6 +126,8 @@ const (
 	// VM
 	fUUID                = "config.uuid"
 	fFirmware            = "config.firmware"
+	fSecureBoot          = "config.bootOptions.efiSecureBootEnabled"
+	fKeyId               = "config.keyId"
 	fFtInfo              = "config.ftInfo"
 	fCpuAffinity         = "config.cpuAffinity"
 	fCpuHotAddEnabled    = "config.cpuHotAddEnabled"
@@ -104,6 +142,7 @@ const (
 	fBalloonedMemory     = "summary.quickStats.balloonedMemory"
 	fVmIpAddress         = "summary.guest.ipAddress"
 	fStorageUsed         = "summary.storage.committed"
+	fParentVApp          = "parentVApp"
 	fRuntimeHost         = "runtime.host"
 	fPowerState          = "runtime.powerState"
 )
@@ -112,6 +151,9 @@ const (
 // Selections
 const (
 	TraverseFolders = "traverseFolders"
+	TraversePools   = "traversePools"
+	TraverseVAppVM  = "traverseVAppVM"
+	TraverseCompute = "traverseCompute"
 )
 
 //
@@ -195,6 +237,111 @@ var TsDatacenterDatastore = &types.TraversalSpec{
 	},
 }
 
+//
+// ComputeResource/ResourcePool traversal Spec.
+// Includes clusters.
+var TsComputeResourcePool = &types.TraversalSpec{
+	Type: ComputeRes,
+	Path: fResourcePool,
+	SelectSet: []types.BaseSelectionSpec{
+		&types.SelectionSpec{
+			Name: TraversePools,
+		},
+	},
+}
+
+//
+// ResourcePool (nested) traversal Spec.
+// Includes vApps.
+var TsResourcePool = &types.TraversalSpec{
+	SelectionSpec: types.SelectionSpec{
+		Name: TraversePools,
+	},
+	Type: ResourcePool,
+	Path: fResourcePool,
+	SelectSet: []types.BaseSelectionSpec{
+		&types.SelectionSpec{
+			Name: TraversePools,
+		},
+		&types.SelectionSpec{
+			Name: TraverseVAppVM,
+		},
+	},
+}
+
+//
+// VirtualApp/VM traversal Spec.
+var TsVAppVM = &types.TraversalSpec{
+	SelectionSpec: types.SelectionSpec{
+		Name: TraverseVAppVM,
+	},
+	Type: VirtualApp,
+	Path: fVm,
+}
+
+//
+// Host folder (compute) traversal Spec.
+// Used by the scope to traverse only the compute resources
+// contained by an included folder.
+var TsComputeFolder = &types.TraversalSpec{
+	SelectionSpec: types.SelectionSpec{
+		Name: TraverseCompute,
+	},
+	Type: Folder,
+	Path: fChildEntity,
+	SelectSet: []types.BaseSelectionSpec{
+		&types.SelectionSpec{
+			Name: TraverseCompute,
+		},
+		TsComputeResourceHost,
+		TsComputeResourcePool,
+	},
+}
+
+//
+// ComputeResource/Host traversal Spec.
+// Includes clusters.
+var TsComputeResourceHost = &types.TraversalSpec{
+	Type: ComputeRes,
+	Path: fHost,
+	SelectSet: []types.BaseSelectionSpec{
+		TsHostVM,
+		TsHostNetwork,
+		TsHostDatastore,
+	},
+}
+
+//
+// Host/VM traversal Spec.
+var TsHostVM = &types.TraversalSpec{
+	Type: Host,
+	Path: fVm,
+}
+
+//
+// Host/Network traversal Spec.
+var TsHostNetwork = &types.TraversalSpec{
+	Type: Host,
+	Path: fNetwork,
+	SelectSet: []types.BaseSelectionSpec{
+		TsPortGroupDVSwitch,
+	},
+}
+
+//
+// Host/Datastore traversal Spec.
+var TsHostDatastore = &types.TraversalSpec{
+	Type: Host,
+	Path: fDatastore,
+}
+
+//
+// PortGroup/DVSwitch traversal Spec.
+var TsPortGroupDVSwitch = &types.TraversalSpec{
+	Type: DVPortGroup,
+	Path: fDVSwitch,
+}
+
 //
 // Root Folder traversal Spec
 var TsRootFolder = &types.TraversalSpec{
@@ -210,6 +357,9 @@ var TsRootFolder = &types.TraversalSpec{
 		TsDatacenterNet,
 		TsDatacenterDatastore,
 		TsClusterHostSystem,
+		TsComputeResourcePool,
+		TsResourcePool,
+		TsVAppVM,
 	},
 }
 
@@ -238,6 +388,21 @@ type Reconciler struct {
 	cancel func()
 	// has consistency
 	consistent bool
+	// Custom field definitions.
+	// Maps field key to name.
+	fields map[int32]string
+	// Collector health.
+	health *Health
+	// Serving the cached inventory.
+	warm bool
+	// The about (cached).
+	about *model.About
+	// Objects reported during the initial sync.
+	seen map[string]bool
+	// Inventory scope.
+	scope *Scope
+	// Analysis rule set source.
+	rules analysis.RuleSource
 }
 
 //
@@ -250,6 +415,41 @@ func New(db libmodel.DB, provider *api.Provider, secret *core.Secret) *Reconcile
 		secret:   secret,
 		db:       db,
 		log:      log,
+		fields:   map[int32]string{},
+		health:   &Health{changed: healthChanged(provider)},
+		scope:    NewScope(provider),
+		rules:    ruleSource(),
+	}
+}
+
+//
+// Build the health changed notification.
+// The event is dropped when the queue is full.
+func healthChanged(provider *api.Provider) func() {
+	owner := &api.Provider{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: provider.Namespace,
+			Name:      provider.Name,
+		},
+	}
+	return func() {
+		select {
+		case inventory.HealthChanged <- event.GenericEvent{Meta: owner, Object: owner}:
+		default:
+		}
+	}
+}
+
+//
+// The analysis rule set source.
+// The (watched) ConfigMap when configured. Otherwise, the file.
+func ruleSource() analysis.RuleSource {
+	if Settings.Inventory.AnalysisConfigMap.Name != "" {
+		return analysis.ConfigMapRules
+	}
+
+	return &analysis.RuleFile{
+		Path: Settings.Inventory.AnalysisRules,
 	}
 }
 
@@ -283,9 +483,17 @@ func (r *Reconciler) Reset() {
 }
 
 //
-// Reset.
+// Has consistency.
+// The cached inventory (warm start) is served while
+// the initial sync is in progress.
 func (r *Reconciler) HasConsistency() bool {
-	return r.consistent
+	return r.consistent || r.warm
+}
+
+//
+// Collector health.
+func (r *Reconciler) Health() inventory.Health {
+	return r.health.Get()
 }
 
 //
@@ -307,6 +515,17 @@ func (r *Reconciler) Test() (err error) {
 //
 // Start the reconciler.
 func (r *Reconciler) Start() error {
+	if Settings.Inventory.WarmStart {
+		cached, err := r.cached()
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		if cached {
+			r.log.Info("Serving cached inventory.")
+			r.warm = true
+			r.health.stale(true)
+		}
+	}
 	ctx := context.Background()
 	ctx, r.cancel = context.WithCancel(ctx)
 	start := func() {
@@ -318,9 +537,14 @@ func (r *Reconciler) Start() error {
 			default:
 				err := r.getUpdates(ctx)
 				if err != nil {
-					r.log.Trace(err, "retry", RetryDelay)
-					time.Sleep(RetryDelay)
-					continue try
+					delay := r.health.failed(err)
+					r.log.Trace(err, "retry", delay)
+					select {
+					case <-ctx.Done():
+						break try
+					case <-time.After(delay):
+						continue try
+					}
 				}
 				break try
 			}
@@ -351,13 +575,23 @@ func (r *Reconciler) getUpdates(ctx context.Context) error {
 	if err != nil {
 		return liberr.Wrap(err)
 	}
-	defer r.client.Logout(ctx)
+	defer func() {
+		_ = r.client.Logout(context.Background())
+		r.health.disconnected()
+	}()
+	r.health.connected()
 	about := r.client.ServiceContent.About
-	err = r.db.Insert(
-		&model.About{
-			APIVersion: about.ApiVersion,
-			Product:    about.LicenseProductName,
-		})
+	err = r.updateAbout(about.ApiVersion, about.LicenseProductName)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	r.seen = map[string]bool{}
+	err = r.customFields(ctx)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	err = r.scope.resolve(ctx, r.client.Client)
+	r.health.scope(r.scope.NotFound)
 	if err != nil {
 		return liberr.Wrap(err)
 	}
@@ -382,6 +616,10 @@ func (r *Reconciler) getUpdates(ctx context.Context) error {
 	watchList := []*libmodel.Watch{}
 	defer func() {
 		r.consistent = false
+		r.seen = nil
+		if r.warm {
+			r.health.stale(true)
+		}
 		for _, w := range watchList {
 			r.db.EndWatch(w)
 		}
@@ -401,11 +639,6 @@ next:
 		}
 		updateSet := response.Returnval
 		if updateSet == nil {
-			err := r.connect(ctx)
-			if err != nil {
-				r.log.Trace(err)
-				time.Sleep(RetryDelay)
-			}
 			continue next
 		}
 		req.Version = updateSet.Version
@@ -428,12 +661,27 @@ next:
 		}
 		if err != nil {
 			Log.Trace(err)
+		} else {
+			r.health.updated()
 		}
 		if updateSet.Truncated == nil || !*updateSet.Truncated {
 			if !r.consistent {
+				err = r.synced()
+				if err != nil {
+					return liberr.Wrap(err)
+				}
 				r.consistent = true
+				if Settings.Inventory.WarmStart {
+					r.warm = true
+				}
+				r.health.stale(false)
 				r.log.Info("Initial consistency.", "duration", time.Since(mark))
 				watchList = r.watch()
+				watchCtx, cancelWatch := context.WithCancel(ctx)
+				defer cancelWatch()
+				go r.watchTags(watchCtx, r.client.Client)
+				go r.watchPerf(watchCtx, r.client.Client)
+				go r.watchRules(watchCtx)
 			}
 		}
 	}
@@ -452,7 +700,10 @@ func (r *Reconciler) watch() (list []*libmodel.Watch) {
 		list = append(list, w)
 	}
 	// VM
-	w, err = r.db.Watch(&model.VM{}, &WatchVM{r.db})
+	w, err = r.db.Watch(&model.VM{}, &WatchVM{
+		DB:        r.db,
+		Analyzers: analyzers(r.rules),
+	})
 	if err != nil {
 		Log.Trace(liberr.Wrap(err))
 	} else {
@@ -477,11 +728,17 @@ func (r *Reconciler) connect(ctx context.Context) error {
 		r.user(),
 		r.password())
 	soapClient := soap.NewClient(url, false)
-	soapClient.SetThumbprint(url.Host, r.thumbprint())
+	err = certificate.TLSConfig(soapClient, r.secret)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
 	vimClient, err := vim25.NewClient(ctx, soapClient)
 	if err != nil {
 		return liberr.Wrap(err)
 	}
+	vimClient.RoundTripper = session.KeepAlive(
+		vimClient.RoundTripper,
+		KeepAliveInterval)
 	r.client = &govmomi.Client{
 		SessionManager: session.NewManager(vimClient),
 		Client:         vimClient,
@@ -514,16 +771,6 @@ func (r *Reconciler) password() string {
 	return ""
 }
 
-//
-// Thumbprint.
-func (r *Reconciler) thumbprint() string {
-	if password, found := r.secret.Data["thumbprint"]; found {
-		return string(password)
-	}
-
-	return ""
-}
-
 //
 // Build the object Spec filter.
 func (r *Reconciler) filter(pc *property.Collector) *property.WaitFilter {
@@ -531,9 +778,9 @@ func (r *Reconciler) filter(pc *property.Collector) *property.WaitFilter {
 		CreateFilter: types.CreateFilter{
 			This: pc.Reference(),
 			Spec: types.PropertyFilterSpec{
-				ObjectSet: []types.ObjectSpec{
+				ObjectSet: append(
 					r.objectSpec(),
-				},
+					r.fieldsSpec()...),
 				PropSet: r.propertySpec(),
 			},
 		},
@@ -543,15 +790,76 @@ func (r *Reconciler) filter(pc *property.Collector) *property.WaitFilter {
 
 //
 // Build the object Spec.
-func (r *Reconciler) objectSpec() types.ObjectSpec {
-	return types.ObjectSpec{
-		Obj: r.client.ServiceContent.RootFolder,
-		SelectSet: []types.BaseSelectionSpec{
-			TsRootFolder,
+// Traverse the scope when paths are included.
+func (r *Reconciler) objectSpec() []types.ObjectSpec {
+	if r.scope.Scoped() {
+		return r.scope.objectSpec
+	}
+
+	return []types.ObjectSpec{
+		{
+			Obj: r.client.ServiceContent.RootFolder,
+			SelectSet: []types.BaseSelectionSpec{
+				TsRootFolder,
+			},
 		},
 	}
 }
 
+//
+// Build the object Spec for the custom fields manager.
+func (r *Reconciler) fieldsSpec() (spec []types.ObjectSpec) {
+	ref := r.client.ServiceContent.CustomFieldsManager
+	if ref != nil {
+		spec = append(
+			spec,
+			types.ObjectSpec{
+				Obj: *ref,
+			})
+	}
+
+	return
+}
+
+//
+// Load the custom field definitions.
+func (r *Reconciler) customFields(ctx context.Context) (err error) {
+	ref := r.client.ServiceContent.CustomFieldsManager
+	if ref == nil {
+		return
+	}
+	manager := mo.CustomFieldsManager{}
+	pc := property.DefaultCollector(r.client.Client)
+	err = pc.RetrieveOne(ctx, *ref, []string{fField}, &manager)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.fields = map[int32]string{}
+	for _, f := range manager.Field {
+		r.fields[f.Key] = f.Name
+	}
+
+	return
+}
+
+//
+// Apply custom field definition updates.
+func (r *Reconciler) applyFields(u types.ObjectUpdate) {
+	for _, p := range u.ChangeSet {
+		if p.Name != fField {
+			continue
+		}
+		if a, cast := p.Val.(types.ArrayOfCustomFieldDef); cast {
+			fields := map[int32]string{}
+			for _, f := range a.CustomFieldDef {
+				fields[f.Key] = f.Name
+			}
+			r.fields = fields
+		}
+	}
+}
+
 //
 // Build the property Spec.
 func (r *Reconciler) propertySpec() []types.PropertySpec {
@@ -585,6 +893,8 @@ func (r *Reconciler) propertySpec() []types.PropertySpec {
 				fDrsEnabled,
 				fDrsVmBehavior,
 				fDrsVmCfg,
+				fConfigEx,
+				fResourcePool,
 				fHost,
 				fNetwork,
 				fDatastore,
@@ -599,8 +909,10 @@ func (r *Reconciler) propertySpec() []types.PropertySpec {
 				fProductVersion,
 				fThumbprint,
 				fInMaintMode,
+				fCustomValue,
 				fCpuSockets,
 				fCpuCores,
+				fCpuMhz,
 				fDatastore,
 				fNetwork,
 				fVm,
@@ -622,7 +934,9 @@ func (r *Reconciler) propertySpec() []types.PropertySpec {
 			Type: DVPortGroup,
 			PathSet: []string{
 				fName,
+				fParent,
 				fDVSwitch,
+				fDefaultPortCfg,
 				fTag,
 			},
 		},
@@ -630,7 +944,45 @@ func (r *Reconciler) propertySpec() []types.PropertySpec {
 			Type: DVSwitch,
 			PathSet: []string{
 				fName,
+				fParent,
+				fDVSwitchHost,
+				fDVSUplinks,
+				fDVSMtu,
+				fDVSPortGroup,
+			},
+		},
+		{ // Not VMware (third party) switch.
+			Type: DVSwitchBase,
+			PathSet: []string{
+				fName,
+				fParent,
 				fDVSwitchHost,
+				fDVSUplinks,
+				fDVSPortGroup,
+			},
+		},
+		{ // Resource pool
+			Type: ResourcePool,
+			PathSet: []string{
+				fName,
+				fParent,
+				fOwner,
+				fCpuAlloc,
+				fMemoryAlloc,
+				fResourcePool,
+				fVm,
+			},
+		},
+		{ // vApp
+			Type: VirtualApp,
+			PathSet: []string{
+				fName,
+				fParent,
+				fOwner,
+				fCpuAlloc,
+				fMemoryAlloc,
+				fResourcePool,
+				fVm,
 			},
 		},
 		{ // Datastore
@@ -642,6 +994,7 @@ func (r *Reconciler) propertySpec() []types.PropertySpec {
 				fCapacity,
 				fFreeSpace,
 				fDsMaintMode,
+				fCustomValue,
 				fHost,
 			},
 		},
@@ -652,6 +1005,8 @@ func (r *Reconciler) propertySpec() []types.PropertySpec {
 				fParent,
 				fUUID,
 				fFirmware,
+				fSecureBoot,
+				fKeyId,
 				fFtInfo,
 				fCpuAffinity,
 				fCpuHotAddEnabled,
@@ -660,6 +1015,8 @@ func (r *Reconciler) propertySpec() []types.PropertySpec {
 				fNumCpu,
 				fNumCoresPerSocket,
 				fMemorySize,
+				fCpuAlloc,
+				fMemoryAlloc,
 				fDevices,
 				fExtraConfig,
 				fGuestName,
@@ -670,6 +1027,15 @@ func (r *Reconciler) propertySpec() []types.PropertySpec {
 				fNetwork,
 				fRuntimeHost,
 				fPowerState,
+				fResourcePool,
+				fParentVApp,
+				fCustomValue,
+			},
+		},
+		{ // Custom fields
+			Type: FieldsManager,
+			PathSet: []string{
+				fField,
 			},
 		},
 	}
@@ -679,6 +1045,10 @@ func (r *Reconciler) propertySpec() []types.PropertySpec {
 // Apply updates.
 func (r *Reconciler) apply(ctx context.Context, tx *libmodel.Tx, updates []types.ObjectUpdate) (err error) {
 	for _, u := range updates {
+		if u.Obj.Type == FieldsManager {
+			r.applyFields(u)
+			continue
+		}
 		switch string(u.Kind) {
 		case Enter:
 			err = r.applyEnter(tx, u)
@@ -727,6 +1097,9 @@ func (r *Reconciler) selectAdapter(u types.ObjectUpdate) (Adapter, bool) {
 		}
 	case Host:
 		adapter = &HostAdapter{
+			Base: Base{
+				fields: r.fields,
+			},
 			model: model.Host{
 				Base: model.Base{
 					ID: u.Obj.Value,
@@ -749,7 +1122,7 @@ func (r *Reconciler) selectAdapter(u types.ObjectUpdate) (Adapter, bool) {
 				},
 			},
 		}
-	case DVSwitch:
+	case DVSwitch, DVSwitchBase:
 		adapter = &DVSwitchAdapter{
 			model: model.DVSwitch{
 				Base: model.Base{
@@ -757,8 +1130,29 @@ func (r *Reconciler) selectAdapter(u types.ObjectUpdate) (Adapter, bool) {
 				},
 			},
 		}
+	case ResourcePool:
+		adapter = &ResourcePoolAdapter{
+			model: model.ResourcePool{
+				Base: model.Base{
+					ID: u.Obj.Value,
+				},
+			},
+		}
+	case VirtualApp:
+		adapter = &VAppAdapter{
+			model: model.VApp{
+				ResourcePool: model.ResourcePool{
+					Base: model.Base{
+						ID: u.Obj.Value,
+					},
+				},
+			},
+		}
 	case Datastore:
 		adapter = &DatastoreAdapter{
+			Base: Base{
+				fields: r.fields,
+			},
 			model: model.Datastore{
 				Base: model.Base{
 					ID: u.Obj.Value,
@@ -767,6 +1161,9 @@ func (r *Reconciler) selectAdapter(u types.ObjectUpdate) (Adapter, bool) {
 		}
 	case VirtualMachine:
 		adapter = &VmAdapter{
+			Base: Base{
+				fields: r.fields,
+			},
 			model: model.VM{
 				Base: model.Base{
 					ID: u.Obj.Value,
@@ -788,13 +1185,53 @@ func (r Reconciler) applyEnter(tx *libmodel.Tx, u types.ObjectUpdate) error {
 	if !selected {
 		return nil
 	}
-	adapter.Apply(u)
+	if !r.scope.admit(u) {
+		return nil
+	}
 	m := adapter.Model()
+	r.reported(m)
+	err := tx.Get(m)
+	if err == nil {
+		return r.applyResync(tx, adapter, u)
+	}
+	if !errors.Is(err, model.NotFound) {
+		return liberr.Wrap(err)
+	}
+	adapter.Apply(u)
 	if mX, cast := m.(interface{ Created() }); cast {
 		mX.Created()
 	}
 	r.log.Info("Create", "model", m.String())
-	err := tx.Insert(m)
+	err = tx.Insert(m)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+
+	return nil
+}
+
+//
+// Object (cached) reported by the initial sync.
+// Updated only when changed.
+func (r Reconciler) applyResync(tx *libmodel.Tx, adapter Adapter, u types.ObjectUpdate) error {
+	m := adapter.Model()
+	before, err := json.Marshal(m)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	adapter.Apply(u)
+	after, err := json.Marshal(m)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	if bytes.Equal(before, after) {
+		return nil
+	}
+	if mX, cast := m.(interface{ Updated() }); cast {
+		mX.Updated()
+	}
+	r.log.Info("Update (resync)", "model", m.String())
+	err = tx.Update(m)
 	if err != nil {
 		return liberr.Wrap(err)
 	}
@@ -805,6 +1242,9 @@ func (r Reconciler) applyEnter(tx *libmodel.Tx, u types.ObjectUpdate) error {
 //
 // Object modified.
 func (r Reconciler) applyModify(tx *libmodel.Tx, u types.ObjectUpdate) error {
+	if !r.scope.collected(u) {
+		return nil
+	}
 	adapter, selected := r.selectAdapter(u)
 	if !selected {
 		return nil
@@ -830,6 +1270,9 @@ func (r Reconciler) applyModify(tx *libmodel.Tx, u types.ObjectUpdate) error {
 //
 // Object deleted.
 func (r Reconciler) applyLeave(tx *libmodel.Tx, u types.ObjectUpdate) error {
+	if !r.scope.collected(u) {
+		return nil
+	}
 	var deleted model.Model
 	switch u.Obj.Type {
 	case Folder:
@@ -856,18 +1299,38 @@ func (r Reconciler) applyLeave(tx *libmodel.Tx, u types.ObjectUpdate) error {
 				ID: u.Obj.Value,
 			},
 		}
-	case Network:
+	case Network, DVPortGroup:
 		deleted = &model.Network{
 			Base: model.Base{
 				ID: u.Obj.Value,
 			},
 		}
+	case DVSwitch, DVSwitchBase:
+		deleted = &model.DVSwitch{
+			Base: model.Base{
+				ID: u.Obj.Value,
+			},
+		}
 	case Datastore:
 		deleted = &model.Datastore{
 			Base: model.Base{
 				ID: u.Obj.Value,
 			},
 		}
+	case ResourcePool:
+		deleted = &model.ResourcePool{
+			Base: model.Base{
+				ID: u.Obj.Value,
+			},
+		}
+	case VirtualApp:
+		deleted = &model.VApp{
+			ResourcePool: model.ResourcePool{
+				Base: model.Base{
+					ID: u.Obj.Value,
+				},
+			},
+		}
 	case VirtualMachine:
 		deleted = &model.VM{
 			Base: model.Base{
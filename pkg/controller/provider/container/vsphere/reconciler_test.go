@@ -0,0 +1,549 @@
+package vsphere_test
+
+import (
+	"context"
+	"errors"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/container/vsphere"
+	inventory "github.com/konveyor/forklift-controller/pkg/controller/provider/model"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/vcsim"
+	"github.com/onsi/gomega"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	_ "github.com/vmware/govmomi/vapi/simulator"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25/types"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+func TestInventory(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	h := &vcsim.Harness{}
+	defer h.Stop()
+	g.Expect(h.Start()).NotTo(gomega.HaveOccurred())
+
+	// Counts.
+	count := func(list interface{}) int {
+		g.Expect(h.DB.List(list, libmodel.ListOptions{})).NotTo(gomega.HaveOccurred())
+		switch l := list.(type) {
+		case *[]model.Datacenter:
+			return len(*l)
+		case *[]model.Cluster:
+			return len(*l)
+		case *[]model.Host:
+			return len(*l)
+		case *[]model.Datastore:
+			return len(*l)
+		case *[]model.Network:
+			return len(*l)
+		case *[]model.VM:
+			return len(*l)
+		}
+		return -1
+	}
+	sim := func(kinds ...string) (n int) {
+		for _, kind := range kinds {
+			n += len(simulator.Map.All(kind))
+		}
+		return
+	}
+	g.Expect(count(&[]model.Datacenter{})).To(gomega.Equal(sim(vsphere.Datacenter)))
+	g.Expect(count(&[]model.Cluster{})).To(gomega.Equal(sim(vsphere.Cluster)))
+	g.Expect(count(&[]model.Host{})).To(gomega.Equal(sim(vsphere.Host)))
+	g.Expect(count(&[]model.Datastore{})).To(gomega.Equal(sim(vsphere.Datastore)))
+	g.Expect(count(&[]model.Network{})).To(gomega.Equal(sim(vsphere.Network, vsphere.DVPortGroup)))
+	g.Expect(count(&[]model.VM{})).To(gomega.Equal(sim(vsphere.VirtualMachine)))
+
+	// VM.
+	for _, entity := range simulator.Map.All(vsphere.VirtualMachine) {
+		simVM := entity.(*simulator.VirtualMachine)
+		vm := &model.VM{Base: model.Base{ID: simVM.Self.Value}}
+		g.Expect(h.DB.Get(vm)).NotTo(gomega.HaveOccurred())
+		g.Expect(vm.Name).To(gomega.Equal(simVM.Name))
+		g.Expect(vm.UUID).To(gomega.Equal(simVM.Config.Uuid))
+		g.Expect(vm.PowerState).To(gomega.Equal(string(simVM.Runtime.PowerState)))
+		g.Expect(vm.CpuCount).To(gomega.Equal(simVM.Config.Hardware.NumCPU))
+		g.Expect(vm.MemoryMB).To(gomega.Equal(simVM.Config.Hardware.MemoryMB))
+		g.Expect(vm.Host.ID).To(gomega.Equal(simVM.Runtime.Host.Value))
+		g.Expect(vm.Host.Kind).To(gomega.Equal(model.HostKind))
+		g.Expect(vm.Disks).NotTo(gomega.BeEmpty())
+		g.Expect(vm.Disks[0].Label).NotTo(gomega.BeEmpty())
+		g.Expect(vm.Networks).NotTo(gomega.BeEmpty())
+	}
+
+	// Host.
+	for _, entity := range simulator.Map.All(vsphere.Host) {
+		simHost := entity.(*simulator.HostSystem)
+		host := &model.Host{Base: model.Base{ID: simHost.Self.Value}}
+		g.Expect(h.DB.Get(host)).NotTo(gomega.HaveOccurred())
+		g.Expect(host.Name).To(gomega.Equal(simHost.Name))
+		g.Expect(host.Thumbprint).To(gomega.Equal(simHost.Summary.Config.SslThumbprint))
+		g.Expect(host.Datastores).NotTo(gomega.BeEmpty())
+	}
+
+	// Updates.
+	ctx := context.TODO()
+	client, err := govmomi.NewClient(ctx, h.Server.URL, true)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	defer client.Logout(ctx)
+	simVM := simulator.Map.All(vsphere.VirtualMachine)[0].(*simulator.VirtualMachine)
+	vmObject := object.NewVirtualMachine(client.Client, simVM.Self)
+	// Renamed.
+	task, err := vmObject.Rename(ctx, "renamed")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(task.Wait(ctx)).NotTo(gomega.HaveOccurred())
+	g.Eventually(
+		func() string {
+			vm := &model.VM{Base: model.Base{ID: simVM.Self.Value}}
+			_ = h.DB.Get(vm)
+			return vm.Name
+		},
+		time.Second*10).Should(gomega.Equal("renamed"))
+	// Powered off.
+	task, err = vmObject.PowerOff(ctx)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(task.Wait(ctx)).NotTo(gomega.HaveOccurred())
+	g.Eventually(
+		func() string {
+			vm := &model.VM{Base: model.Base{ID: simVM.Self.Value}}
+			_ = h.DB.Get(vm)
+			return vm.PowerState
+		},
+		time.Second*10).Should(gomega.Equal(string(types.VirtualMachinePowerStatePoweredOff)))
+	// Deleted.
+	task, err = vmObject.Destroy(ctx)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(task.Wait(ctx)).NotTo(gomega.HaveOccurred())
+	g.Eventually(
+		func() error {
+			return h.DB.Get(&model.VM{Base: model.Base{ID: simVM.Self.Value}})
+		},
+		time.Second*10).Should(gomega.MatchError(model.NotFound))
+}
+
+func TestScope(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	vpx := simulator.VPX()
+	vpx.Cluster = 2
+	h := &vcsim.Harness{
+		Model: vpx,
+		Scope: &api.ProviderScope{
+			Include: []string{"/DC0/host/DC0_C0"},
+		},
+	}
+	defer h.Stop()
+	g.Expect(h.Start()).NotTo(gomega.HaveOccurred())
+	var cluster *simulator.ClusterComputeResource
+	for _, entity := range simulator.Map.All(vsphere.Cluster) {
+		if entity.Entity().Name == "DC0_C0" {
+			cluster = entity.(*simulator.ClusterComputeResource)
+		}
+	}
+	inCluster := map[string]bool{}
+	for _, ref := range cluster.Host {
+		inCluster[ref.Value] = true
+	}
+	expected := 0
+	for _, entity := range simulator.Map.All(vsphere.VirtualMachine) {
+		if inCluster[entity.(*simulator.VirtualMachine).Runtime.Host.Value] {
+			expected++
+		}
+	}
+	g.Expect(expected).To(gomega.BeNumerically(">", 0))
+	list := []model.VM{}
+	g.Expect(h.DB.List(&list, libmodel.ListOptions{Detail: 1})).NotTo(gomega.HaveOccurred())
+	g.Expect(list).To(gomega.HaveLen(expected))
+	for _, vm := range list {
+		g.Expect(inCluster[vm.Host.ID]).To(gomega.BeTrue())
+	}
+	// Only the included cluster and the hosts it contains.
+	clusters := []model.Cluster{}
+	g.Expect(h.DB.List(&clusters, libmodel.ListOptions{})).NotTo(gomega.HaveOccurred())
+	g.Expect(clusters).To(gomega.HaveLen(1))
+	g.Expect(clusters[0].ID).To(gomega.Equal(cluster.Self.Value))
+	g.Expect(len(simulator.Map.All(vsphere.Cluster))).To(gomega.BeNumerically(">", 1))
+	hosts := []model.Host{}
+	g.Expect(h.DB.List(&hosts, libmodel.ListOptions{Detail: 1})).NotTo(gomega.HaveOccurred())
+	g.Expect(hosts).To(gomega.HaveLen(len(cluster.Host)))
+	networks := map[string]bool{}
+	datastores := map[string]bool{}
+	for _, host := range hosts {
+		g.Expect(inCluster[host.ID]).To(gomega.BeTrue())
+		for _, ref := range host.Networks {
+			networks[ref.ID] = true
+		}
+		for _, ref := range host.Datastores {
+			datastores[ref.ID] = true
+		}
+	}
+	// Only the networks and datastores referenced by the hosts.
+	netList := []model.Network{}
+	g.Expect(h.DB.List(&netList, libmodel.ListOptions{})).NotTo(gomega.HaveOccurred())
+	g.Expect(netList).To(gomega.HaveLen(len(networks)))
+	for _, network := range netList {
+		g.Expect(networks[network.ID]).To(gomega.BeTrue())
+	}
+	dsList := []model.Datastore{}
+	g.Expect(h.DB.List(&dsList, libmodel.ListOptions{})).NotTo(gomega.HaveOccurred())
+	g.Expect(dsList).To(gomega.HaveLen(len(datastores)))
+	for _, ds := range dsList {
+		g.Expect(datastores[ds.ID]).To(gomega.BeTrue())
+	}
+}
+
+func TestScopeNotFound(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	h := &vcsim.Harness{}
+	defer h.Stop()
+	g.Expect(h.Start()).NotTo(gomega.HaveOccurred())
+	ctx := context.Background()
+	client, err := govmomi.NewClient(ctx, h.Server.URL, true)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	defer client.Logout(ctx)
+	scope := &vsphere.Scope{
+		Include: []string{"/DC0/host/DC0_C0", "/DC0/host/missing"},
+		Exclude: []string{"/DC0/vm/missing"},
+	}
+	g.Expect(scope.Resolve(ctx, client.Client)).To(gomega.HaveOccurred())
+	g.Expect(scope.NotFound).To(
+		gomega.Equal([]string{"/DC0/host/missing", "/DC0/vm/missing"}))
+}
+
+func TestScopeExclude(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	h := &vcsim.Harness{
+		Scope: &api.ProviderScope{
+			Exclude: []string{"/DC0/host/DC0_C0"},
+		},
+	}
+	defer h.Stop()
+	g.Expect(h.Start()).NotTo(gomega.HaveOccurred())
+	cluster := simulator.Map.Any(vsphere.Cluster).(*simulator.ClusterComputeResource)
+	list := []model.VM{}
+	g.Expect(h.DB.List(&list, libmodel.ListOptions{Detail: 1})).NotTo(gomega.HaveOccurred())
+	g.Expect(list).NotTo(gomega.BeEmpty())
+	for _, vm := range list {
+		for _, ref := range cluster.Host {
+			g.Expect(vm.Host.ID).NotTo(gomega.Equal(ref.Value))
+		}
+	}
+	g.Expect(h.DB.Get(&model.Cluster{Base: model.Base{ID: cluster.Self.Value}})).
+		To(gomega.MatchError(model.NotFound))
+}
+
+func TestDVSwitch(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	h := &vcsim.Harness{}
+	defer h.Stop()
+	g.Expect(h.Start()).NotTo(gomega.HaveOccurred())
+	simDVS := simulator.Map.Any(vsphere.DVSwitchBase).(*simulator.DistributedVirtualSwitch)
+	simPG := simulator.Map.Get(simDVS.Portgroup[len(simDVS.Portgroup)-1]).(*simulator.DistributedVirtualPortgroup)
+	// Collected.
+	dvs := &model.DVSwitch{Base: model.Base{ID: simDVS.Self.Value}}
+	g.Expect(h.DB.Get(dvs)).NotTo(gomega.HaveOccurred())
+	g.Expect(dvs.Name).To(gomega.Equal(simDVS.Name))
+	g.Expect(dvs.PortGroups).To(gomega.HaveLen(len(simDVS.Portgroup)))
+	network := &model.Network{Base: model.Base{ID: simPG.Self.Value}}
+	g.Expect(h.DB.Get(network)).NotTo(gomega.HaveOccurred())
+	g.Expect(network.DVSwitch.ID).To(gomega.Equal(simDVS.Self.Value))
+	g.Expect(network.VLAN.Type).To(gomega.Equal(model.VlanNone))
+	// Uplinks.
+	uplinks := []string{"uplink1", "uplink2"}
+	simulator.Map.WithLock(simDVS, func() {
+		policy := &types.DVSNameArrayUplinkPortPolicy{
+			UplinkPortName: uplinks,
+		}
+		simDVS.Config.GetDVSConfigInfo().UplinkPortPolicy = policy
+		simulator.Map.Update(
+			simDVS,
+			[]types.PropertyChange{
+				{Name: "config.uplinkPortPolicy", Val: policy},
+			})
+	})
+	g.Eventually(
+		func() []string {
+			dvs := &model.DVSwitch{Base: model.Base{ID: simDVS.Self.Value}}
+			_ = h.DB.Get(dvs)
+			return dvs.Uplinks
+		},
+		time.Second*10).Should(gomega.Equal(uplinks))
+	// VLAN.
+	setVLAN := func(vlan types.BaseVmwareDistributedVirtualSwitchVlanSpec) {
+		simulator.Map.WithLock(simPG, func() {
+			setting := &types.VMwareDVSPortSetting{Vlan: vlan}
+			simPG.Config.DefaultPortConfig = setting
+			simulator.Map.Update(
+				simPG,
+				[]types.PropertyChange{
+					{Name: "config.defaultPortConfig", Val: setting},
+				})
+		})
+	}
+	vlan := func() model.VLAN {
+		network := &model.Network{Base: model.Base{ID: simPG.Self.Value}}
+		_ = h.DB.Get(network)
+		return network.VLAN
+	}
+	setVLAN(&types.VmwareDistributedVirtualSwitchVlanIdSpec{VlanId: 100})
+	g.Eventually(vlan, time.Second*10).Should(
+		gomega.Equal(
+			model.VLAN{
+				Type: model.VlanID,
+				ID:   100,
+			}))
+	// Trunk.
+	setVLAN(
+		&types.VmwareDistributedVirtualSwitchTrunkVlanSpec{
+			VlanId: []types.NumericRange{
+				{Start: 10, End: 20},
+			},
+		})
+	g.Eventually(vlan, time.Second*10).Should(
+		gomega.Equal(
+			model.VLAN{
+				Type: model.VlanTrunk,
+				Ranges: []model.VLANRange{
+					{Start: 10, End: 20},
+				},
+			}))
+}
+
+func TestPerf(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	h := &vcsim.Harness{}
+	defer h.Stop()
+	g.Expect(h.Start()).NotTo(gomega.HaveOccurred())
+	ctx := context.Background()
+	client, err := govmomi.NewClient(ctx, h.Server.URL, true)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	defer func() {
+		_ = client.Logout(ctx)
+	}()
+	vsphere.Settings.Inventory.Perf.Windows = []int{1, 24}
+	// Collected for powered-on VMs.
+	for i := 0; i < 3; i++ {
+		err = h.Reconciler.CollectPerf(ctx, client.Client, time.Minute*5)
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+	}
+	poweredOn := 0
+	for _, entity := range simulator.Map.All(vsphere.VirtualMachine) {
+		simVM := entity.(*simulator.VirtualMachine)
+		if simVM.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOn {
+			continue
+		}
+		poweredOn++
+		m := &model.VMMetrics{Base: model.Base{ID: simVM.Self.Value}}
+		g.Expect(h.DB.Get(m)).NotTo(gomega.HaveOccurred())
+		g.Expect(m.Samples).To(gomega.HaveLen(3))
+		g.Expect(m.Windows).To(gomega.HaveLen(2))
+		g.Expect(m.Windows[0].Window).To(gomega.Equal("1h"))
+		g.Expect(m.Windows[1].Window).To(gomega.Equal("24h"))
+		for _, window := range m.Windows {
+			g.Expect(window.Samples).To(gomega.Equal(3))
+			cpu, found := window.Metrics[model.CpuUsageMHz]
+			g.Expect(found).To(gomega.BeTrue())
+			g.Expect(cpu.Peak).To(gomega.BeNumerically(">=", cpu.IntervalP95))
+			g.Expect(cpu.IntervalP95).To(gomega.BeNumerically(">=", 0))
+		}
+	}
+	g.Expect(poweredOn).To(gomega.BeNumerically(">", 0))
+	list := []model.VMMetrics{}
+	g.Expect(h.DB.List(&list, libmodel.ListOptions{})).NotTo(gomega.HaveOccurred())
+	g.Expect(list).To(gomega.HaveLen(poweredOn))
+}
+
+func TestTags(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	h := &vcsim.Harness{}
+	defer h.Stop()
+	g.Expect(h.Start()).NotTo(gomega.HaveOccurred())
+	ctx := context.Background()
+	client, err := govmomi.NewClient(ctx, h.Server.URL, true)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	defer func() {
+		_ = client.Logout(ctx)
+	}()
+	rc := rest.NewClient(client.Client)
+	g.Expect(rc.Login(ctx, h.Server.URL.User)).NotTo(gomega.HaveOccurred())
+	defer func() {
+		_ = rc.Logout(ctx)
+	}()
+	manager := tags.NewManager(rc)
+	categoryID, err := manager.CreateCategory(ctx, &tags.Category{Name: "env"})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	prodID, err := manager.CreateTag(ctx, &tags.Tag{Name: "prod", CategoryID: categoryID})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	dbID, err := manager.CreateTag(ctx, &tags.Tag{Name: "db", CategoryID: categoryID})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	simVM := simulator.Map.All(vsphere.VirtualMachine)[0].(*simulator.VirtualMachine)
+	simHost := simulator.Map.All(vsphere.Host)[0].(*simulator.HostSystem)
+	g.Expect(manager.AttachTag(ctx, prodID, simVM.Self)).NotTo(gomega.HaveOccurred())
+	g.Expect(manager.AttachTag(ctx, dbID, simVM.Self)).NotTo(gomega.HaveOccurred())
+	g.Expect(manager.AttachTag(ctx, prodID, simHost.Self)).NotTo(gomega.HaveOccurred())
+
+	// Attached.
+	g.Expect(h.Reconciler.RefreshTags(ctx, client.Client)).NotTo(gomega.HaveOccurred())
+	vm := &model.VM{Base: model.Base{ID: simVM.Self.Value}}
+	g.Expect(h.DB.Get(vm)).NotTo(gomega.HaveOccurred())
+	g.Expect(vm.Tags).To(
+		gomega.Equal(
+			[]model.Tag{
+				{Category: "env", Name: "db"},
+				{Category: "env", Name: "prod"},
+			}))
+	host := &model.Host{Base: model.Base{ID: simHost.Self.Value}}
+	g.Expect(h.DB.Get(host)).NotTo(gomega.HaveOccurred())
+	g.Expect(host.Tags).To(gomega.Equal([]model.Tag{{Category: "env", Name: "prod"}}))
+	other := &model.VM{Base: model.Base{ID: simulator.Map.All(vsphere.VirtualMachine)[1].Reference().Value}}
+	g.Expect(h.DB.Get(other)).NotTo(gomega.HaveOccurred())
+	g.Expect(other.Tags).To(gomega.BeEmpty())
+
+	// Detached.
+	g.Expect(manager.DetachTag(ctx, dbID, simVM.Self)).NotTo(gomega.HaveOccurred())
+	g.Expect(h.Reconciler.RefreshTags(ctx, client.Client)).NotTo(gomega.HaveOccurred())
+	g.Expect(h.DB.Get(vm)).NotTo(gomega.HaveOccurred())
+	g.Expect(vm.Tags).To(gomega.Equal([]model.Tag{{Category: "env", Name: "prod"}}))
+}
+
+func TestResourcePools(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	h := &vcsim.Harness{}
+	defer h.Stop()
+	g.Expect(h.Start()).NotTo(gomega.HaveOccurred())
+	ctx := context.Background()
+	client, err := govmomi.NewClient(ctx, h.Server.URL, true)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	defer func() {
+		_ = client.Logout(ctx)
+	}()
+	simCluster := simulator.Map.Any(vsphere.Cluster).(*simulator.ClusterComputeResource)
+	rootRef := *simCluster.ResourcePool
+
+	// Root pool.
+	pool := &model.ResourcePool{Base: model.Base{ID: rootRef.Value}}
+	g.Expect(h.DB.Get(pool)).NotTo(gomega.HaveOccurred())
+	g.Expect(pool.Owner.ID).To(gomega.Equal(simCluster.Self.Value))
+	g.Expect(pool.Owner.Kind).To(gomega.Equal(model.ClusterKind))
+	simRoot := simulator.Map.Get(rootRef).(*simulator.ResourcePool)
+	g.Expect(pool.CpuAllocation.Limit).To(gomega.Equal(*simRoot.Config.CpuAllocation.Limit))
+	g.Expect(pool.MemoryAllocation.Reservation).To(gomega.Equal(*simRoot.Config.MemoryAllocation.Reservation))
+
+	// Child pool and vApp.
+	allocation := func(limit int64) types.ResourceAllocationInfo {
+		return types.ResourceAllocationInfo{
+			Reservation:           types.NewInt64(0),
+			Limit:                 types.NewInt64(limit),
+			ExpandableReservation: types.NewBool(true),
+			Shares: &types.SharesInfo{
+				Level: types.SharesLevelNormal,
+			},
+		}
+	}
+	spec := types.ResourceConfigSpec{
+		CpuAllocation:    allocation(2000),
+		MemoryAllocation: allocation(4096),
+	}
+	root := object.NewResourcePool(client.Client, rootRef)
+	child, err := root.Create(ctx, "child", spec)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	vApp, err := child.CreateVApp(ctx, "app", spec, types.VAppConfigSpec{}, nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Eventually(
+		func() error {
+			return h.DB.Get(&model.ResourcePool{Base: model.Base{ID: child.Reference().Value}})
+		},
+		time.Second*10).ShouldNot(gomega.HaveOccurred())
+	pool = &model.ResourcePool{Base: model.Base{ID: child.Reference().Value}}
+	g.Expect(h.DB.Get(pool)).NotTo(gomega.HaveOccurred())
+	g.Expect(pool.Name).To(gomega.Equal("child"))
+	g.Expect(pool.Parent.ID).To(gomega.Equal(rootRef.Value))
+	g.Expect(pool.Parent.Kind).To(gomega.Equal(model.PoolKind))
+	g.Expect(pool.CpuAllocation.Limit).To(gomega.Equal(int64(2000)))
+	g.Expect(pool.MemoryAllocation.Limit).To(gomega.Equal(int64(4096)))
+	g.Expect(pool.MemoryAllocation.Expandable).To(gomega.BeTrue())
+	g.Expect(pool.MemoryAllocation.SharesLevel).To(gomega.Equal(string(types.SharesLevelNormal)))
+	g.Eventually(
+		func() error {
+			return h.DB.Get(&model.VApp{ResourcePool: model.ResourcePool{Base: model.Base{ID: vApp.Reference().Value}}})
+		},
+		time.Second*10).ShouldNot(gomega.HaveOccurred())
+	app := &model.VApp{ResourcePool: model.ResourcePool{Base: model.Base{ID: vApp.Reference().Value}}}
+	g.Expect(h.DB.Get(app)).NotTo(gomega.HaveOccurred())
+	g.Expect(app.Name).To(gomega.Equal("app"))
+	g.Expect(app.Parent.ID).To(gomega.Equal(child.Reference().Value))
+	g.Expect(app.CpuAllocation.Limit).To(gomega.Equal(int64(2000)))
+
+	// VM allocation.
+	simVM := simulator.Map.All(vsphere.VirtualMachine)[0].(*simulator.VirtualMachine)
+	simulator.Map.WithLock(simVM, func() {
+		cpu := &types.ResourceAllocationInfo{
+			Reservation: types.NewInt64(500),
+			Limit:       types.NewInt64(1000),
+		}
+		simVM.Config.CpuAllocation = cpu
+		simulator.Map.Update(
+			simVM,
+			[]types.PropertyChange{
+				{Name: "config.cpuAllocation", Val: cpu},
+			})
+	})
+	g.Eventually(
+		func() []int64 {
+			vm := &model.VM{Base: model.Base{ID: simVM.Self.Value}}
+			_ = h.DB.Get(vm)
+			return []int64{
+				vm.CpuAllocation.Reservation,
+				vm.CpuAllocation.Limit,
+			}
+		},
+		time.Second*10).Should(gomega.Equal([]int64{500, 1000}))
+
+	// Host CPU speed.
+	simHost := simulator.Map.Get(*simVM.Runtime.Host).(*simulator.HostSystem)
+	host := &model.Host{Base: model.Base{ID: simHost.Self.Value}}
+	g.Expect(h.DB.Get(host)).NotTo(gomega.HaveOccurred())
+	g.Expect(host.CpuMhz).To(gomega.Equal(simHost.Summary.Hardware.CpuMhz))
+}
+
+func TestHealthChanged(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	for len(inventory.HealthChanged) > 0 {
+		<-inventory.HealthChanged
+	}
+	provider := &api.Provider{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "test",
+			Name:      "health",
+		},
+	}
+	r := vsphere.New(nil, provider, &core.Secret{})
+	changed := func() (names []string) {
+		for len(inventory.HealthChanged) > 0 {
+			event := <-inventory.HealthChanged
+			names = append(names, event.Meta.GetNamespace()+"/"+event.Meta.GetName())
+		}
+		return
+	}
+
+	// Reported.
+	r.Stale(true)
+	g.Expect(changed()).To(gomega.Equal([]string{"test/health"}))
+	r.Failed(errors.New("login failed"))
+	g.Expect(changed()).To(gomega.HaveLen(1))
+
+	// Not reported (unchanged).
+	r.Stale(true)
+	r.Failed(errors.New("login failed"))
+	g.Expect(changed()).To(gomega.BeEmpty())
+
+	// Reported (cleared).
+	r.Stale(false)
+	g.Expect(changed()).To(gomega.HaveLen(1))
+}
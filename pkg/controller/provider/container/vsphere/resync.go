@@ -0,0 +1,152 @@
+package vsphere
+
+import (
+	"errors"
+	"fmt"
+	liberr "github.com/konveyor/controller/pkg/error"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"reflect"
+)
+
+//
+// Resync.
+// The property collector version is scoped to the session so
+// updates cannot be resumed (incrementally) after a restart. The
+// cached inventory is served (stale) until the initial (full) sync
+// has been reconciled with it. Objects are updated only when changed
+// and those no longer reported by the provider are deleted once
+// consistent.
+
+//
+// Determine if the DB contains the (stale) inventory
+// from a previous (full) sync.
+func (r *Reconciler) cached() (cached bool, err error) {
+	about := &model.About{}
+	err = r.db.Get(about)
+	if err != nil {
+		if errors.Is(err, model.NotFound) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	cached = about.Synced
+
+	return
+}
+
+//
+// Create/update the `About`.
+// The sync status is retained.
+func (r *Reconciler) updateAbout(apiVersion, product string) (err error) {
+	about := &model.About{}
+	err = r.db.Get(about)
+	if err != nil && !errors.Is(err, model.NotFound) {
+		err = liberr.Wrap(err)
+		return
+	}
+	found := err == nil
+	about.APIVersion = apiVersion
+	about.Product = product
+	if found {
+		err = r.db.Update(about)
+	} else {
+		err = r.db.Insert(about)
+	}
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.about = about
+
+	return
+}
+
+//
+// Mark the object as reported during the initial sync.
+func (r *Reconciler) reported(m libmodel.Model) {
+	if r.seen != nil {
+		r.seen[r.key(m)] = true
+	}
+}
+
+//
+// Seen map key.
+func (r *Reconciler) key(m libmodel.Model) string {
+	return fmt.Sprintf("%T/%s", m, m.Pk())
+}
+
+//
+// Initial sync completed.
+// Delete the (cached) objects not reported by the provider
+// and mark the inventory as synced.
+func (r *Reconciler) synced() (err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer func() {
+		_ = tx.End()
+	}()
+	for _, kind := range []interface{}{
+		&model.Folder{},
+		&model.Datacenter{},
+		&model.Cluster{},
+		&model.Host{},
+		&model.Network{},
+		&model.DVSwitch{},
+		&model.Datastore{},
+		&model.ResourcePool{},
+		&model.VApp{},
+		&model.VM{},
+	} {
+		mt := reflect.TypeOf(kind).Elem()
+		list := reflect.New(reflect.SliceOf(mt))
+		err = tx.List(list.Interface(), libmodel.ListOptions{})
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		for i := 0; i < list.Elem().Len(); i++ {
+			m := list.Elem().Index(i).Addr().Interface().(libmodel.Model)
+			if r.seen[r.key(m)] {
+				continue
+			}
+			r.log.Info("Delete (stale)", "model", m.String())
+			err = tx.Delete(m)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+			if vm, cast := m.(*model.VM); cast {
+				err = tx.Delete(
+					&model.VMMetrics{
+						Base: model.Base{ID: vm.ID},
+					})
+				if err != nil {
+					err = liberr.Wrap(err)
+					return
+				}
+			}
+		}
+	}
+	if r.about != nil {
+		r.about.Synced = true
+		err = tx.Update(r.about)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.seen = nil
+
+	return
+}
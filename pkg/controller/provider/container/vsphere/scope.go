@@ -0,0 +1,406 @@
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	liberr "github.com/konveyor/controller/pkg/error"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/view"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"strings"
+)
+
+//
+// Inventory scope.
+// Restricts the collection to the datacenters, clusters and
+// folders (inventory paths) listed in the provider spec.
+// When paths are included, the property collector traverses only:
+//   - VM folders (and vApps) along with the datacenters containing them.
+//   - clusters, hosts and host folders along with the VMs, networks
+//     and datastores referenced by the contained hosts.
+// VMs, resource pools and vApps are collected only when contained
+// by an included path. Objects contained by an excluded path are
+// never collected.
+// Membership is determined when the object is first reported
+// and re-evaluated on each (re)connect.
+type Scope struct {
+	// Included inventory paths.
+	Include []string
+	// Excluded inventory paths.
+	Exclude []string
+	// Inventory paths not found.
+	NotFound []string
+	// Included object IDs.
+	included map[string]bool
+	// Excluded object IDs.
+	excluded map[string]bool
+	// Dropped (not collected) object IDs.
+	dropped map[string]bool
+	// Property collector object specs.
+	objectSpec []types.ObjectSpec
+}
+
+//
+// Build the scope.
+func NewScope(provider *api.Provider) (scope *Scope) {
+	scope = &Scope{}
+	if provider.Spec.Scope != nil {
+		scope.Include = provider.Spec.Scope.Include
+		scope.Exclude = provider.Spec.Scope.Exclude
+	}
+	scope.reset()
+
+	return
+}
+
+//
+// The collection is restricted to included paths.
+func (r *Scope) Scoped() bool {
+	return len(r.Include) > 0
+}
+
+//
+// Reset the resolved scope.
+func (r *Scope) reset() {
+	r.included = map[string]bool{}
+	r.excluded = map[string]bool{}
+	r.dropped = map[string]bool{}
+	r.objectSpec = nil
+	r.NotFound = nil
+}
+
+//
+// Resolve the inventory paths.
+// Builds the included/excluded object sets and
+// the property collector object specs.
+func (r *Scope) resolve(ctx context.Context, client *vim25.Client) (err error) {
+	r.reset()
+	refs := map[string]types.ManagedObjectReference{}
+	for _, path := range append(r.Include, r.Exclude...) {
+		var ref *types.ManagedObjectReference
+		ref, err = r.find(ctx, client, path)
+		if err != nil {
+			return
+		}
+		if ref != nil {
+			refs[path] = *ref
+		} else {
+			r.NotFound = append(r.NotFound, path)
+		}
+	}
+	if len(r.NotFound) > 0 {
+		err = liberr.New(
+			fmt.Sprintf(
+				"Scope path(s): %s not found.",
+				strings.Join(r.NotFound, ", ")))
+		return
+	}
+	for _, path := range r.Exclude {
+		err = r.members(ctx, client, refs[path], r.excluded)
+		if err != nil {
+			return
+		}
+	}
+	added := map[types.ManagedObjectReference]bool{}
+	add := func(spec types.ObjectSpec) {
+		if !added[spec.Obj] {
+			added[spec.Obj] = true
+			r.objectSpec = append(r.objectSpec, spec)
+		}
+	}
+	datacenters := []types.ManagedObjectReference{}
+	vmFolder := map[types.ManagedObjectReference]bool{}
+	ancestors := []types.ManagedObjectReference{}
+	for _, path := range r.Include {
+		ref := refs[path]
+		err = r.members(ctx, client, ref, r.included)
+		if err != nil {
+			return
+		}
+		var parents []types.ManagedObjectReference
+		parents, err = r.ancestors(ctx, client, ref)
+		if err != nil {
+			return
+		}
+		ancestors = append(ancestors, parents...)
+		var dc *types.ManagedObjectReference
+		for _, p := range append([]types.ManagedObjectReference{ref}, parents...) {
+			if p.Type == Datacenter {
+				dc = &p
+				break
+			}
+		}
+		if dc == nil {
+			// Root (or datacenter) folder.
+			add(types.ObjectSpec{
+				Obj: ref,
+				SelectSet: []types.BaseSelectionSpec{
+					TsRootFolder,
+				},
+			})
+			continue
+		}
+		if ref == *dc {
+			if _, found := vmFolder[ref]; !found {
+				datacenters = append(datacenters, ref)
+			}
+			vmFolder[ref] = true
+			continue
+		}
+		var tree string
+		tree, err = r.tree(ctx, client, ref)
+		if err != nil {
+			return
+		}
+		switch tree {
+		case VirtualMachine:
+			// VMs may run on any host and use any network
+			// or datastore within the datacenter.
+			if _, found := vmFolder[*dc]; !found {
+				datacenters = append(datacenters, *dc)
+				vmFolder[*dc] = false
+			}
+			switch ref.Type {
+			case Folder:
+				add(types.ObjectSpec{
+					Obj: ref,
+					SelectSet: []types.BaseSelectionSpec{
+						TsRootFolder,
+					},
+				})
+			case VirtualApp:
+				add(types.ObjectSpec{
+					Obj: ref,
+					SelectSet: []types.BaseSelectionSpec{
+						TsResourcePool,
+						TsVAppVM,
+					},
+				})
+			default:
+				add(types.ObjectSpec{Obj: ref})
+			}
+		case ComputeRes:
+			// Only the hosts (and the VMs, networks and datastores
+			// they reference) contained by the path are traversed.
+			// The VMs in resource pools are traversed through the
+			// hosts of the owner (compute resource).
+			if ref.Type == ResourcePool {
+				for _, p := range parents {
+					if p.Type == Cluster || p.Type == ComputeRes {
+						ref = p
+						break
+					}
+				}
+			}
+			add(types.ObjectSpec{
+				Obj: ref,
+				SelectSet: []types.BaseSelectionSpec{
+					TsComputeFolder,
+					TsComputeResourceHost,
+					TsComputeResourcePool,
+					TsResourcePool,
+					TsVAppVM,
+					TsHostVM,
+					TsHostNetwork,
+					TsHostDatastore,
+				},
+			})
+		default:
+			// Network and datastore folders.
+			add(types.ObjectSpec{
+				Obj: ref,
+				SelectSet: []types.BaseSelectionSpec{
+					TsRootFolder,
+				},
+			})
+		}
+	}
+	for _, dc := range datacenters {
+		selectSet := []types.BaseSelectionSpec{
+			TsDatacenterHost,
+			TsDatacenterNet,
+			TsDatacenterDatastore,
+			TsRootFolder,
+		}
+		if vmFolder[dc] {
+			selectSet = append(selectSet, TsDatacenterVM)
+		}
+		add(types.ObjectSpec{
+			Obj:       dc,
+			SelectSet: selectSet,
+		})
+	}
+	for _, ref := range ancestors {
+		add(types.ObjectSpec{Obj: ref})
+	}
+
+	return
+}
+
+//
+// Find an object by inventory path.
+// Returns nil when not found.
+func (r *Scope) find(ctx context.Context, client *vim25.Client, path string) (ref *types.ManagedObjectReference, err error) {
+	if strings.Trim(path, "/") == "" {
+		ref = &client.ServiceContent.RootFolder
+		return
+	}
+	found, err := object.NewSearchIndex(client).FindByInventoryPath(ctx, path)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if found != nil {
+		mRef := found.Reference()
+		ref = &mRef
+	}
+
+	return
+}
+
+//
+// Add the object and the objects it contains to the set.
+func (r *Scope) members(ctx context.Context, client *vim25.Client, ref types.ManagedObjectReference, set map[string]bool) (err error) {
+	set[ref.Value] = true
+	switch ref.Type {
+	case Folder,
+		Datacenter,
+		Cluster,
+		ComputeRes,
+		Host,
+		ResourcePool,
+		VirtualApp:
+	default:
+		return
+	}
+	container, err := view.NewManager(client).CreateContainerView(ctx, ref, nil, true)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer container.Destroy(context.Background())
+	list, err := container.Find(ctx, nil, nil)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, m := range list {
+		set[m.Value] = true
+	}
+
+	return
+}
+
+//
+// Ancestors of an object ordered from parent to the root folder.
+func (r *Scope) ancestors(ctx context.Context, client *vim25.Client, ref types.ManagedObjectReference) (list []types.ManagedObjectReference, err error) {
+	pc := property.DefaultCollector(client)
+	for {
+		entity := mo.ManagedEntity{}
+		err = pc.RetrieveOne(ctx, ref, []string{fParent}, &entity)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		if entity.Parent == nil {
+			break
+		}
+		ref = *entity.Parent
+		list = append(list, ref)
+	}
+
+	return
+}
+
+//
+// Determine the tree containing the object.
+// Returns VirtualMachine for the VM (folder) tree and ComputeRes
+// for the host (folder) tree. VMs contained by clusters, hosts and
+// resource pools are reported through the VM folder of the datacenter.
+func (r *Scope) tree(ctx context.Context, client *vim25.Client, ref types.ManagedObjectReference) (tree string, err error) {
+	switch ref.Type {
+	case VirtualMachine, VirtualApp:
+		tree = VirtualMachine
+	case Cluster, ComputeRes, Host, ResourcePool:
+		tree = ComputeRes
+	case Folder:
+		folder := mo.Folder{}
+		pc := property.DefaultCollector(client)
+		err = pc.RetrieveOne(ctx, ref, []string{fChildType}, &folder)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		for _, kind := range folder.ChildType {
+			switch kind {
+			case VirtualMachine, ComputeRes:
+				tree = kind
+				return
+			}
+		}
+	}
+
+	return
+}
+
+//
+// Determine if the reported (enter) object is within
+// the scope. Objects referencing (contained by) an included
+// or excluded object are added to the respective set so that
+// objects created later are evaluated the same.
+func (r *Scope) admit(u types.ObjectUpdate) (admitted bool) {
+	id := u.Obj.Value
+	defer func() {
+		if admitted {
+			delete(r.dropped, id)
+		} else {
+			r.dropped[id] = true
+		}
+	}()
+	if len(r.Include) == 0 && len(r.Exclude) == 0 {
+		admitted = true
+		return
+	}
+	refs := []string{id}
+	for _, p := range u.ChangeSet {
+		switch p.Name {
+		case fParent, fResourcePool, fRuntimeHost, fParentVApp:
+			if ref, cast := p.Val.(types.ManagedObjectReference); cast {
+				refs = append(refs, ref.Value)
+			}
+		}
+	}
+	for _, ref := range refs {
+		if r.excluded[ref] {
+			r.excluded[id] = true
+			return
+		}
+	}
+	switch u.Obj.Type {
+	case VirtualMachine, ResourcePool, VirtualApp:
+		if !r.Scoped() {
+			break
+		}
+		for _, ref := range refs {
+			if r.included[ref] {
+				r.included[id] = true
+				admitted = true
+				return
+			}
+		}
+		return
+	}
+
+	admitted = true
+	return
+}
+
+//
+// Determine if the object is collected.
+func (r *Scope) collected(u types.ObjectUpdate) bool {
+	return !r.dropped[u.Obj.Value]
+}
@@ -0,0 +1,238 @@
+package vsphere
+
+import (
+	"context"
+	liberr "github.com/konveyor/controller/pkg/error"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25"
+	liburl "net/url"
+	"reflect"
+	"sort"
+	"time"
+)
+
+//
+// Tagged objects.
+// Maps object (type/ID) to tags.
+type tagged map[string][]model.Tag
+
+//
+// Object key.
+func (t tagged) key(kind, id string) string {
+	return kind + "/" + id
+}
+
+//
+// Tags for the object.
+func (t tagged) find(kind, id string) (list []model.Tag) {
+	list = t[t.key(kind, id)]
+	if list == nil {
+		list = []model.Tag{}
+	}
+
+	return
+}
+
+//
+// Periodically refresh tags.
+// Tags are managed by the vAPI tagging service and are
+// not reported by the property collector. The tagging
+// service is only provided by vCenter.
+func (r *Reconciler) watchTags(ctx context.Context, client *vim25.Client) {
+	if !client.IsVC() {
+		return
+	}
+	for {
+		err := r.refreshTags(ctx, client)
+		if err != nil {
+			r.log.Trace(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(TagRefreshInterval):
+		}
+	}
+}
+
+//
+// Refresh tags.
+// Fetch the tags and associated objects and update the
+// VM, host and datastore models as needed.
+func (r *Reconciler) refreshTags(ctx context.Context, client *vim25.Client) (err error) {
+	rc := rest.NewClient(client)
+	err = rc.Login(ctx, liburl.UserPassword(r.user(), r.password()))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer rc.Logout(context.Background())
+	found, err := r.fetchTags(ctx, tags.NewManager(rc))
+	if err != nil {
+		return
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer tx.End()
+	err = r.applyTags(tx, found)
+	if err != nil {
+		return
+	}
+	err = tx.Commit()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
+//
+// Fetch tags and the objects to which they are attached.
+func (r *Reconciler) fetchTags(ctx context.Context, manager *tags.Manager) (found tagged, err error) {
+	found = tagged{}
+	categories, err := manager.GetCategories(ctx)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	categoryName := map[string]string{}
+	for _, c := range categories {
+		categoryName[c.ID] = c.Name
+	}
+	tagList, err := manager.GetTags(ctx)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if len(tagList) == 0 {
+		return
+	}
+	byID := map[string]model.Tag{}
+	ids := []string{}
+	for _, t := range tagList {
+		byID[t.ID] = model.Tag{
+			Category: categoryName[t.CategoryID],
+			Name:     t.Name,
+		}
+		ids = append(ids, t.ID)
+	}
+	attached, err := manager.ListAttachedObjectsOnTags(ctx, ids)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, a := range attached {
+		tag, known := byID[a.TagID]
+		if !known {
+			continue
+		}
+		for _, object := range a.ObjectIDs {
+			ref := object.Reference()
+			key := found.key(ref.Type, ref.Value)
+			found[key] = append(found[key], tag)
+		}
+	}
+	for _, list := range found {
+		sort.Slice(
+			list,
+			func(i, j int) bool {
+				if list[i].Category != list[j].Category {
+					return list[i].Category < list[j].Category
+				}
+				return list[i].Name < list[j].Name
+			})
+	}
+
+	return
+}
+
+//
+// Update the tags on VM, host and datastore models.
+// Only changed models are updated.
+func (r *Reconciler) applyTags(tx *libmodel.Tx, found tagged) (err error) {
+	vmList := []model.VM{}
+	err = tx.List(&vmList, libmodel.ListOptions{Detail: 1})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range vmList {
+		m := &vmList[i]
+		list := found.find(VirtualMachine, m.ID)
+		if r.tagsChanged(m.Tags, list) {
+			m.Tags = list
+			err = r.updateTagged(tx, m)
+			if err != nil {
+				return
+			}
+		}
+	}
+	hostList := []model.Host{}
+	err = tx.List(&hostList, libmodel.ListOptions{Detail: 1})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range hostList {
+		m := &hostList[i]
+		list := found.find(Host, m.ID)
+		if r.tagsChanged(m.Tags, list) {
+			m.Tags = list
+			err = r.updateTagged(tx, m)
+			if err != nil {
+				return
+			}
+		}
+	}
+	dsList := []model.Datastore{}
+	err = tx.List(&dsList, libmodel.ListOptions{Detail: 1})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range dsList {
+		m := &dsList[i]
+		list := found.find(Datastore, m.ID)
+		if r.tagsChanged(m.Tags, list) {
+			m.Tags = list
+			err = r.updateTagged(tx, m)
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	return
+}
+
+//
+// Update a tagged model.
+func (r *Reconciler) updateTagged(tx *libmodel.Tx, m model.Model) (err error) {
+	if mX, cast := m.(interface{ Updated() }); cast {
+		mX.Updated()
+	}
+	r.log.Info("Update (tags)", "model", m.String())
+	err = tx.Update(m)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
+
+//
+// Determine if the tags have changed.
+func (r *Reconciler) tagsChanged(current, found []model.Tag) bool {
+	if len(current) == 0 && len(found) == 0 {
+		return false
+	}
+
+	return !reflect.DeepEqual(current, found)
+}
@@ -11,6 +11,8 @@ import (
 // Watch for VM changes and analyze as needed.
 type WatchVM struct {
 	libmodel.DB
+	// VM analyzers.
+	Analyzers []Analyzer
 }
 
 //
@@ -60,21 +62,33 @@ func (r WatchVM) End() {
 
 //
 // Analyze the VM.
+// The concerns reported by each analyzer are stored
+// on the VM unless it was updated during the analysis.
 func (r WatchVM) analyze(vm *model.VM) {
+	concerns := []model.Concern{}
+	for _, analyzer := range r.Analyzers {
+		list, err := analyzer.Analyze(r.DB, vm)
+		if err != nil {
+			Log.Trace(err)
+		}
+		concerns = append(concerns, list...)
+	}
 	tx, err := r.DB.Begin()
 	if err != nil {
 		Log.Trace(liberr.Wrap(err))
 		return
 	}
 	defer tx.End()
+	revision := vm.Revision
 	err = tx.Get(vm)
 	if err != nil {
 		Log.Trace(liberr.Wrap(err))
 		return
 	}
-
-	// TODO: Open Policy Agent - HERE
-
+	if vm.Revision != revision {
+		return
+	}
+	vm.Concerns = concerns
 	vm.RevisionAnalyzed = vm.Revision
 	err = tx.Update(vm)
 	if err != nil {
@@ -18,6 +18,8 @@ package provider
 
 import (
 	"context"
+	"crypto/sha1"
+	"encoding/hex"
 	libcnd "github.com/konveyor/controller/pkg/condition"
 	liberr "github.com/konveyor/controller/pkg/error"
 	libcontainer "github.com/konveyor/controller/pkg/inventory/container"
@@ -26,6 +28,7 @@ import (
 	"github.com/konveyor/controller/pkg/logging"
 	libref "github.com/konveyor/controller/pkg/ref"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/auth"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/container"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/model"
 	ocpmodel "github.com/konveyor/forklift-controller/pkg/controller/provider/model/ocp"
@@ -134,6 +137,29 @@ func Add(mgr manager.Manager) error {
 		log.Trace(err)
 		return err
 	}
+	// Inventory collector health.
+	err = cnt.Watch(
+		&source.Channel{
+			Source: model.HealthChanged,
+		},
+		&handler.EnqueueRequestForObject{})
+	if err != nil {
+		log.Trace(err)
+		return err
+	}
+	// Analysis rule set ConfigMap.
+	if Settings.Inventory.AnalysisConfigMap.Name != "" {
+		err = cnt.Watch(
+			&source.Kind{
+				Type: &core.ConfigMap{},
+			},
+			&handler.EnqueueRequestForObject{},
+			&RulePredicate{})
+		if err != nil {
+			log.Trace(err)
+			return err
+		}
+	}
 
 	return nil
 }
@@ -186,6 +212,7 @@ func (r *Reconciler) Reconcile(request reconcile.Request) (result reconcile.Resu
 				r.Shutdown()
 				r.DB().Close(true)
 			}
+			auth.Delete(deleted)
 		} else {
 			result = fastReQ
 		}
@@ -305,13 +332,18 @@ func (r *Reconciler) updateContainer(provider *api.Provider) (err error) {
 	if err != nil {
 		return liberr.Wrap(err)
 	}
-	err = db.Open(true)
+	err = db.Open(!r.warmStart(provider))
 	if err != nil {
 		return liberr.Wrap(err)
 	}
 	pModel := &ocpmodel.Provider{}
 	pModel.With(provider)
-	err = db.Insert(pModel)
+	err = db.Get(&ocpmodel.Provider{Base: pModel.Base})
+	if err == nil {
+		err = db.Update(pModel)
+	} else {
+		err = db.Insert(pModel)
+	}
 	if err != nil {
 		return liberr.Wrap(err)
 	}
@@ -327,20 +359,59 @@ func (r *Reconciler) updateContainer(provider *api.Provider) (err error) {
 	return nil
 }
 
+//
+// Determine if the (cached) inventory DB may be retained.
+// Supported by vSphere providers when the spec has not
+// been changed.
+func (r *Reconciler) warmStart(provider *api.Provider) bool {
+	return Settings.Inventory.WarmStart &&
+		provider.Type() == api.VSphere &&
+		provider.HasReconciled()
+}
+
 //
 // Build DB for provider.
+// The DB file is named using the digest of the schema so that
+// a (cached) DB with a different schema is not opened. DB files
+// for other schemas (and the legacy DB file) are deleted.
 func (r *Reconciler) getDB(provider *api.Provider) (db libmodel.DB) {
 	dir := Settings.Inventory.WorkingDir
-	dir = filepath.Join(dir, provider.Namespace)
+	dir = filepath.Join(dir, provider.Namespace, provider.Name)
 	os.MkdirAll(dir, 0755)
-	file := provider.Name + ".db"
-	path := filepath.Join(dir, file)
 	models := model.Models(provider)
+	file := r.schema(models) + ".db"
+	path := filepath.Join(dir, file)
+	_ = os.Remove(dir + ".db")
+	if found, err := filepath.Glob(filepath.Join(dir, "*.db")); err == nil {
+		for _, other := range found {
+			if other != path {
+				_ = os.Remove(other)
+			}
+		}
+	}
 	db = libmodel.New(path, models...)
 	db.Journal().Enable()
 	return
 }
 
+//
+// Schema digest.
+func (r *Reconciler) schema(models []interface{}) string {
+	h := sha1.New()
+	for _, m := range models {
+		ddl, err := libmodel.Table{}.DDL(m)
+		if err != nil {
+			log.Trace(err)
+			continue
+		}
+		for _, stmt := range ddl {
+			h.Write([]byte(stmt))
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
 //
 // Get the secret referenced by the provider.
 func (r *Reconciler) getSecret(provider *api.Provider) (*core.Secret, error) {
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"time"
+)
+
+//
+// Health change events.
+// Sent (without blocking) by the inventory collector when the
+// reported health has changed. The event object is the provider.
+// Watched by the provider controller so the provider conditions
+// reflect the health without polling the collector.
+var HealthChanged = make(chan event.GenericEvent, 100)
+
+//
+// Inventory collector health.
+type Health struct {
+	// Connected to the provider.
+	Connected bool `json:"connected"`
+	// Time of the last (applied) update.
+	LastUpdate *time.Time `json:"lastUpdate,omitempty"`
+	// The last error.
+	LastError string `json:"lastError,omitempty"`
+	// Time of the last error.
+	LastErrorTime *time.Time `json:"lastErrorTime,omitempty"`
+	// Number of consecutive failures.
+	Failures int `json:"consecutiveFailures"`
+	// Time of the next (connect) retry.
+	NextRetry *time.Time `json:"nextRetry,omitempty"`
+	// Serving cached inventory not (yet) reconciled
+	// with the provider.
+	Stale bool `json:"stale"`
+	// Scope (inventory) paths not found.
+	ScopeNotFound []string `json:"scopeNotFound,omitempty"`
+}
+
+//
+// Reports inventory collector health.
+type HealthReporter interface {
+	// Get the collector health.
+	Health() Health
+}
@@ -19,5 +19,13 @@ func All() []interface{} {
 		&NetworkAttachmentDefinition{},
 		&StorageClass{},
 		&Namespace{},
+		&VM{},
+		&VMI{},
+		&PersistentVolumeClaim{},
+		&PersistentVolume{},
+		&DataVolume{},
+		&Node{},
+		&ResourceQuota{},
+		&LimitRange{},
 	}
 }
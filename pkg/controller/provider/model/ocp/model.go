@@ -7,7 +7,9 @@ import (
 	core "k8s.io/api/core/v1"
 	storage "k8s.io/api/storage/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	cdi "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	"path"
 	"strconv"
 )
@@ -130,3 +132,101 @@ func (m *NetworkAttachmentDefinition) With(n *net.NetworkAttachmentDefinition) {
 	m.Base.With(n)
 	m.Object = *n
 }
+
+//
+// KubeVirt VirtualMachine.
+// The object is stored as unstructured content.
+type VM struct {
+	Base
+	Object map[string]interface{} `sql:""`
+}
+
+func (m *VM) With(u *unstructured.Unstructured) {
+	m.Base.With(u)
+	m.Object = u.Object
+}
+
+//
+// KubeVirt VirtualMachineInstance.
+// The object is stored as unstructured content.
+type VMI struct {
+	Base
+	Object map[string]interface{} `sql:""`
+}
+
+func (m *VMI) With(u *unstructured.Unstructured) {
+	m.Base.With(u)
+	m.Object = u.Object
+}
+
+//
+// PersistentVolumeClaim
+type PersistentVolumeClaim struct {
+	Base
+	Object core.PersistentVolumeClaim `sql:""`
+}
+
+func (m *PersistentVolumeClaim) With(p *core.PersistentVolumeClaim) {
+	m.Base.With(p)
+	m.Object = *p
+}
+
+//
+// PersistentVolume
+type PersistentVolume struct {
+	Base
+	Object core.PersistentVolume `sql:""`
+}
+
+func (m *PersistentVolume) With(p *core.PersistentVolume) {
+	m.Base.With(p)
+	m.Object = *p
+}
+
+//
+// CDI DataVolume
+type DataVolume struct {
+	Base
+	Object cdi.DataVolume `sql:""`
+}
+
+func (m *DataVolume) With(d *cdi.DataVolume) {
+	m.Base.With(d)
+	m.Object = *d
+}
+
+//
+// Node
+type Node struct {
+	Base
+	Object core.Node `sql:""`
+}
+
+func (m *Node) With(n *core.Node) {
+	m.Base.With(n)
+	m.Object = *n
+}
+
+//
+// ResourceQuota
+type ResourceQuota struct {
+	Base
+	Object core.ResourceQuota `sql:""`
+}
+
+func (m *ResourceQuota) With(r *core.ResourceQuota) {
+	m.Base.With(r)
+	m.Object = *r
+}
+
+//
+// LimitRange
+type LimitRange struct {
+	Base
+	Object core.LimitRange `sql:""`
+}
+
+func (m *LimitRange) With(l *core.LimitRange) {
+	m.Base.With(l)
+	m.Object = *l
+}
@@ -27,6 +27,9 @@ func All() []interface{} {
 		&DVSwitch{},
 		&Datastore{},
 		&Host{},
+		&ResourcePool{},
+		&VApp{},
 		&VM{},
+		&VMMetrics{},
 	}
 }
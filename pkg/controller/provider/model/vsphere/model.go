@@ -4,6 +4,7 @@ import (
 	liberr "github.com/konveyor/controller/pkg/error"
 	libmodel "github.com/konveyor/controller/pkg/inventory/model"
 	"strings"
+	"time"
 )
 
 //
@@ -144,6 +145,24 @@ Walk:
 			}
 			parts = append(parts, m.Name)
 			node = &m.Base
+		case PoolKind:
+			m := &ResourcePool{}
+			m.WithRef(parent)
+			err = db.Get(m)
+			if err != nil {
+				return
+			}
+			parts = append(parts, m.Name)
+			node = &m.Base
+		case VAppKind:
+			m := &VApp{}
+			m.WithRef(parent)
+			err = db.Get(m)
+			if err != nil {
+				return
+			}
+			parts = append(parts, m.Name)
+			node = &m.Base
 		default:
 			break Walk
 		}
@@ -172,6 +191,8 @@ type About struct {
 	Base
 	APIVersion string `sql:""`
 	Product    string `sql:""`
+	// A full sync has completed.
+	Synced bool `sql:""`
 }
 
 type Folder struct {
@@ -189,14 +210,17 @@ type Datacenter struct {
 
 type Cluster struct {
 	Base
-	Hosts       []Ref  `sql:""`
-	Networks    []Ref  `sql:""`
-	Datastores  []Ref  `sql:""`
-	DasEnabled  bool   `sql:""`
-	DasVms      []Ref  `sql:""`
-	DrsEnabled  bool   `sql:""`
-	DrsBehavior string `sql:""`
-	DrsVms      []Ref  `sql:""`
+	ResourcePool Ref       `sql:""`
+	Hosts        []Ref     `sql:""`
+	Networks     []Ref     `sql:""`
+	Datastores   []Ref     `sql:""`
+	DasEnabled   bool      `sql:""`
+	DasVms       []Ref     `sql:""`
+	DrsEnabled   bool      `sql:""`
+	DrsBehavior  string    `sql:""`
+	DrsVms       []Ref     `sql:""`
+	DrsRules     []DrsRule `sql:""`
+	VmGroups     []VmGroup `sql:""`
 }
 
 type Host struct {
@@ -205,12 +229,15 @@ type Host struct {
 	Thumbprint        string      `sql:""`
 	CpuSockets        int16       `sql:""`
 	CpuCores          int16       `sql:""`
+	CpuMhz            int32       `sql:""`
 	ProductName       string      `sql:""`
 	ProductVersion    string      `sql:""`
 	Network           HostNetwork `sql:""`
 	Networks          []Ref       `sql:""`
 	Datastores        []Ref       `sql:""`
 	Vms               []Ref       `sql:""`
+	Tags              []Tag       `sql:""`
+	CustomAttributes  Attributes  `sql:""`
 }
 
 type HostNetwork struct {
@@ -272,6 +299,7 @@ type PortGroup struct {
 	Key    string `json:"key"`
 	Name   string `json:"name"`
 	Switch string `json:"vSwitch"`
+	VlanID int32  `json:"vlanId"`
 }
 
 type Switch struct {
@@ -285,50 +313,129 @@ type Network struct {
 	Base
 	Tag      string `sql:""`
 	DVSwitch Ref    `sql:""`
+	VLAN     VLAN   `sql:""`
+}
+
+//
+// VLAN types.
+const (
+	VlanNone  = "none"
+	VlanID    = "vlan"
+	VlanTrunk = "trunk"
+	VlanPVLAN = "pvlan"
+)
+
+//
+// Portgroup VLAN configuration.
+type VLAN struct {
+	// Type: none|vlan|trunk|pvlan.
+	Type string `json:"type"`
+	// VLAN (or private VLAN) ID.
+	ID int32 `json:"id,omitempty"`
+	// Trunk ranges.
+	Ranges []VLANRange `json:"ranges,omitempty"`
+}
+
+//
+// VLAN ID range.
+type VLANRange struct {
+	Start int32 `json:"start"`
+	End   int32 `json:"end"`
 }
 
 type DVSwitch struct {
 	Base
-	Host []DVSHost `sql:""`
+	Host       []DVSHost `sql:""`
+	Uplinks    []string  `sql:""`
+	MTU        int32     `sql:""`
+	PortGroups []Ref     `sql:""`
 }
 
 type DVSHost struct {
-	Host Ref
-	PNIC []string
+	Host Ref      `json:"host"`
+	PNIC []string `json:"pNICs"`
 }
 
 type Datastore struct {
 	Base
-	Type            string `sql:""`
-	Capacity        int64  `sql:""`
-	Free            int64  `sql:""`
-	MaintenanceMode string `sql:""`
+	Type             string     `sql:""`
+	Capacity         int64      `sql:""`
+	Free             int64      `sql:""`
+	MaintenanceMode  string     `sql:""`
+	Tags             []Tag      `sql:""`
+	CustomAttributes Attributes `sql:""`
+}
+
+//
+// Resource pool.
+type ResourcePool struct {
+	Base
+	// Owner (compute resource).
+	Owner Ref `sql:""`
+	// CPU (MHz) allocation.
+	CpuAllocation Allocation `sql:""`
+	// Memory (MB) allocation.
+	MemoryAllocation Allocation `sql:""`
+	// Child pools and vApps.
+	Pools []Ref `sql:""`
+	// VMs.
+	Vms []Ref `sql:""`
+}
+
+//
+// Resource allocation.
+type Allocation struct {
+	// Reservation.
+	Reservation int64 `json:"reservation"`
+	// Limit (-1=unlimited).
+	Limit int64 `json:"limit"`
+	// Reservation may grow beyond the specified value.
+	Expandable bool `json:"expandable"`
+	// Shares.
+	Shares int32 `json:"shares"`
+	// Shares level (low|normal|high|custom).
+	SharesLevel string `json:"sharesLevel"`
+}
+
+//
+// Virtual application (vApp).
+// A specialized resource pool.
+type VApp struct {
+	ResourcePool
 }
 
 type VM struct {
 	Base
-	UUID                  string    `sql:""`
-	Firmware              string    `sql:""`
-	PowerState            string    `sql:""`
-	CpuAffinity           []int32   `sql:""`
-	CpuHotAddEnabled      bool      `sql:""`
-	CpuHotRemoveEnabled   bool      `sql:""`
-	MemoryHotAddEnabled   bool      `sql:""`
-	FaultToleranceEnabled bool      `sql:""`
-	CpuCount              int32     `sql:""`
-	CoresPerSocket        int32     `sql:""`
-	MemoryMB              int32     `sql:""`
-	GuestName             string    `sql:""`
-	BalloonedMemory       int32     `sql:""`
-	IpAddress             string    `sql:""`
-	NumaNodeAffinity      []string  `sql:""`
-	StorageUsed           int64     `sql:""`
-	Devices               []Device  `sql:""`
-	Disks                 []Disk    `sql:""`
-	Networks              []Ref     `sql:""`
-	Host                  Ref       `sql:""`
-	RevisionAnalyzed      int64     `sql:""`
-	Concerns              []Concern `sql:""`
+	UUID                  string     `sql:""`
+	Firmware              string     `sql:""`
+	SecureBoot            bool       `sql:""`
+	TpmEnabled            bool       `sql:""`
+	Encrypted             bool       `sql:""`
+	PowerState            string     `sql:""`
+	CpuAffinity           []int32    `sql:""`
+	CpuHotAddEnabled      bool       `sql:""`
+	CpuHotRemoveEnabled   bool       `sql:""`
+	MemoryHotAddEnabled   bool       `sql:""`
+	FaultToleranceEnabled bool       `sql:""`
+	CpuCount              int32      `sql:""`
+	CoresPerSocket        int32      `sql:""`
+	MemoryMB              int32      `sql:""`
+	CpuAllocation         Allocation `sql:""`
+	MemoryAllocation      Allocation `sql:""`
+	GuestName             string     `sql:""`
+	BalloonedMemory       int32      `sql:""`
+	IpAddress             string     `sql:""`
+	NumaNodeAffinity      []string   `sql:""`
+	StorageUsed           int64      `sql:""`
+	Devices               []Device   `sql:""`
+	Disks                 []Disk     `sql:""`
+	Networks              []Ref      `sql:""`
+	Host                  Ref        `sql:""`
+	ResourcePool          Ref        `sql:""`
+	RevisionAnalyzed      int64      `sql:""`
+	Concerns              []Concern  `sql:""`
+	Tags                  []Tag      `sql:""`
+	CustomAttributes      Attributes `sql:""`
 }
 
 //
@@ -363,14 +470,29 @@ func (m *VM) Cluster(db libmodel.DB) (matched *Cluster, err error) {
 	return
 }
 
+//
+// Disk controller bus types.
+const (
+	SCSI = "scsi"
+	SATA = "sata"
+	IDE  = "ide"
+	NVME = "nvme"
+)
+
 //
 // Virtual Disk.
 type Disk struct {
-	File      string `json:"file"`
-	Datastore Ref    `json:"datastore"`
-	Capacity  int64  `json:"capacity"`
-	Shared    bool   `json:"shared"`
-	RDM       bool   `json:"rdm"`
+	ObjectID   string `json:"objectId"`
+	Label      string `json:"label"`
+	File       string `json:"file"`
+	Datastore  Ref    `json:"datastore"`
+	Capacity   int64  `json:"capacity"`
+	Shared     bool   `json:"shared"`
+	RDM        bool   `json:"rdm"`
+	Bus        string `json:"bus"`
+	Controller string `json:"controller"`
+	BusNumber  int32  `json:"busNumber"`
+	UnitNumber int32  `json:"unitNumber"`
 }
 
 //
@@ -379,6 +501,117 @@ type Device struct {
 	Kind string `json:"kind"`
 }
 
+//
+// vSphere tag.
+type Tag struct {
+	Category string `json:"category"`
+	Name     string `json:"name"`
+}
+
+//
+// Custom attributes.
+// Maps attribute name to value.
+type Attributes map[string]string
+
+//
+// Performance metric names.
+const (
+	CpuUsageMHz     = "cpuUsageMHz"
+	CpuUsagePercent = "cpuUsagePercent"
+	MemConsumedKB   = "memoryConsumedKB"
+	MemActiveKB     = "memoryActiveKB"
+	DiskReadIOPS    = "diskReadIOPS"
+	DiskWriteIOPS   = "diskWriteIOPS"
+	DiskReadKBps    = "diskReadKBps"
+	DiskWriteKBps   = "diskWriteKBps"
+	NetRxKBps       = "networkRxKBps"
+	NetTxKBps       = "networkTxKBps"
+)
+
+//
+// VM performance metrics.
+// The ID is the VM ID.
+type VMMetrics struct {
+	Base
+	// Samples (one per collection interval).
+	Samples []MetricSample `sql:""`
+	// Aggregates by window.
+	Windows []MetricWindow `sql:""`
+}
+
+//
+// Metric sample.
+// Values (by metric name) aggregated over the collection interval.
+type MetricSample struct {
+	Time    time.Time          `json:"time"`
+	Average map[string]float64 `json:"average"`
+	Peak    map[string]float64 `json:"peak"`
+}
+
+//
+// Metric aggregates over a window.
+type MetricWindow struct {
+	// Window (duration).
+	Window string `json:"window"`
+	// Number of samples.
+	Samples int `json:"samples"`
+	// Aggregates by metric name.
+	Metrics map[string]Aggregate `json:"metrics"`
+}
+
+//
+// Metric aggregate.
+// The raw (real-time) samples are not retained. The average
+// and the percentile are computed over the interval averages
+// and the peak is the largest raw sample.
+type Aggregate struct {
+	Average float64 `json:"average"`
+	// 95th percentile of the interval averages.
+	IntervalP95 float64 `json:"intervalP95"`
+	Peak        float64 `json:"peak"`
+}
+
+//
+// DRS rule kinds.
+const (
+	AffinityRule     = "affinity"
+	AntiAffinityRule = "anti-affinity"
+	VmHostRule       = "vm-host"
+	DependencyRule   = "dependency"
+)
+
+//
+// DRS rule.
+type DrsRule struct {
+	// Rule name.
+	Name string `json:"name"`
+	// Rule kind.
+	Kind string `json:"kind"`
+	// Enabled.
+	Enabled bool `json:"enabled"`
+	// Mandatory.
+	Mandatory bool `json:"mandatory"`
+	// VMs (affinity|anti-affinity).
+	Vms []Ref `json:"vms,omitempty"`
+	// VM group (vm-host|dependency).
+	VmGroup string `json:"vmGroup,omitempty"`
+	// Affine host group (vm-host).
+	AffineHostGroup string `json:"affineHostGroup,omitempty"`
+	// Anti-affine host group (vm-host).
+	AntiAffineHostGroup string `json:"antiAffineHostGroup,omitempty"`
+	// Depends on VM group (dependency).
+	DependsOnVmGroup string `json:"dependsOnVmGroup,omitempty"`
+}
+
+//
+// DRS VM group.
+type VmGroup struct {
+	// Group name.
+	Name string `json:"name"`
+	// VMs.
+	Vms []Ref `json:"vms"`
+}
+
 //
 // VM concerns.
 type Concern struct {
@@ -16,7 +16,10 @@ var (
 	ClusterKind    = libref.ToKind(Cluster{})
 	HostKind       = libref.ToKind(Host{})
 	NetKind        = libref.ToKind(Network{})
+	DVSwitchKind   = libref.ToKind(DVSwitch{})
 	DsKind         = libref.ToKind(Datastore{})
+	PoolKind       = libref.ToKind(ResourcePool{})
+	VAppKind       = libref.ToKind(VApp{})
 	VmKind         = libref.ToKind(VM{})
 )
 
@@ -111,6 +114,12 @@ func (r *Tree) Build() (*TreeNode, error) {
 					}
 					return liberr.Wrap(err)
 				}
+				// Portgroups are walked by switch.
+				if network, cast := m.(*Network); cast && r.Leaf == NetKind {
+					if network.DVSwitch.ID != "" {
+						continue next
+					}
+				}
 				err = walk(m, true)
 				if err != nil {
 					return liberr.Wrap(err)
@@ -120,7 +129,7 @@ func (r *Tree) Build() (*TreeNode, error) {
 			var ref Ref
 			dc := model.(*Datacenter)
 			switch r.Leaf {
-			case ClusterKind, HostKind:
+			case ClusterKind, HostKind, PoolKind:
 				ref = dc.Clusters
 			case VmKind:
 				ref = dc.Vms
@@ -154,6 +163,8 @@ func (r *Tree) Build() (*TreeNode, error) {
 				refList = cluster.Networks
 			case DsKind:
 				refList = cluster.Datastores
+			case PoolKind:
+				refList = []Ref{cluster.ResourcePool}
 			case ClusterKind:
 				// Leaf
 			default:
@@ -200,6 +211,58 @@ func (r *Tree) Build() (*TreeNode, error) {
 					return liberr.Wrap(err)
 				}
 			}
+		case PoolKind, VAppKind:
+			refList := []Ref{}
+			var pool *ResourcePool
+			if kind == VAppKind {
+				pool = &model.(*VApp).ResourcePool
+			} else {
+				pool = model.(*ResourcePool)
+			}
+			switch r.Leaf {
+			case PoolKind, VmKind:
+				refList = append(refList, pool.Pools...)
+				refList = append(refList, pool.Vms...)
+			default:
+				return InvalidKindError{kind}
+			}
+			for _, ref := range refList {
+				m, err := r.getRef(ref)
+				if err != nil {
+					if errors.As(err, &InvalidRefError{}) {
+						continue
+					}
+					return liberr.Wrap(err)
+				}
+				err = walk(m, true)
+				if err != nil {
+					return liberr.Wrap(err)
+				}
+			}
+		case DVSwitchKind:
+			refList := []Ref{}
+			dvSwitch := model.(*DVSwitch)
+			switch r.Leaf {
+			case NetKind:
+				refList = dvSwitch.PortGroups
+			case DVSwitchKind:
+				// Leaf
+			default:
+				return InvalidKindError{kind}
+			}
+			for _, ref := range refList {
+				m, err := r.getRef(ref)
+				if err != nil {
+					if errors.As(err, &InvalidRefError{}) {
+						continue
+					}
+					return liberr.Wrap(err)
+				}
+				err = walk(m, true)
+				if err != nil {
+					return liberr.Wrap(err)
+				}
+			}
 		case VmKind:
 			// Leaf
 		case NetKind:
@@ -239,8 +302,16 @@ func (r *Tree) getRef(ref Ref) (model Model, err error) {
 		model = &VM{Base: base}
 	case NetKind:
 		model = &Network{Base: base}
+	case DVSwitchKind:
+		model = &DVSwitch{Base: base}
 	case DsKind:
 		model = &Datastore{Base: base}
+	case PoolKind:
+		model = &ResourcePool{Base: base}
+	case VAppKind:
+		model = &VApp{
+			ResourcePool: ResourcePool{Base: base},
+		}
 	default:
 		err = InvalidRefError{ref}
 	}
@@ -3,6 +3,9 @@ package provider
 import (
 	libref "github.com/konveyor/controller/pkg/ref"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/analysis"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
@@ -43,3 +46,56 @@ func (r ProviderPredicate) Delete(e event.DeleteEvent) bool {
 
 	return false
 }
+
+//
+// Analysis rule set ConfigMap predicate.
+// The shared rule set is updated when the configured
+// ConfigMap is created, updated or deleted. Nothing is
+// reconciled. See: analysis.ConfigMapRules.
+type RulePredicate struct {
+	predicate.Funcs
+}
+
+func (r RulePredicate) Create(e event.CreateEvent) bool {
+	if cm, cast := e.Object.(*core.ConfigMap); cast && r.match(e.Meta) {
+		r.update(cm)
+	}
+
+	return false
+}
+
+func (r RulePredicate) Update(e event.UpdateEvent) bool {
+	if cm, cast := e.ObjectNew.(*core.ConfigMap); cast && r.match(e.MetaNew) {
+		r.update(cm)
+	}
+
+	return false
+}
+
+func (r RulePredicate) Delete(e event.DeleteEvent) bool {
+	if r.match(e.Meta) {
+		r.update(nil)
+	}
+
+	return false
+}
+
+func (r RulePredicate) Generic(e event.GenericEvent) bool {
+	return false
+}
+
+//
+// Match the configured ConfigMap.
+func (r RulePredicate) match(object meta.Object) bool {
+	ref := Settings.Inventory.AnalysisConfigMap
+	return object.GetNamespace() == ref.Namespace && object.GetName() == ref.Name
+}
+
+//
+// Update the shared rule set.
+func (r RulePredicate) update(cm *core.ConfigMap) {
+	err := analysis.ConfigMapRules.Update(cm)
+	if err != nil {
+		log.Trace(err)
+	}
+}
@@ -2,16 +2,24 @@ package provider
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	libcnd "github.com/konveyor/controller/pkg/condition"
 	liberr "github.com/konveyor/controller/pkg/error"
 	libref "github.com/konveyor/controller/pkg/ref"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/auth"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/certificate"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/container"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/model"
 	core "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	"net/url"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strconv"
+	"strings"
 )
 
 //
@@ -24,6 +32,10 @@ const (
 	ConnectionTested = "ConnectionTested"
 	InventoryCreated = "InventoryCreated"
 	LoadInventory    = "LoadInventory"
+	NotConnected     = "CollectorNotConnected"
+	InventoryStale   = "InventoryStale"
+	TLSNotVerified   = "TLSNotVerified"
+	ScopeNotValid    = "ScopeNotValid"
 )
 
 //
@@ -48,6 +60,9 @@ const (
 	Completed    = "Completed"
 	Tested       = "Tested"
 	Started      = "Started"
+	Disconnected = "Disconnected"
+	NotTrusted   = "NotTrusted"
+	Insecure     = "Insecure"
 )
 
 //
@@ -68,6 +83,10 @@ func (r *Reconciler) validate(provider *api.Provider) error {
 	if err != nil {
 		return liberr.Wrap(err)
 	}
+	err = r.validateScope(provider)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
 	secret, err := r.validateSecret(provider)
 	if err != nil {
 		return liberr.Wrap(err)
@@ -80,6 +99,10 @@ func (r *Reconciler) validate(provider *api.Provider) error {
 	if err != nil {
 		return liberr.Wrap(err)
 	}
+	err = r.collectorHealth(provider)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
 	if !provider.Status.HasBlockerCondition() {
 		provider.Status.SetCondition(
 			libcnd.Condition{
@@ -149,6 +172,47 @@ func (r *Reconciler) validateURL(provider *api.Provider) error {
 	return nil
 }
 
+//
+// Validate the scope.
+// Paths must be absolute inventory paths.
+// Resolved by the collector. See: collectorHealth().
+func (r *Reconciler) validateScope(provider *api.Provider) error {
+	scope := provider.Spec.Scope
+	if scope == nil {
+		return nil
+	}
+	if provider.Type() != api.VSphere {
+		provider.Status.SetCondition(
+			libcnd.Condition{
+				Type:     ScopeNotValid,
+				Status:   True,
+				Reason:   NotSupported,
+				Category: Critical,
+				Message:  "The `scope` is supported by vSphere providers only.",
+			})
+		return nil
+	}
+	malformed := []string{}
+	for _, path := range append(scope.Include, scope.Exclude...) {
+		if !strings.HasPrefix(path, "/") {
+			malformed = append(malformed, path)
+		}
+	}
+	if len(malformed) > 0 {
+		provider.Status.SetCondition(
+			libcnd.Condition{
+				Type:     ScopeNotValid,
+				Status:   True,
+				Reason:   Malformed,
+				Category: Critical,
+				Message:  "The `scope` paths must be absolute inventory paths.",
+				Items:    malformed,
+			})
+	}
+
+	return nil
+}
+
 //
 // Validate secret (ref).
 //   1. The references is complete.
@@ -178,7 +242,7 @@ func (r *Reconciler) validateSecret(provider *api.Provider) (secret *core.Secret
 		Name:      ref.Name,
 	}
 	err = r.Get(context.TODO(), key, secret)
-	if errors.IsNotFound(err) {
+	if k8serr.IsNotFound(err) {
 		err = nil
 		newCnd.Reason = NotFound
 		provider.Status.SetCondition(newCnd)
@@ -191,12 +255,24 @@ func (r *Reconciler) validateSecret(provider *api.Provider) (secret *core.Secret
 	keyList := []string{}
 	switch provider.Type() {
 	case api.OpenShift:
-		keyList = []string{"token"}
+		_, kubeconfig := secret.Data[api.Kubeconfig]
+		_, cert := secret.Data[api.ClientCert]
+		_, key := secret.Data[api.ClientKey]
+		switch {
+		case kubeconfig:
+		case cert || key:
+			keyList = []string{
+				api.ClientCert,
+				api.ClientKey,
+			}
+		default:
+			keyList = []string{api.Token}
+		}
 	case api.VSphere:
 		keyList = []string{
 			"user",
 			"password",
-			"thumbprint",
+			api.Thumbprint,
 		}
 	}
 	for _, key := range keyList {
@@ -207,6 +283,82 @@ func (r *Reconciler) validateSecret(provider *api.Provider) (secret *core.Secret
 	if len(newCnd.Items) > 0 {
 		newCnd.Reason = DataErr
 		provider.Status.SetCondition(newCnd)
+		return
+	}
+	// Malformed
+	newCnd.Items = append(
+		r.validateTLS(provider, secret),
+		r.validateAuth(provider, secret)...)
+	if len(newCnd.Items) > 0 {
+		newCnd.Reason = Malformed
+		newCnd.Message = "The `secret` contains malformed settings."
+		provider.Status.SetCondition(newCnd)
+		return
+	}
+	if api.InsecureSkipVerify(secret) {
+		provider.Status.SetCondition(
+			libcnd.Condition{
+				Type:     TLSNotVerified,
+				Status:   True,
+				Reason:   Insecure,
+				Category: Warn,
+				Message:  "The server certificate will not be verified.",
+			})
+	}
+
+	return
+}
+
+//
+// Validate the TLS settings in the secret.
+// Returns the list of malformed keys.
+func (r *Reconciler) validateTLS(provider *api.Provider, secret *core.Secret) (malformed []string) {
+	if b, found := secret.Data[api.Insecure]; found {
+		if _, err := strconv.ParseBool(string(b)); err != nil {
+			malformed = append(malformed, api.Insecure)
+		}
+	}
+	if b, found := secret.Data[api.CACert]; found {
+		if !x509.NewCertPool().AppendCertsFromPEM(b) {
+			malformed = append(malformed, api.CACert)
+		}
+	}
+	if provider.Type() == api.VSphere {
+		if b, found := secret.Data[api.Thumbprint]; found {
+			if !certificate.ValidThumbprint(string(b)) {
+				malformed = append(malformed, api.Thumbprint)
+			}
+		}
+	}
+
+	return
+}
+
+//
+// Validate the (openshift) credentials in the secret.
+// Returns the list of malformed keys.
+func (r *Reconciler) validateAuth(provider *api.Provider, secret *core.Secret) (malformed []string) {
+	if provider.Type() != api.OpenShift {
+		return
+	}
+	if b, found := secret.Data[api.ServiceAccount]; found {
+		if _, _, err := auth.SplitServiceAccount(string(b)); err != nil {
+			malformed = append(malformed, api.ServiceAccount)
+		}
+	}
+	if b, found := secret.Data[api.Kubeconfig]; found {
+		name := string(secret.Data[api.Context])
+		if _, err := api.KubeconfigRestCfg(b, name); err != nil {
+			malformed = append(malformed, api.Kubeconfig)
+		}
+		return
+	}
+	cert, hasCert := secret.Data[api.ClientCert]
+	key, hasKey := secret.Data[api.ClientKey]
+	if hasCert && hasKey {
+		if _, err := tls.X509KeyPair(cert, key); err != nil {
+			malformed = append(malformed, api.ClientCert, api.ClientKey)
+		}
 	}
 
 	return
@@ -232,6 +384,12 @@ func (r *Reconciler) testConnection(provider *api.Provider, secret *core.Secret)
 		cnd.Status = False
 		cnd.Reason = Failed
 		cnd.Message = fmt.Sprintf("Connection test, failed: %s", err.Error())
+		if r.untrusted(err) {
+			cnd.Reason = NotTrusted
+			cnd.Message = fmt.Sprintf(
+				"Connection test, failed: the server certificate is not trusted: %s",
+				err.Error())
+		}
 	}
 
 	provider.Status.SetCondition(cnd)
@@ -239,6 +397,20 @@ func (r *Reconciler) testConnection(provider *api.Provider, secret *core.Secret)
 	return nil
 }
 
+//
+// The error reports the server certificate
+// could not be verified.
+func (r *Reconciler) untrusted(err error) bool {
+	if errors.As(err, &x509.UnknownAuthorityError{}) ||
+		errors.As(err, &x509.HostnameError{}) ||
+		errors.As(err, &x509.CertificateInvalidError{}) ||
+		errors.As(err, &certificate.ThumbprintError{}) {
+		return true
+	}
+
+	return false
+}
+
 //
 // Validate inventory created.
 func (r *Reconciler) inventoryCreated(provider *api.Provider) error {
@@ -269,3 +441,54 @@ func (r *Reconciler) inventoryCreated(provider *api.Provider) error {
 
 	return nil
 }
+
+//
+// Validate the inventory collector is connected.
+// The provider is reconciled when the collector reports
+// the health has changed. See: model.HealthChanged.
+func (r *Reconciler) collectorHealth(provider *api.Provider) error {
+	rl, found := r.container.Get(provider)
+	if !found {
+		return nil
+	}
+	reporter, cast := rl.(model.HealthReporter)
+	if !cast {
+		return nil
+	}
+	health := reporter.Health()
+	if health.Stale {
+		provider.Status.SetCondition(
+			libcnd.Condition{
+				Type:     InventoryStale,
+				Status:   True,
+				Reason:   Started,
+				Category: Advisory,
+				Message:  "Serving the cached inventory; reconciling with the provider.",
+			})
+	}
+	if len(health.ScopeNotFound) > 0 && !provider.Status.HasCondition(ScopeNotValid) {
+		provider.Status.SetCondition(
+			libcnd.Condition{
+				Type:     ScopeNotValid,
+				Status:   True,
+				Reason:   NotFound,
+				Category: Critical,
+				Message:  "The `scope` paths not found.",
+				Items:    health.ScopeNotFound,
+			})
+	}
+	if !health.Connected && health.Failures > 0 {
+		provider.Status.SetCondition(
+			libcnd.Condition{
+				Type:     NotConnected,
+				Status:   True,
+				Reason:   Disconnected,
+				Category: Warn,
+				Message: fmt.Sprintf(
+					"The inventory collector is not connected: %s",
+					health.LastError),
+			})
+	}
+
+	return nil
+}
@@ -0,0 +1,360 @@
+package vcsim
+
+import (
+	"context"
+	"crypto/tls"
+	"github.com/gin-gonic/gin"
+	liberr "github.com/konveyor/controller/pkg/error"
+	libcontainer "github.com/konveyor/controller/pkg/inventory/container"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/container/vsphere"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/model"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/model/ocp"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	"github.com/konveyor/forklift-controller/pkg/settings"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"io/ioutil"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"net/http/httptest"
+	liburl "net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+//
+// Defaults.
+const (
+	// Provider namespace.
+	Namespace = "konveyor-forklift"
+	// Provider name.
+	Name = "vcsim"
+	// Initial sync timeout.
+	SyncTimeout = time.Minute
+)
+
+//
+// Settings.
+var Settings = &settings.Settings
+
+//
+// vSphere simulator (vcsim) test harness.
+// Starts the govmomi simulator, the vSphere collector
+// (reconciler) and the inventory web server. The simulator
+// registry (simulator.Map) is global so only one harness
+// may be started at a time.
+type Harness struct {
+	// Simulator model.
+	// Defaults to the vCenter (VPX) model.
+	Model *simulator.Model
+	// Simulator server.
+	Server *simulator.Server
+	// Provider (inventory) scope.
+	Scope *api.ProviderScope
+	// The provider.
+	Provider *api.Provider
+	// The provider secret.
+	Secret *core.Secret
+	// Inventory DB.
+	DB libmodel.DB
+	// The collector.
+	Reconciler *vsphere.Reconciler
+	// Inventory container.
+	Container *libcontainer.Container
+	// Inventory web server.
+	Web *httptest.Server
+	// Working directory.
+	dir string
+}
+
+//
+// Start the simulator, collector and web server.
+// Blocks until the initial sync has completed.
+func (r *Harness) Start() (err error) {
+	gin.SetMode(gin.TestMode)
+	if r.Model == nil {
+		r.Model = simulator.VPX()
+	}
+	err = r.Model.Create()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.datastoreIDs()
+	r.Model.Service.TLS = &tls.Config{}
+	// Endpoints registered by imported simulators.
+	// Example: the vAPI (tags) simulator.
+	r.Model.Service.RegisterEndpoints = true
+	r.Server = r.Model.Service.NewServer()
+	r.dir, err = ioutil.TempDir("", "vcsim")
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.thumbprint()
+	r.build()
+	r.DB = libmodel.New(
+		filepath.Join(r.dir, "inventory.db"),
+		model.Models(r.Provider)...)
+	r.DB.Journal().Enable()
+	err = r.DB.Open(true)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	pModel := &ocp.Provider{}
+	pModel.With(r.Provider)
+	err = r.DB.Insert(pModel)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.Reconciler = vsphere.New(r.DB, r.Provider, r.Secret)
+	r.Container = libcontainer.New()
+	err = r.Container.Add(r.Reconciler)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = r.serve()
+	if err != nil {
+		return
+	}
+	err = r.Wait(SyncTimeout)
+
+	return
+}
+
+//
+// Stop and delete everything.
+func (r *Harness) Stop() {
+	if r.Reconciler != nil {
+		r.Reconciler.Shutdown()
+		r.disconnected(SyncTimeout)
+	}
+	if r.Web != nil {
+		r.Web.Close()
+	}
+	if r.DB != nil {
+		_ = r.DB.Close(true)
+	}
+	if r.Server != nil {
+		r.Server.Close()
+	}
+	if r.Model != nil {
+		r.Model.Remove()
+	}
+	if r.dir != "" {
+		_ = os.RemoveAll(r.dir)
+	}
+}
+
+//
+// Wait for the collector to have (initial) consistency.
+func (r *Harness) Wait(timeout time.Duration) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	for !r.Reconciler.HasConsistency() {
+		select {
+		case <-ctx.Done():
+			err = liberr.New("Initial sync not completed.")
+			return
+		case <-time.After(time.Millisecond * 100):
+		}
+	}
+
+	return
+}
+
+//
+// Wait for the collector to be disconnected.
+// The simulator server cannot be closed while the
+// collector is waiting on updates.
+func (r *Harness) disconnected(timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	for r.Reconciler.Health().Connected {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Millisecond * 100):
+		}
+	}
+}
+
+//
+// Build the provider and secret.
+func (r *Harness) build() {
+	url := *r.Server.URL
+	user := url.User.Username()
+	password, _ := url.User.Password()
+	url.User = nil
+	r.Secret = &core.Secret{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: Namespace,
+			Name:      Name,
+		},
+		Data: map[string][]byte{
+			"user":       []byte(user),
+			"password":   []byte(password),
+			"thumbprint": []byte(r.Server.CertificateInfo().ThumbprintSHA1),
+		},
+	}
+	r.Provider = &api.Provider{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: Namespace,
+			Name:      Name,
+			UID:       k8stypes.UID(Name),
+		},
+		Spec: api.ProviderSpec{
+			Type: api.VSphere,
+			URL:  url.String(),
+			Secret: core.ObjectReference{
+				Namespace: Namespace,
+				Name:      Name,
+			},
+			Scope: r.Scope,
+		},
+	}
+}
+
+//
+// Hosts report the thumbprint of the simulator so that
+// (ESX) host connections may be verified.
+func (r *Harness) thumbprint() {
+	thumbprint := r.Server.CertificateInfo().ThumbprintSHA1
+	for _, entity := range simulator.Map.All(vsphere.Host) {
+		if host, cast := entity.(*simulator.HostSystem); cast {
+			host.Summary.Config.SslThumbprint = thumbprint
+		}
+	}
+}
+
+//
+// Replace simulator datastore IDs.
+// The simulator uses the datastore (file) path as the ID which
+// cannot be used in the inventory (REST) paths. The datastores
+// are assigned vCenter style IDs and all references updated.
+func (r *Harness) datastoreIDs() {
+	ids := map[string]string{}
+	objects := []mo.Reference{}
+	for _, entity := range simulator.Map.All("") {
+		objects = append(objects, entity)
+	}
+	for i, entity := range simulator.Map.All(vsphere.Datastore) {
+		ids[entity.Entity().Self.Value] = "datastore-" + strconv.Itoa(i+1000)
+	}
+	for _, entity := range simulator.Map.All(vsphere.Host) {
+		if host, cast := entity.(*simulator.HostSystem); cast {
+			if ref := host.ConfigManager.DatastoreSystem; ref != nil {
+				if dss := simulator.Map.Get(*ref); dss != nil {
+					objects = append(objects, dss)
+				}
+			}
+		}
+	}
+	datastores := []mo.Reference{}
+	for _, object := range objects {
+		if ref := object.Reference(); ref.Type == vsphere.Datastore {
+			simulator.Map.Remove(ref)
+			datastores = append(datastores, object)
+		}
+	}
+	visited := map[visit]bool{}
+	for _, object := range objects {
+		replaceRef(reflect.ValueOf(object), ids, visited)
+	}
+	for _, object := range datastores {
+		simulator.Map.Put(object)
+	}
+}
+
+//
+// Visited pointer.
+// A struct and its first field have the same address.
+type visit struct {
+	kind    reflect.Type
+	address uintptr
+}
+
+//
+// Replace datastore references (recursively).
+func replaceRef(v reflect.Value, ids map[string]string, visited map[visit]bool) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		key := visit{kind: v.Type(), address: v.Pointer()}
+		if visited[key] {
+			return
+		}
+		visited[key] = true
+		replaceRef(v.Elem(), ids, visited)
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		e := v.Elem()
+		if e.Kind() == reflect.Ptr {
+			replaceRef(e, ids, visited)
+			return
+		}
+		if v.CanSet() {
+			c := reflect.New(e.Type()).Elem()
+			c.Set(e)
+			replaceRef(c, ids, visited)
+			v.Set(c)
+		}
+	case reflect.Struct:
+		if ref, cast := v.Interface().(types.ManagedObjectReference); cast {
+			if id, found := ids[ref.Value]; found && v.CanSet() && ref.Type == vsphere.Datastore {
+				ref.Value = id
+				v.Set(reflect.ValueOf(ref))
+			}
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Field(i); f.CanSet() {
+				replaceRef(f, ids, visited)
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			replaceRef(v.Index(i), ids, visited)
+		}
+	}
+}
+
+//
+// Start the inventory web server.
+// The inventory (REST) client settings are updated
+// to use the server.
+func (r *Harness) serve() (err error) {
+	router := gin.New()
+	for _, h := range web.All(r.Container) {
+		h.AddRoutes(router)
+	}
+	r.Web = httptest.NewServer(router)
+	url, err := liburl.Parse(r.Web.URL)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	Settings.Inventory.Host = url.Hostname()
+	Settings.Inventory.Port, err = strconv.Atoi(url.Port())
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	Settings.Inventory.TLS.Enabled = false
+
+	return
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	liberr "github.com/konveyor/controller/pkg/error"
 	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1/ref"
 	"github.com/konveyor/forklift-controller/pkg/settings"
 	"io/ioutil"
@@ -19,6 +20,16 @@ import (
 // Application settings.
 var Settings = &settings.Settings
 
+//
+// Provider (type) not supported.
+type ProviderNotSupportedError struct {
+	*api.Provider
+}
+
+func (r ProviderNotSupportedError) Error() string {
+	return fmt.Sprintf("Provider (type) not supported: %#v", r.Provider)
+}
+
 //
 // Resource kind cannot be resolved.
 type ResourceNotResolvedError struct {
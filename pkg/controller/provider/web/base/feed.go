@@ -0,0 +1,428 @@
+package base
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	liberr "github.com/konveyor/controller/pkg/error"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//
+// Routes.
+const (
+	EventCollection = "events"
+	CollectionParam = "collection"
+	RevisionParam   = "revision"
+	LastEventHeader = "Last-Event-ID"
+	EventMediaType  = "text/event-stream"
+)
+
+//
+// Event actions.
+const (
+	ActionCreated = "created"
+	ActionUpdated = "updated"
+	ActionDeleted = "deleted"
+	// The requested revision is no longer available.
+	// Clients must (re)list the collections.
+	ActionReset = "reset"
+)
+
+//
+// Feed settings.
+const (
+	// Number of events retained for resume.
+	FeedBacklog = 10000
+	// Subscriber queue depth.
+	FeedQueue = 1000
+	// Interval between heartbeat (comment) frames.
+	FeedHeartbeat = time.Second * 30
+)
+
+//
+// Event feeds (by DB).
+var feeds = struct {
+	mutex   sync.Mutex
+	content map[libmodel.DB]*Feed
+}{
+	content: map[libmodel.DB]*Feed{},
+}
+
+//
+// Find (or create) the event feed for the DB.
+func FeedFor(db libmodel.DB, models []interface{}) (feed *Feed, err error) {
+	feeds.mutex.Lock()
+	defer feeds.mutex.Unlock()
+	feed, found := feeds.content[db]
+	if found {
+		return
+	}
+	feed = &Feed{
+		db:          db,
+		epoch:       time.Now().UnixNano(),
+		subscribers: map[*Subscriber]bool{},
+	}
+	err = feed.start(models)
+	if err != nil {
+		feed = nil
+		return
+	}
+	feeds.content[db] = feed
+	return
+}
+
+//
+// Inventory event.
+type Event struct {
+	// Feed revision.
+	Revision uint64
+	// Action (created|updated|deleted).
+	Action string
+	// The model (as updated).
+	Model libmodel.Model
+}
+
+//
+// Inventory change feed.
+// Model events reported by the DB journal are numbered
+// with a (feed) revision and retained (bounded) so that
+// clients may resume from a revision. The revision is not
+// persisted so the event ID is qualified by the feed epoch
+// (start time). Events cannot be resumed across epochs.
+type Feed struct {
+	mutex sync.Mutex
+	// DB.
+	db libmodel.DB
+	// Epoch (start time).
+	epoch int64
+	// DB watches.
+	watches []*libmodel.Watch
+	// Latest revision.
+	revision uint64
+	// Retained events.
+	backlog []Event
+	// Subscribers.
+	subscribers map[*Subscriber]bool
+	// Ended.
+	ended bool
+}
+
+//
+// Start watching the models.
+func (r *Feed) start(models []interface{}) (err error) {
+	journal := r.db.Journal()
+	for _, m := range models {
+		var w *libmodel.Watch
+		w, err = journal.Watch(m.(libmodel.Model), r)
+		if err != nil {
+			err = liberr.Wrap(err)
+			r.end()
+			return
+		}
+		r.watches = append(r.watches, w)
+		w.Start()
+	}
+
+	return
+}
+
+//
+// Subscribe to events following the revision.
+// Returns false when events following the revision are
+// no longer retained or the epoch does not match.
+func (r *Feed) Subscribe(epoch int64, revision uint64) (sub *Subscriber, resumed bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	sub = &Subscriber{
+		feed:  r,
+		queue: make(chan Event, FeedQueue+len(r.backlog)),
+	}
+	if r.ended {
+		close(sub.queue)
+		return
+	}
+	r.subscribers[sub] = true
+	if epoch != r.epoch || revision > r.revision {
+		return
+	}
+	if revision == r.revision {
+		resumed = true
+		return
+	}
+	oldest := r.revision - uint64(len(r.backlog)) + 1
+	if revision+1 < oldest {
+		return
+	}
+	resumed = true
+	for _, event := range r.backlog {
+		if event.Revision > revision {
+			sub.queue <- event
+		}
+	}
+
+	return
+}
+
+//
+// Latest revision.
+func (r *Feed) Revision() uint64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.revision
+}
+
+//
+// Epoch (start time).
+func (r *Feed) Epoch() int64 {
+	return r.epoch
+}
+
+//
+// A model has been created.
+func (r *Feed) Created(event libmodel.Event) {
+	r.publish(ActionCreated, event.Model)
+}
+
+//
+// A model has been updated.
+func (r *Feed) Updated(event libmodel.Event) {
+	r.publish(ActionUpdated, event.Updated)
+}
+
+//
+// A model has been deleted.
+func (r *Feed) Deleted(event libmodel.Event) {
+	r.publish(ActionDeleted, event.Model)
+}
+
+//
+// An error has occurred delivering an event.
+func (r *Feed) Error(err error) {
+	Log.Trace(err)
+}
+
+//
+// A watch has ended.
+// The feed is ended with any of the watches.
+func (r *Feed) End() {
+	feeds.mutex.Lock()
+	if feeds.content[r.db] == r {
+		delete(feeds.content, r.db)
+	}
+	feeds.mutex.Unlock()
+	r.end()
+}
+
+//
+// End the feed and the subscriptions.
+func (r *Feed) end() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.ended {
+		return
+	}
+	r.ended = true
+	for sub := range r.subscribers {
+		close(sub.queue)
+	}
+	r.subscribers = map[*Subscriber]bool{}
+	watches := r.watches
+	r.watches = nil
+	go func() {
+		for _, w := range watches {
+			r.db.EndWatch(w)
+		}
+	}()
+}
+
+//
+// Number, retain and deliver the event.
+// Subscribers that cannot keep up are ended and expected
+// to reconnect (resume).
+func (r *Feed) publish(action string, m libmodel.Model) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.ended {
+		return
+	}
+	r.revision++
+	event := Event{
+		Revision: r.revision,
+		Action:   action,
+		Model:    m,
+	}
+	r.backlog = append(r.backlog, event)
+	if len(r.backlog) > FeedBacklog {
+		r.backlog = r.backlog[len(r.backlog)-FeedBacklog:]
+	}
+	for sub := range r.subscribers {
+		select {
+		case sub.queue <- event:
+		default:
+			delete(r.subscribers, sub)
+			close(sub.queue)
+		}
+	}
+}
+
+//
+// Remove the subscriber.
+func (r *Feed) unsubscribe(sub *Subscriber) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, found := r.subscribers[sub]; found {
+		delete(r.subscribers, sub)
+		close(sub.queue)
+	}
+}
+
+//
+// Feed subscriber.
+type Subscriber struct {
+	// Feed.
+	feed *Feed
+	// Event queue.
+	// Closed when the subscription has ended.
+	queue chan Event
+}
+
+//
+// Event queue.
+func (r *Subscriber) Queue() <-chan Event {
+	return r.queue
+}
+
+//
+// End the subscription.
+func (r *Subscriber) End() {
+	r.feed.unsubscribe(r)
+}
+
+//
+// Event (REST) resource.
+type EventResource struct {
+	// Feed epoch.
+	Epoch int64 `json:"epoch"`
+	// Feed revision.
+	Revision uint64 `json:"revision"`
+	// Action (created|updated|deleted|reset).
+	Action string `json:"action"`
+	// Collection.
+	Collection string `json:"collection,omitempty"`
+	// Resource.
+	Resource interface{} `json:"resource,omitempty"`
+}
+
+//
+// Build the event resource.
+// Returns false when the event is not reported.
+type EventBuilder func(Event) (r *EventResource, reported bool)
+
+//
+// Stream (server-sent) events.
+// The event ID (<epoch>-<revision>) is specified by the `revision`
+// parameter or the `Last-Event-ID` header (reconnect). A `reset`
+// event is sent when the event cannot be resumed. Streaming begins
+// with the next event when not specified.
+func (h *Handler) Stream(ctx *gin.Context, feed *Feed, collection string, builder EventBuilder) {
+	epoch := feed.Epoch()
+	revision := feed.Revision()
+	s := ctx.Request.Header.Get(LastEventHeader)
+	if s == "" {
+		s = ctx.Request.URL.Query().Get(RevisionParam)
+	}
+	if s != "" {
+		var err error
+		epoch, revision, err = h.eventID(s)
+		if err != nil {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+	}
+	sub, resumed := feed.Subscribe(epoch, revision)
+	defer sub.End()
+	header := ctx.Writer.Header()
+	header.Set("Content-Type", EventMediaType)
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	ctx.Status(http.StatusOK)
+	if !resumed {
+		h.writeEvent(
+			ctx.Writer,
+			&EventResource{
+				Epoch:    feed.Epoch(),
+				Revision: feed.Revision(),
+				Action:   ActionReset,
+			})
+	}
+	ctx.Writer.Flush()
+	done := ctx.Request.Context().Done()
+	heartbeat := time.NewTicker(FeedHeartbeat)
+	defer heartbeat.Stop()
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-done:
+			return false
+		case <-heartbeat.C:
+			_, err := fmt.Fprint(w, ":\n\n")
+			return err == nil
+		case event, open := <-sub.Queue():
+			if !open {
+				return false
+			}
+			r, reported := builder(event)
+			if !reported {
+				return true
+			}
+			if collection != "" && r.Collection != collection {
+				return true
+			}
+			r.Epoch = feed.Epoch()
+			r.Revision = event.Revision
+			r.Action = event.Action
+			return h.writeEvent(w, r) == nil
+		}
+	})
+}
+
+//
+// Write server-sent event.
+func (h *Handler) writeEvent(w io.Writer, r *EventResource) (err error) {
+	b, err := json.Marshal(r)
+	if err != nil {
+		Log.Trace(err)
+		return
+	}
+	_, err = fmt.Fprintf(w, "id: %d-%d\nevent: %s\ndata: %s\n\n", r.Epoch, r.Revision, r.Action, b)
+	return
+}
+
+//
+// Parse the event ID.
+// Format: <epoch>-<revision>.
+func (h *Handler) eventID(s string) (epoch int64, revision uint64, err error) {
+	part := strings.SplitN(s, "-", 2)
+	if len(part) != 2 {
+		err = liberr.New("event ID must be: <epoch>-<revision>.")
+		return
+	}
+	epoch, err = strconv.ParseInt(part[0], 10, 64)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	revision, err = strconv.ParseUint(part[1], 10, 64)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
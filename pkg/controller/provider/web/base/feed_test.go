@@ -0,0 +1,226 @@
+package base
+
+import (
+	"fmt"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	"github.com/onsi/gomega"
+	"testing"
+	"time"
+)
+
+type TestObject struct {
+	ID   string `sql:"pk"`
+	Name string `sql:""`
+}
+
+func (m *TestObject) Pk() string {
+	return m.ID
+}
+
+func (m *TestObject) String() string {
+	return m.ID
+}
+
+func (m *TestObject) Equals(other libmodel.Model) bool {
+	return false
+}
+
+func (m *TestObject) Labels() libmodel.Labels {
+	return nil
+}
+
+func TestFeed(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := openDB(g, "feed")
+	defer db.Close(true)
+	feed, err := FeedFor(db, []interface{}{&TestObject{}})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	defer feed.End()
+	again, err := FeedFor(db, []interface{}{&TestObject{}})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(again).To(gomega.BeIdenticalTo(feed))
+	epoch := feed.Epoch()
+
+	// Created.
+	for i := 0; i < 3; i++ {
+		g.Expect(db.Insert(&TestObject{ID: fmt.Sprint(i)})).NotTo(gomega.HaveOccurred())
+	}
+	g.Eventually(feed.Revision, time.Second*10).Should(gomega.Equal(uint64(3)))
+
+	// Resumed.
+	sub, resumed := feed.Subscribe(epoch, 1)
+	g.Expect(resumed).To(gomega.BeTrue())
+	g.Expect(drain(sub)).To(
+		gomega.Equal(
+			[]string{
+				"2:created:1",
+				"3:created:2",
+			}))
+	sub.End()
+	sub, resumed = feed.Subscribe(epoch, 3)
+	g.Expect(resumed).To(gomega.BeTrue())
+	g.Expect(drain(sub)).To(gomega.BeEmpty())
+
+	// Live.
+	object := &TestObject{ID: "0", Name: "updated"}
+	g.Expect(db.Update(object)).NotTo(gomega.HaveOccurred())
+	g.Expect(db.Delete(object)).NotTo(gomega.HaveOccurred())
+	g.Eventually(feed.Revision, time.Second*10).Should(gomega.Equal(uint64(5)))
+	g.Expect(drain(sub)).To(
+		gomega.Equal(
+			[]string{
+				"4:updated:0",
+				"5:deleted:0",
+			}))
+	sub.End()
+	sub.End()
+
+	// Reset: future revision.
+	sub, resumed = feed.Subscribe(epoch, 6)
+	g.Expect(resumed).To(gomega.BeFalse())
+	g.Expect(drain(sub)).To(gomega.BeEmpty())
+	sub.End()
+
+	// Reset: epoch mismatch.
+	sub, resumed = feed.Subscribe(epoch-1, 1)
+	g.Expect(resumed).To(gomega.BeFalse())
+	g.Expect(drain(sub)).To(gomega.BeEmpty())
+	sub.End()
+
+	// Ended.
+	sub, _ = feed.Subscribe(epoch, 5)
+	feed.End()
+	_, open := <-sub.Queue()
+	g.Expect(open).To(gomega.BeFalse())
+	sub.End()
+	sub, resumed = feed.Subscribe(epoch, 5)
+	g.Expect(resumed).To(gomega.BeFalse())
+	_, open = <-sub.Queue()
+	g.Expect(open).To(gomega.BeFalse())
+	other, err := FeedFor(db, []interface{}{&TestObject{}})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	defer other.End()
+	g.Expect(other).NotTo(gomega.BeIdenticalTo(feed))
+}
+
+func TestFeedBacklog(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := openDB(g, "backlog")
+	defer db.Close(true)
+	feed, err := FeedFor(db, []interface{}{&TestObject{}})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	defer feed.End()
+	epoch := feed.Epoch()
+	extra := 10
+	for i := 0; i < FeedBacklog+extra; i++ {
+		feed.publish(ActionCreated, &TestObject{ID: fmt.Sprint(i)})
+	}
+	g.Expect(feed.Revision()).To(gomega.Equal(uint64(FeedBacklog + extra)))
+	g.Expect(feed.backlog).To(gomega.HaveLen(FeedBacklog))
+	g.Expect(feed.backlog[0].Revision).To(gomega.Equal(uint64(extra + 1)))
+
+	// Trimmed.
+	sub, resumed := feed.Subscribe(epoch, uint64(extra-1))
+	g.Expect(resumed).To(gomega.BeFalse())
+	sub.End()
+
+	// Oldest retained.
+	sub, resumed = feed.Subscribe(epoch, uint64(extra))
+	g.Expect(resumed).To(gomega.BeTrue())
+	events := drain(sub)
+	g.Expect(events).To(gomega.HaveLen(FeedBacklog))
+	g.Expect(events[0]).To(gomega.Equal(fmt.Sprintf("%d:created:%d", extra+1, extra)))
+	sub.End()
+}
+
+func TestFeedSlowSubscriber(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := openDB(g, "slow")
+	defer db.Close(true)
+	feed, err := FeedFor(db, []interface{}{&TestObject{}})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	defer feed.End()
+	slow, _ := feed.Subscribe(feed.Epoch(), feed.Revision())
+	fast, _ := feed.Subscribe(feed.Epoch(), feed.Revision())
+	received := 0
+	for i := 0; i < FeedQueue+1; i++ {
+		feed.publish(ActionCreated, &TestObject{ID: fmt.Sprint(i)})
+		<-fast.Queue()
+		received++
+	}
+	g.Expect(received).To(gomega.Equal(FeedQueue + 1))
+
+	// Dropped (queue closed) when full.
+	g.Expect(drain(slow)).To(gomega.HaveLen(FeedQueue))
+	_, open := <-slow.Queue()
+	g.Expect(open).To(gomega.BeFalse())
+	g.Expect(feed.subscribers).NotTo(gomega.HaveKey(slow))
+	g.Expect(feed.subscribers).To(gomega.HaveKey(fast))
+	slow.End()
+	fast.End()
+	g.Expect(feed.subscribers).To(gomega.BeEmpty())
+}
+
+func TestEventID(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	h := &Handler{}
+	cases := []struct {
+		id       string
+		epoch    int64
+		revision uint64
+		valid    bool
+	}{
+		{id: "1600000000-42", epoch: 1600000000, revision: 42, valid: true},
+		{id: "1-0", epoch: 1, valid: true},
+		{id: ""},
+		{id: "10"},
+		{id: "a-1"},
+		{id: "1-b"},
+		{id: "1--2"},
+		{id: "1-2-3"},
+	}
+	for _, c := range cases {
+		epoch, revision, err := h.eventID(c.id)
+		if !c.valid {
+			g.Expect(err).To(gomega.HaveOccurred(), c.id)
+			continue
+		}
+		g.Expect(err).NotTo(gomega.HaveOccurred(), c.id)
+		g.Expect(epoch).To(gomega.Equal(c.epoch), c.id)
+		g.Expect(revision).To(gomega.Equal(c.revision), c.id)
+	}
+}
+
+//
+// Open an in-memory DB with the journal enabled.
+func openDB(g *gomega.GomegaWithT, name string) (db libmodel.DB) {
+	db = libmodel.New(
+		fmt.Sprintf("file:%s?mode=memory&cache=shared", name),
+		&TestObject{})
+	db.Journal().Enable()
+	g.Expect(db.Open(true)).NotTo(gomega.HaveOccurred())
+	return
+}
+
+//
+// Drain the queued events.
+// Formatted as: <revision>:<action>:<id>.
+func drain(sub *Subscriber) (events []string) {
+	for {
+		select {
+		case event, open := <-sub.Queue():
+			if !open {
+				return
+			}
+			events = append(
+				events,
+				fmt.Sprintf(
+					"%d:%s:%s",
+					event.Revision,
+					event.Action,
+					event.Model.Pk()))
+		default:
+			return
+		}
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"github.com/gin-gonic/gin"
 	libcontainer "github.com/konveyor/controller/pkg/inventory/container"
 	libweb "github.com/konveyor/controller/pkg/inventory/web"
+	"github.com/konveyor/controller/pkg/logging"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/model"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"net/http"
 	"strconv"
@@ -22,8 +24,36 @@ const (
 	ProviderParam      = "provider"
 	DetailParam        = "detail"
 	NameParam          = "name"
+	LimitParam         = "limit"
+	OffsetParam        = "offset"
 )
 
+//
+// API version.
+// All routes are served with the version prefix. The
+// routes without the prefix are served using the current
+// version for compatibility.
+const (
+	Version     = "v1"
+	VersionRoot = "/" + Version
+)
+
+//
+// Headers.
+const (
+	// Inventory (cached) not yet reconciled with the provider.
+	StaleHeader = "X-Inventory-Stale"
+)
+
+//
+// Shared logger.
+var Log *logging.Logger
+
+func init() {
+	log := logging.WithName("web")
+	Log = &log
+}
+
 //
 // Params
 type Params = map[string]string
@@ -41,6 +71,8 @@ type Handler struct {
 	Reconciler libcontainer.Reconciler
 	// Resources include details.
 	Detail bool
+	// Collection query.
+	Query *Query
 }
 
 //
@@ -58,6 +90,10 @@ func (h *Handler) Prepare(ctx *gin.Context) int {
 	if status != http.StatusOK {
 		return status
 	}
+	status = h.setQuery(ctx)
+	if status != http.StatusOK {
+		return status
+	}
 
 	return http.StatusOK
 }
@@ -81,6 +117,11 @@ func (h *Handler) setProvider(ctx *gin.Context) int {
 		if status != http.StatusOK {
 			return status
 		}
+		if reporter, cast := h.Reconciler.(model.HealthReporter); cast {
+			if reporter.Health().Stale {
+				ctx.Header(StaleHeader, "true")
+			}
+		}
 	}
 
 	return http.StatusOK
@@ -103,14 +144,30 @@ func (h *Handler) setDetail(ctx *gin.Context) int {
 	return http.StatusOK
 }
 
+//
+// Set the collection query.
+func (h *Handler) setQuery(ctx *gin.Context) int {
+	h.Query = &Query{}
+	err := h.Query.With(ctx)
+	if err != nil {
+		return http.StatusBadRequest
+	}
+
+	return http.StatusOK
+}
+
 //
 // Build link.
+// The (absolute) path is prefixed with the API version.
 func (h *Handler) Link(path string, params Params) string {
 	for k, v := range params {
 		if len(v) > 0 {
 			path = strings.Replace(path, ":"+k, v, 1)
 		}
 	}
+	if strings.HasPrefix(path, "/") && !strings.HasPrefix(path, VersionRoot+"/") {
+		path = VersionRoot + path
+	}
 
 	return path
 }
@@ -0,0 +1,104 @@
+package base
+
+//
+// API documentation (by route).
+// Used to generate the OpenAPI specification.
+type Docs map[string]Doc
+
+//
+// Route documentation.
+type Doc struct {
+	// Summary.
+	Summary string
+	// The (REST) resource returned.
+	// A slice for collections.
+	Resource interface{}
+	// Query parameters.
+	Params []ParamDoc
+	// Media type. Default: application/json.
+	MediaType string
+}
+
+//
+// Query parameter documentation.
+type ParamDoc struct {
+	// Name.
+	Name string
+	// Type. Default: string.
+	Type string
+	// Description.
+	Description string
+	// May be specified more than once.
+	Repeated bool
+}
+
+//
+// Query parameters supported by resources.
+var DetailParams = []ParamDoc{
+	{
+		Name:        DetailParam,
+		Type:        "boolean",
+		Description: "Include resource details.",
+	},
+}
+
+//
+// Query parameters supported by collections.
+var ListParams = JoinParams(
+	DetailParams,
+	[]ParamDoc{
+		{
+			Name:        NameParam,
+			Description: "Match the resource name.",
+		},
+		{
+			Name:        LimitParam,
+			Type:        "integer",
+			Description: "Page limit.",
+		},
+		{
+			Name:        OffsetParam,
+			Type:        "integer",
+			Description: "Page offset.",
+		},
+	})
+
+//
+// Collection query parameters.
+// See: Query.
+var QueryParams = []ParamDoc{
+	{
+		Name:        FilterParam,
+		Description: "Filter expression. Example: cpuCount > 4 && powerState == \"poweredOn\".",
+	},
+	{
+		Name:        SortParam,
+		Description: "Comma separated fields. Prefixed with `-` for descending.",
+	},
+	{
+		Name:        FieldsParam,
+		Description: "Comma separated fields to be returned.",
+	},
+}
+
+//
+// Query parameters supported by event streams.
+var EventParams = JoinParams(
+	DetailParams,
+	[]ParamDoc{
+		{
+			Name:        RevisionParam,
+			Description: "Resume after the event ID (<epoch>-<revision>). See: Last-Event-ID.",
+		},
+	})
+
+//
+// Join parameter lists.
+func JoinParams(lists ...[]ParamDoc) (joined []ParamDoc) {
+	joined = []ParamDoc{}
+	for _, list := range lists {
+		joined = append(joined, list...)
+	}
+
+	return
+}
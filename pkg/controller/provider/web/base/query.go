@@ -0,0 +1,368 @@
+package base
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/analysis"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+//
+// Query parameters.
+const (
+	FilterParam = "filter"
+	SortParam   = "sort"
+	FieldsParam = "fields"
+)
+
+//
+// REST resource content.
+type Content interface {
+	// As content.
+	Content(detail bool) interface{}
+}
+
+//
+// Collection query.
+// Example:
+//   ?filter=cpuCount > 4 && has(disks, "datastore.id", "datastore-1")
+//   &sort=-memoryMB,name
+//   &fields=id,name,cpuCount,memoryMB
+// The filter is an analysis expression evaluated against
+// each (detailed) resource. Comparisons of numeric and boolean
+// fields joined by `&&` are translated to DB predicates.
+// Sort fields prefixed with `-` are descending.
+type Query struct {
+	// Filter.
+	Filter *analysis.Expression
+	// Sort fields.
+	Sort []string
+	// Projected fields.
+	Fields []string
+	// The filter is fully translated to the DB predicate.
+	exact bool
+	// Filtered, sorted and paged in memory.
+	inMemory bool
+}
+
+//
+// Parse the query parameters.
+func (q *Query) With(ctx *gin.Context) (err error) {
+	params := ctx.Request.URL.Query()
+	if s := params.Get(FilterParam); s != "" {
+		q.Filter, err = analysis.Compile(s)
+		if err != nil {
+			return
+		}
+	}
+	q.Sort = q.split(params.Get(SortParam))
+	q.Fields = q.split(params.Get(FieldsParam))
+
+	return
+}
+
+//
+// Update the list options.
+// The DB predicate is built using the filter comparisons on
+// fields (columns) of the model. The DB page is not used when
+// the filter cannot be fully translated or sorting is requested.
+func (q *Query) Options(options *libmodel.ListOptions, m interface{}) {
+	q.exact = true
+	q.inMemory = false
+	if q.Filter != nil {
+		var p libmodel.Predicate
+		p, q.exact = q.predicate(m)
+		if p != nil {
+			if options.Predicate != nil {
+				p = libmodel.And(options.Predicate, p)
+			}
+			options.Predicate = p
+		}
+	}
+	if !q.exact || len(q.Sort) > 0 {
+		q.inMemory = true
+		options.Page = nil
+	}
+	if q.Filter != nil || len(q.Sort) > 0 || len(q.Fields) > 0 {
+		options.Detail = 1
+	}
+}
+
+//
+// Page in memory.
+// Used when the handler filters the listed models in
+// memory (after the DB query).
+func (q *Query) InMemory(options *libmodel.ListOptions) {
+	q.inMemory = true
+	options.Page = nil
+}
+
+//
+// Build the collection content.
+// Filter, sort and page (as needed) the resources and
+// project the requested fields.
+func (q *Query) Content(list []Content, detail bool, page *libmodel.Page) (content []interface{}, err error) {
+	content = []interface{}{}
+	if !q.inMemory && len(q.Fields) == 0 {
+		for _, r := range list {
+			content = append(content, r.Content(detail))
+		}
+		return
+	}
+	type item struct {
+		resource Content
+		object   map[string]interface{}
+	}
+	items := []item{}
+	for _, r := range list {
+		var object interface{}
+		object, err = analysis.Object(r.Content(true))
+		if err != nil {
+			return
+		}
+		mp, _ := object.(map[string]interface{})
+		if q.Filter != nil && !q.exact {
+			var matched bool
+			matched, err = q.Filter.Eval(analysis.Env(mp))
+			if err != nil {
+				return
+			}
+			if !matched {
+				continue
+			}
+		}
+		items = append(items, item{resource: r, object: mp})
+	}
+	if len(q.Sort) > 0 {
+		sort.SliceStable(
+			items,
+			func(i, j int) bool {
+				return q.less(items[i].object, items[j].object)
+			})
+	}
+	if q.inMemory && page != nil {
+		page.Slice(&items)
+	}
+	for _, item := range items {
+		if len(q.Fields) > 0 {
+			content = append(content, q.project(item.object))
+		} else {
+			content = append(content, item.resource.Content(detail))
+		}
+	}
+
+	return
+}
+
+//
+// Build the DB predicate.
+// Only comparisons of numeric and boolean fields are translated.
+// String comparisons are case insensitive and evaluated in memory.
+func (q *Query) predicate(m interface{}) (p libmodel.Predicate, exact bool) {
+	comparisons, exact := q.Filter.Comparisons()
+	columns := q.columns(reflect.TypeOf(m))
+	predicates := []libmodel.Predicate{}
+	for _, c := range comparisons {
+		translated := q.translate(columns, c)
+		if translated == nil {
+			exact = false
+			continue
+		}
+		predicates = append(predicates, translated)
+	}
+	switch len(predicates) {
+	case 0:
+	case 1:
+		p = predicates[0]
+	default:
+		p = libmodel.And(predicates...)
+	}
+
+	return
+}
+
+//
+// Translate a comparison to a DB predicate.
+// Returns nil when not supported.
+func (q *Query) translate(columns map[string]reflect.Kind, c analysis.Comparison) (p libmodel.Predicate) {
+	kind, found := columns[strings.ToLower(c.Field)]
+	if !found {
+		return
+	}
+	switch kind {
+	case reflect.Bool:
+		b, cast := c.Value.(bool)
+		if !cast {
+			return
+		}
+		switch c.Op {
+		case "==":
+			p = libmodel.Eq(c.Field, b)
+		case "!=":
+			p = libmodel.Neq(c.Field, b)
+		}
+	case reflect.Int,
+		reflect.Int8,
+		reflect.Int16,
+		reflect.Int32,
+		reflect.Int64:
+		f, cast := c.Value.(float64)
+		if !cast || f != math.Trunc(f) {
+			return
+		}
+		n := int64(f)
+		switch c.Op {
+		case "==":
+			p = libmodel.Eq(c.Field, n)
+		case "!=":
+			p = libmodel.Neq(c.Field, n)
+		case "<":
+			p = libmodel.Lt(c.Field, n)
+		case ">":
+			p = libmodel.Gt(c.Field, n)
+		case "<=":
+			p = &orPredicate{libmodel.Or(libmodel.Lt(c.Field, n), libmodel.Eq(c.Field, n))}
+		case ">=":
+			p = &orPredicate{libmodel.Or(libmodel.Gt(c.Field, n), libmodel.Eq(c.Field, n))}
+		}
+	}
+
+	return
+}
+
+//
+// Parenthesized OR predicate.
+// The libmodel OR expression is not parenthesized and
+// would not be evaluated as expected when joined by AND.
+type orPredicate struct {
+	*libmodel.OrPredicate
+}
+
+//
+// Render the expression.
+func (p *orPredicate) Expr() string {
+	return "(" + p.OrPredicate.Expr() + ")"
+}
+
+//
+// Model (DB) columns.
+// Maps the (lower case) field name to kind.
+func (q *Query) columns(mt reflect.Type) (columns map[string]reflect.Kind) {
+	columns = map[string]reflect.Kind{}
+	if mt.Kind() == reflect.Ptr {
+		mt = mt.Elem()
+	}
+	if mt.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < mt.NumField(); i++ {
+		f := mt.Field(i)
+		_, tagged := f.Tag.Lookup("sql")
+		if f.Anonymous && !tagged {
+			for k, v := range q.columns(f.Type) {
+				columns[k] = v
+			}
+			continue
+		}
+		if tagged {
+			columns[strings.ToLower(f.Name)] = f.Type.Kind()
+		}
+	}
+
+	return
+}
+
+//
+// Sort comparison.
+func (q *Query) less(a, b map[string]interface{}) bool {
+	for _, name := range q.Sort {
+		desc := strings.HasPrefix(name, "-")
+		name = strings.TrimLeft(name, "-+")
+		n := q.compare(q.field(a, name), q.field(b, name))
+		if n == 0 {
+			continue
+		}
+		if desc {
+			return n > 0
+		}
+		return n < 0
+	}
+
+	return false
+}
+
+//
+// Compare field values.
+// Missing (nil) values are ordered first.
+func (q *Query) compare(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	af, aNum := a.(float64)
+	bf, bNum := b.(float64)
+	if aNum && bNum {
+		switch {
+		case af < bf:
+			return -1
+		case af > bf:
+			return 1
+		}
+		return 0
+	}
+
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+//
+// Get a (dotted) field (case insensitive).
+func (q *Query) field(object map[string]interface{}, name string) (v interface{}) {
+	v = object
+	for _, part := range strings.Split(name, ".") {
+		mp, cast := v.(map[string]interface{})
+		if !cast {
+			return nil
+		}
+		v = nil
+		for k, fv := range mp {
+			if strings.EqualFold(k, part) {
+				v = fv
+				break
+			}
+		}
+	}
+
+	return
+}
+
+//
+// Project the requested fields.
+func (q *Query) project(object map[string]interface{}) (projected map[string]interface{}) {
+	projected = map[string]interface{}{}
+	for _, name := range q.Fields {
+		projected[name] = q.field(object, name)
+	}
+
+	return
+}
+
+//
+// Split a comma separated list.
+func (q *Query) split(s string) (list []string) {
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			list = append(list, part)
+		}
+	}
+
+	return
+}
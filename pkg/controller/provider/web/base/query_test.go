@@ -0,0 +1,320 @@
+package base
+
+import (
+	"fmt"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/analysis"
+	"github.com/onsi/gomega"
+	"testing"
+)
+
+type TestVM struct {
+	ID       string `sql:"pk"`
+	Name     string `sql:""`
+	CpuCount int32  `sql:""`
+	Enabled  bool   `sql:""`
+	HostName string `sql:""`
+}
+
+func (m *TestVM) Pk() string {
+	return m.ID
+}
+
+func (m *TestVM) String() string {
+	return m.ID
+}
+
+func (m *TestVM) Equals(other libmodel.Model) bool {
+	return false
+}
+
+func (m *TestVM) Labels() libmodel.Labels {
+	return nil
+}
+
+type TestHost struct {
+	Name string `json:"name"`
+}
+
+type TestResource struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	CpuCount int32     `json:"cpuCount"`
+	Enabled  bool      `json:"enabled"`
+	Host     *TestHost `json:"host,omitempty"`
+}
+
+func (r *TestResource) With(m *TestVM) {
+	r.ID = m.ID
+	r.Name = m.Name
+	r.CpuCount = m.CpuCount
+	r.Enabled = m.Enabled
+	if m.HostName != "" {
+		r.Host = &TestHost{Name: m.HostName}
+	}
+}
+
+func (r *TestResource) Content(detail bool) interface{} {
+	if !detail {
+		return map[string]interface{}{"id": r.ID}
+	}
+	return r
+}
+
+func TestQueryOptions(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	page := func() *libmodel.Page {
+		return &libmodel.Page{Limit: 2}
+	}
+	cases := []struct {
+		name     string
+		filter   string
+		sort     []string
+		fields   []string
+		exact    bool
+		inMemory bool
+		detail   int
+	}{
+		{
+			name:  "none",
+			exact: true,
+		},
+		{
+			name:   "numeric",
+			filter: "cpuCount > 2",
+			exact:  true,
+			detail: 1,
+		},
+		{
+			name:   "numeric and boolean",
+			filter: "cpuCount >= 2 && enabled == true",
+			exact:  true,
+			detail: 1,
+		},
+		{
+			name:     "string",
+			filter:   `name == "b"`,
+			inMemory: true,
+			detail:   1,
+		},
+		{
+			name:     "partial",
+			filter:   `cpuCount > 2 && name == "b"`,
+			inMemory: true,
+			detail:   1,
+		},
+		{
+			name:     "not a column",
+			filter:   "memoryMB > 2",
+			inMemory: true,
+			detail:   1,
+		},
+		{
+			name:     "fraction",
+			filter:   "cpuCount > 2.5",
+			inMemory: true,
+			detail:   1,
+		},
+		{
+			name:     "sorted",
+			sort:     []string{"name"},
+			exact:    true,
+			inMemory: true,
+			detail:   1,
+		},
+		{
+			name:   "projected",
+			fields: []string{"name"},
+			exact:  true,
+			detail: 1,
+		},
+	}
+	for _, c := range cases {
+		q := &Query{Sort: c.sort, Fields: c.fields}
+		if c.filter != "" {
+			filter, err := analysis.Compile(c.filter)
+			g.Expect(err).NotTo(gomega.HaveOccurred(), c.name)
+			q.Filter = filter
+		}
+		options := &libmodel.ListOptions{Page: page()}
+		q.Options(options, &TestVM{})
+		g.Expect(q.exact).To(gomega.Equal(c.exact), c.name)
+		g.Expect(q.inMemory).To(gomega.Equal(c.inMemory), c.name)
+		g.Expect(options.Detail).To(gomega.Equal(c.detail), c.name)
+		if c.inMemory {
+			g.Expect(options.Page).To(gomega.BeNil(), c.name)
+		} else {
+			g.Expect(options.Page).To(gomega.Equal(page()), c.name)
+		}
+	}
+
+	// Paged in memory.
+	q := &Query{}
+	options := &libmodel.ListOptions{Page: page()}
+	q.Options(options, &TestVM{})
+	q.InMemory(options)
+	g.Expect(q.inMemory).To(gomega.BeTrue())
+	g.Expect(options.Page).To(gomega.BeNil())
+
+	// Combined with the handler predicate.
+	filter, _ := analysis.Compile("cpuCount > 2")
+	q = &Query{Filter: filter}
+	options = &libmodel.ListOptions{Predicate: libmodel.Eq("enabled", true)}
+	q.Options(options, &TestVM{})
+	_, and := options.Predicate.(*libmodel.AndPredicate)
+	g.Expect(and).To(gomega.BeTrue())
+}
+
+func TestQueryPredicate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := openQueryDB(g)
+	defer db.Close(true)
+	cases := []struct {
+		filter   string
+		or       bool
+		expected []string
+	}{
+		{filter: "cpuCount == 3", expected: []string{"3"}},
+		{filter: "cpuCount != 3", expected: []string{"1", "2", "4", "5"}},
+		{filter: "cpuCount < 3", expected: []string{"1", "2"}},
+		{filter: "cpuCount > 3", expected: []string{"4", "5"}},
+		{filter: "cpuCount <= 3", or: true, expected: []string{"1", "2", "3"}},
+		{filter: "cpuCount >= 3", or: true, expected: []string{"3", "4", "5"}},
+		{filter: "enabled == true", expected: []string{"2", "4"}},
+		{filter: "enabled != true", expected: []string{"1", "3", "5"}},
+		{filter: "cpuCount >= 2 && enabled == true", expected: []string{"2", "4"}},
+		{filter: "enabled == true && cpuCount <= 3", expected: []string{"2"}},
+	}
+	for _, c := range cases {
+		filter, err := analysis.Compile(c.filter)
+		g.Expect(err).NotTo(gomega.HaveOccurred(), c.filter)
+		q := &Query{Filter: filter}
+		p, exact := q.predicate(&TestVM{})
+		g.Expect(exact).To(gomega.BeTrue(), c.filter)
+		_, or := p.(*orPredicate)
+		g.Expect(or).To(gomega.Equal(c.or), c.filter)
+		list := []TestVM{}
+		err = db.List(&list, libmodel.ListOptions{Predicate: p})
+		g.Expect(err).NotTo(gomega.HaveOccurred(), c.filter)
+		ids := []string{}
+		for _, m := range list {
+			ids = append(ids, m.ID)
+		}
+		g.Expect(ids).To(gomega.ConsistOf(c.expected), c.filter)
+	}
+}
+
+func TestQueryContent(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := openQueryDB(g)
+	defer db.Close(true)
+	content := func(q *Query, page *libmodel.Page) (ids []string, content []interface{}) {
+		options := &libmodel.ListOptions{Page: page}
+		q.Options(options, &TestVM{})
+		list := []TestVM{}
+		g.Expect(db.List(&list, *options)).NotTo(gomega.HaveOccurred())
+		resources := []Content{}
+		for i := range list {
+			r := &TestResource{}
+			r.With(&list[i])
+			resources = append(resources, r)
+		}
+		content, err := q.Content(resources, true, page)
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		for _, item := range content {
+			switch r := item.(type) {
+			case *TestResource:
+				ids = append(ids, r.ID)
+			case map[string]interface{}:
+				ids = append(ids, fmt.Sprint(r["id"]))
+			}
+		}
+		return
+	}
+
+	// Filtered in memory then paged.
+	filter, _ := analysis.Compile(`name != "b" && cpuCount > 1`)
+	ids, _ := content(&Query{Filter: filter}, &libmodel.Page{Offset: 1, Limit: 2})
+	g.Expect(ids).To(gomega.Equal([]string{"4", "5"}))
+
+	// Paged by the DB (exact).
+	filter, _ = analysis.Compile("cpuCount > 1")
+	ids, _ = content(&Query{Filter: filter}, &libmodel.Page{Offset: 1, Limit: 2})
+	g.Expect(ids).To(gomega.HaveLen(2))
+	g.Expect(ids).NotTo(gomega.ContainElement("1"))
+
+	// Sorted; missing (nil) values first.
+	ids, _ = content(&Query{Sort: []string{"host.name", "-cpuCount"}}, nil)
+	g.Expect(ids).To(gomega.Equal([]string{"5", "3", "1", "4", "2"}))
+	ids, _ = content(&Query{Sort: []string{"-host.name", "cpuCount"}}, nil)
+	g.Expect(ids).To(gomega.Equal([]string{"2", "4", "1", "3", "5"}))
+
+	// Sorted then paged.
+	ids, _ = content(&Query{Sort: []string{"-cpuCount"}}, &libmodel.Page{Offset: 1, Limit: 2})
+	g.Expect(ids).To(gomega.Equal([]string{"4", "3"}))
+
+	// Projected (dotted, case insensitive).
+	_, projected := content(
+		&Query{
+			Fields: []string{"id", "Host.Name", "missing", "name.first"},
+			Sort:   []string{"id"},
+		},
+		nil)
+	g.Expect(projected).To(gomega.HaveLen(5))
+	g.Expect(projected[0]).To(
+		gomega.Equal(
+			map[string]interface{}{
+				"id":         "1",
+				"Host.Name":  "host-b",
+				"missing":    nil,
+				"name.first": nil,
+			}))
+	g.Expect(projected[4]).To(
+		gomega.Equal(
+			map[string]interface{}{
+				"id":         "5",
+				"Host.Name":  nil,
+				"missing":    nil,
+				"name.first": nil,
+			}))
+}
+
+func TestQuerySplitCompare(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	q := &Query{}
+	g.Expect(q.split(" a, ,b,")).To(gomega.Equal([]string{"a", "b"}))
+	g.Expect(q.split("")).To(gomega.BeEmpty())
+	g.Expect(q.compare(nil, nil)).To(gomega.Equal(0))
+	g.Expect(q.compare(nil, 1.0)).To(gomega.Equal(-1))
+	g.Expect(q.compare(1.0, nil)).To(gomega.Equal(1))
+	g.Expect(q.compare(2.0, 10.0)).To(gomega.Equal(-1))
+	g.Expect(q.compare("2", "10")).To(gomega.Equal(1))
+}
+
+//
+// Open an in-memory DB populated with VMs:
+//   id  name  cpuCount  enabled  host
+//   1   a     1         false    host-b
+//   2   b     2         true     host-c
+//   3   c     3         false    host-b
+//   4   d     4         true     host-c
+//   5   e     5         false    -
+func openQueryDB(g *gomega.GomegaWithT) (db libmodel.DB) {
+	db = libmodel.New(
+		"file:query?mode=memory&cache=shared",
+		&TestVM{})
+	g.Expect(db.Open(true)).NotTo(gomega.HaveOccurred())
+	hosts := []string{"host-b", "host-c", "host-b", "host-c", ""}
+	for i := 1; i <= len(hosts); i++ {
+		m := &TestVM{
+			ID:       fmt.Sprint(i),
+			Name:     string(rune('a' + i - 1)),
+			CpuCount: int32(i),
+			Enabled:  i%2 == 0,
+			HostName: hosts[i-1],
+		}
+		g.Expect(db.Insert(m)).NotTo(gomega.HaveOccurred())
+	}
+
+	return
+}
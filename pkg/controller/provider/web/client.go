@@ -11,16 +11,6 @@ import (
 	"path"
 )
 
-//
-// Resource kind cannot be resolved.
-type ProviderNotSupportedError struct {
-	*api.Provider
-}
-
-func (r ProviderNotSupportedError) Error() string {
-	return fmt.Sprintf("Provider (type) not supported: %#v", r.Provider)
-}
-
 //
 // Resource kind cannot be resolved.
 type ProviderNotReadyError struct {
@@ -31,6 +21,7 @@ func (r ProviderNotReadyError) Error() string {
 	return fmt.Sprintf("Provider not ready: %#v", r.Provider)
 }
 
+type ProviderNotSupportedError = base.ProviderNotSupportedError
 type RefNotUniqueError = base.RefNotUniqueError
 type NotFoundError = base.NotFoundError
 
@@ -47,7 +38,7 @@ func NewClient(provider *api.Provider) (client Client, err error) {
 	case api.OpenShift:
 		client = &ProviderClient{
 			provider: provider,
-			finder:   &ocp.Finder{},
+			finder:   &ocp.Finder{Provider: provider},
 			restClient: base.RestClient{
 				Resolver: &ocp.Resolver{Provider: provider},
 			},
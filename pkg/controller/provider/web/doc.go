@@ -41,6 +41,47 @@ func All(container *container.Container) (all []libweb.RequestHandler) {
 	all = append(
 		all,
 		vsphere.Handlers(container)...)
+	all = append(
+		all,
+		&OpenAPIHandler{
+			Docs: Docs(),
+		},
+		&VersionHandler{})
+
+	return
+}
+
+//
+// API documentation.
+func Docs() (docs base.Docs) {
+	docs = base.Docs{
+		"/schema": {
+			Summary: "List the routes.",
+		},
+		"/" + libweb.NsCollection: {
+			Summary:  "List namespaces containing providers.",
+			Resource: []Namespace{},
+		},
+		base.Root: {
+			Summary:  "Get a namespace.",
+			Resource: Namespace{},
+		},
+		base.ProvidersRoot: {
+			Summary:  "List providers in the namespace (by type).",
+			Resource: map[string][]interface{}{},
+			Params:   base.DetailParams,
+		},
+		ProvidersRoot: {
+			Summary:  "List providers in all namespaces (by type).",
+			Resource: map[string][]interface{}{},
+			Params:   base.DetailParams,
+		},
+	}
+	for _, d := range []base.Docs{ocp.Docs(), vsphere.Docs()} {
+		for path, doc := range d {
+			docs[path] = doc
+		}
+	}
 
 	return
 }
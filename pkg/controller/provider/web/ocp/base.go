@@ -6,12 +6,46 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
 )
 
+//
+// Fields.
+const (
+	DetailParam    = base.DetailParam
+	NameParam      = base.NameParam
+	NamespaceField = "namespace"
+)
+
 //
 // Base handler.
 type Handler struct {
 	base.Handler
 }
 
+//
+// Build list predicate.
+// Namespaced collections are limited to the namespace
+// in the path and may be filtered by name.
+func (h Handler) Predicate(ctx *gin.Context) (p libmodel.Predicate) {
+	list := []libmodel.Predicate{}
+	ns := ctx.Param(Ns2Param)
+	if len(ns) > 0 {
+		list = append(list, libmodel.Eq(NamespaceField, ns))
+	}
+	q := ctx.Request.URL.Query()
+	name := q.Get(NameParam)
+	if len(name) > 0 {
+		list = append(list, libmodel.Eq(NameParam, name))
+	}
+	switch len(list) {
+	case 0:
+	case 1:
+		p = list[0]
+	default:
+		p = libmodel.And(list...)
+	}
+
+	return
+}
+
 //
 // Build list options.
 func (h Handler) ListOptions(ctx *gin.Context) libmodel.ListOptions {
@@ -20,7 +54,8 @@ func (h Handler) ListOptions(ctx *gin.Context) libmodel.ListOptions {
 		detail = 1
 	}
 	return libmodel.ListOptions{
-		Detail: detail,
-		Page:   &h.Page,
+		Predicate: h.Predicate(ctx),
+		Detail:    detail,
+		Page:      &h.Page,
 	}
 }
@@ -6,11 +6,13 @@ import (
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/ocp"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
 	pathlib "path"
+	"reflect"
 	"strings"
 )
 
 //
 // Errors.
+type ProviderNotSupportedError = base.ProviderNotSupportedError
 type ResourceNotResolvedError = base.ResourceNotResolvedError
 type RefNotUniqueError = base.RefNotUniqueError
 type NotFoundError = base.NotFoundError
@@ -40,6 +42,10 @@ func (r *Resolver) Path(object interface{}, id string) (path string, err error)
 		})
 	case *Namespace:
 		h := NamespaceHandler{}
+		if id == "/" { // list
+			path = r.all(&h.Handler, NamespacesRoot)
+			break
+		}
 		path = h.Link(
 			r.Provider,
 			&model.Namespace{
@@ -49,6 +55,10 @@ func (r *Resolver) Path(object interface{}, id string) (path string, err error)
 			})
 	case *StorageClass:
 		h := StorageClassHandler{}
+		if id == "/" { // list
+			path = r.all(&h.Handler, StorageClassesRoot)
+			break
+		}
 		path = h.Link(
 			r.Provider,
 			&model.StorageClass{
@@ -57,10 +67,11 @@ func (r *Resolver) Path(object interface{}, id string) (path string, err error)
 				},
 			})
 	case *NetworkAttachmentDefinition:
-		if id == "" { // list
-			ns = r.Provider.Namespace
-		}
 		h := NetworkAttachmentDefinitionHandler{}
+		if id == "/" { // list
+			path = r.all(&h.Handler, AllNetworkAttachmentDefinitionsRoot)
+			break
+		}
 		path = h.Link(
 			r.Provider,
 			&model.NetworkAttachmentDefinition{
@@ -69,6 +80,116 @@ func (r *Resolver) Path(object interface{}, id string) (path string, err error)
 					Name:      name,
 				},
 			})
+	case *VM:
+		h := VMHandler{}
+		if id == "/" { // list
+			path = r.all(&h.Handler, AllVMsRoot)
+			break
+		}
+		path = h.Link(
+			r.Provider,
+			&model.VM{
+				Base: model.Base{
+					Namespace: ns,
+					Name:      name,
+				},
+			})
+	case *VMI:
+		h := VMIHandler{}
+		if id == "/" { // list
+			path = r.all(&h.Handler, AllVMIsRoot)
+			break
+		}
+		path = h.Link(
+			r.Provider,
+			&model.VMI{
+				Base: model.Base{
+					Namespace: ns,
+					Name:      name,
+				},
+			})
+	case *PersistentVolumeClaim:
+		h := PersistentVolumeClaimHandler{}
+		if id == "/" { // list
+			path = r.all(&h.Handler, AllPersistentVolumeClaimsRoot)
+			break
+		}
+		path = h.Link(
+			r.Provider,
+			&model.PersistentVolumeClaim{
+				Base: model.Base{
+					Namespace: ns,
+					Name:      name,
+				},
+			})
+	case *DataVolume:
+		h := DataVolumeHandler{}
+		if id == "/" { // list
+			path = r.all(&h.Handler, AllDataVolumesRoot)
+			break
+		}
+		path = h.Link(
+			r.Provider,
+			&model.DataVolume{
+				Base: model.Base{
+					Namespace: ns,
+					Name:      name,
+				},
+			})
+	case *PersistentVolume:
+		h := PersistentVolumeHandler{}
+		if id == "/" { // list
+			path = r.all(&h.Handler, PersistentVolumesRoot)
+			break
+		}
+		path = h.Link(
+			r.Provider,
+			&model.PersistentVolume{
+				Base: model.Base{
+					Name: name,
+				},
+			})
+	case *Node:
+		h := NodeHandler{}
+		if id == "/" { // list
+			path = r.all(&h.Handler, NodesRoot)
+			break
+		}
+		path = h.Link(
+			r.Provider,
+			&model.Node{
+				Base: model.Base{
+					Name: name,
+				},
+			})
+	case *ResourceQuota:
+		h := ResourceQuotaHandler{}
+		if id == "/" { // list
+			path = r.all(&h.Handler, AllResourceQuotasRoot)
+			break
+		}
+		path = h.Link(
+			r.Provider,
+			&model.ResourceQuota{
+				Base: model.Base{
+					Namespace: ns,
+					Name:      name,
+				},
+			})
+	case *LimitRange:
+		h := LimitRangeHandler{}
+		if id == "/" { // list
+			path = r.all(&h.Handler, AllLimitRangesRoot)
+			break
+		}
+		path = h.Link(
+			r.Provider,
+			&model.LimitRange{
+				Base: model.Base{
+					Namespace: ns,
+					Name:      name,
+				},
+			})
 	default:
 		err = liberr.Wrap(
 			ResourceNotResolvedError{
@@ -79,10 +200,24 @@ func (r *Resolver) Path(object interface{}, id string) (path string, err error)
 	return
 }
 
+//
+// Path to a collection.
+// Namespaced collections include all namespaces.
+func (r *Resolver) all(h *Handler, root string) string {
+	return h.Link(
+		root,
+		base.Params{
+			base.NsParam:       r.Provider.Namespace,
+			base.ProviderParam: r.Provider.Name,
+		})
+}
+
 //
 // Resource finder.
 type Finder struct {
 	base.Client
+	// The provider.
+	Provider *api.Provider
 }
 
 //
@@ -100,17 +235,89 @@ func (r *Finder) With(client base.Client) base.Finder {
 //   NotFoundErr
 //   RefNotUniqueErr
 func (r *Finder) ByRef(resource interface{}, ref base.Ref) (err error) {
+	var list interface{}
+	switch resource.(type) {
+	case *VM:
+		list = &[]VM{}
+	case *VMI:
+		list = &[]VMI{}
+	case *NetworkAttachmentDefinition:
+		list = &[]NetworkAttachmentDefinition{}
+	case *StorageClass:
+		list = &[]StorageClass{}
+	case *PersistentVolumeClaim:
+		list = &[]PersistentVolumeClaim{}
+	case *PersistentVolume:
+		list = &[]PersistentVolume{}
+	case *DataVolume:
+		list = &[]DataVolume{}
+	case *Node:
+		list = &[]Node{}
+	case *ResourceQuota:
+		list = &[]ResourceQuota{}
+	case *LimitRange:
+		list = &[]LimitRange{}
+	default:
+		err = liberr.Wrap(
+			ResourceNotResolvedError{
+				Object: resource,
+			})
+		return
+	}
+	params := []base.Param{
+		{
+			Key:   DetailParam,
+			Value: "1",
+		},
+	}
+	if ref.ID == "" {
+		_, name := pathlib.Split(ref.Name)
+		params = append(
+			params,
+			base.Param{
+				Key:   NameParam,
+				Value: name,
+			})
+	}
+	err = r.List(list, params...)
+	if err != nil {
+		return
+	}
+	matched := []reflect.Value{}
+	lv := reflect.ValueOf(list).Elem()
+	for i := 0; i < lv.Len(); i++ {
+		item := lv.Index(i)
+		if m, cast := item.Addr().Interface().(interface{ Match(base.Ref) bool }); cast {
+			if m.Match(ref) {
+				matched = append(matched, item)
+			}
+		}
+	}
+	switch len(matched) {
+	case 0:
+		err = liberr.Wrap(NotFoundError{Ref: ref})
+	case 1:
+		reflect.ValueOf(resource).Elem().Set(matched[0])
+	default:
+		err = liberr.Wrap(RefNotUniqueError{Ref: ref})
+	}
+
 	return
 }
 
 //
 // Find a VM by ref.
-// Returns the matching resource and:
+// Not supported: OpenShift providers are supported only as
+// the migration destination. The (KubeVirt) VMs are collected
+// to report those that already exist. See: ByRef().
+// Returns:
 //   ProviderNotSupportedErr
-//   ProviderNotReadyErr
-//   NotFoundErr
-//   RefNotUniqueErr
 func (r *Finder) VM(ref *base.Ref) (object interface{}, err error) {
+	err = liberr.Wrap(
+		ProviderNotSupportedError{
+			Provider: r.Provider,
+		})
+
 	return
 }
 
@@ -122,6 +329,14 @@ func (r *Finder) VM(ref *base.Ref) (object interface{}, err error) {
 //   NotFoundErr
 //   RefNotUniqueErr
 func (r *Finder) Network(ref *base.Ref) (object interface{}, err error) {
+	network := &NetworkAttachmentDefinition{}
+	err = r.ByRef(network, *ref)
+	if err == nil {
+		ref.ID = pathlib.Join(network.Namespace, network.Name)
+		ref.Name = network.Name
+		object = network
+	}
+
 	return
 }
 
@@ -133,6 +348,14 @@ func (r *Finder) Network(ref *base.Ref) (object interface{}, err error) {
 //   NotFoundErr
 //   RefNotUniqueErr
 func (r *Finder) Storage(ref *base.Ref) (object interface{}, err error) {
+	sc := &StorageClass{}
+	err = r.ByRef(sc, *ref)
+	if err == nil {
+		ref.ID = pathlib.Join(sc.Namespace, sc.Name)
+		ref.Name = sc.Name
+		object = sc
+	}
+
 	return
 }
 
@@ -0,0 +1,159 @@
+package ocp
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/ocp"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	cdi "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"net/http"
+)
+
+//
+// Routes.
+const (
+	DataVolumeParam    = "dv"
+	DataVolumesRoot    = NamespaceRoot + "/datavolumes"
+	AllDataVolumesRoot = ProviderRoot + "/datavolumes"
+	DataVolumeRoot     = DataVolumesRoot + "/:" + DataVolumeParam
+)
+
+//
+// DataVolume handler.
+type DataVolumeHandler struct {
+	Handler
+}
+
+//
+// Add routes to the `gin` router.
+func (h *DataVolumeHandler) AddRoutes(e *gin.Engine) {
+	e.GET(AllDataVolumesRoot, h.ListAll)
+	e.GET(DataVolumesRoot, h.List)
+	e.GET(DataVolumesRoot+"/", h.List)
+	e.GET(DataVolumeRoot, h.Get)
+}
+
+//
+// List resources in a REST collection (all namespaces).
+func (h DataVolumeHandler) ListAll(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	db := h.Reconciler.DB()
+	list := []model.DataVolume{}
+	err := db.List(&list, h.ListOptions(ctx))
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	content := []interface{}{}
+	for _, m := range list {
+		r := &DataVolume{}
+		r.With(&m)
+		r.SelfLink = h.Link(h.Provider, &m)
+		content = append(content, r.Content(h.Detail))
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// List resources in a REST collection.
+func (h DataVolumeHandler) List(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	db := h.Reconciler.DB()
+	list := []model.DataVolume{}
+	err := db.List(&list, h.ListOptions(ctx))
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	content := []interface{}{}
+	for _, m := range list {
+		r := &DataVolume{}
+		r.With(&m)
+		r.SelfLink = h.Link(h.Provider, &m)
+		content = append(content, r.Content(h.Detail))
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// Get a specific REST resource.
+func (h DataVolumeHandler) Get(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	m := &model.DataVolume{
+		Base: model.Base{
+			Namespace: ctx.Param(Ns2Param),
+			Name:      ctx.Param(DataVolumeParam),
+		},
+	}
+	db := h.Reconciler.DB()
+	err := db.Get(m)
+	if errors.Is(err, model.NotFound) {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	r := &DataVolume{}
+	r.With(m)
+	r.SelfLink = h.Link(h.Provider, m)
+	content := r.Content(true)
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// Build self link (URI).
+func (h DataVolumeHandler) Link(p *api.Provider, m *model.DataVolume) string {
+	return h.Handler.Link(
+		DataVolumeRoot,
+		base.Params{
+			base.NsParam:       p.Namespace,
+			base.ProviderParam: p.Name,
+			Ns2Param:           m.Namespace,
+			DataVolumeParam:    m.Name,
+		})
+}
+
+//
+// REST Resource.
+type DataVolume struct {
+	Resource
+	Object cdi.DataVolume `json:"object"`
+}
+
+//
+// Set fields with the specified object.
+func (r *DataVolume) With(m *model.DataVolume) {
+	r.Resource.With(&m.Base)
+	r.Object = m.Object
+}
+
+//
+// As content.
+func (r *DataVolume) Content(detail bool) interface{} {
+	if !detail {
+		return r.Resource
+	}
+
+	return r
+}
@@ -47,5 +47,212 @@ func Handlers(container *container.Container) []libweb.RequestHandler {
 				base.Handler{Container: container},
 			},
 		},
+		&VMHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&VMIHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&PersistentVolumeClaimHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&PersistentVolumeHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&DataVolumeHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&NodeHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&ResourceQuotaHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&LimitRangeHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&EventHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+	}
+}
+
+//
+// API documentation.
+func Docs() base.Docs {
+	return base.Docs{
+		ProvidersRoot: {
+			Summary:  "List OpenShift providers.",
+			Resource: []Provider{},
+			Params:   base.ListParams,
+		},
+		ProviderRoot: {
+			Summary:  "Get an OpenShift provider.",
+			Resource: Provider{},
+		},
+		NamespacesRoot: {
+			Summary:  "List namespaces.",
+			Resource: []Namespace{},
+			Params:   base.ListParams,
+		},
+		NamespaceRoot: {
+			Summary:  "Get a namespace.",
+			Resource: Namespace{},
+		},
+		NodesRoot: {
+			Summary:  "List nodes.",
+			Resource: []Node{},
+			Params:   base.ListParams,
+		},
+		NodeRoot: {
+			Summary:  "Get a node.",
+			Resource: Node{},
+		},
+		StorageClassesRoot: {
+			Summary:  "List storage classes.",
+			Resource: []StorageClass{},
+			Params:   base.ListParams,
+		},
+		StorageClassRoot: {
+			Summary:  "Get a storage class.",
+			Resource: StorageClass{},
+		},
+		PersistentVolumesRoot: {
+			Summary:  "List persistent volumes.",
+			Resource: []PersistentVolume{},
+			Params:   base.ListParams,
+		},
+		PersistentVolumeRoot: {
+			Summary:  "Get a persistent volume.",
+			Resource: PersistentVolume{},
+		},
+		AllNetworkAttachmentDefinitionsRoot: {
+			Summary:  "List network attachment definitions in all namespaces.",
+			Resource: []NetworkAttachmentDefinition{},
+			Params:   base.ListParams,
+		},
+		NetworkAttachmentDefinitionsRoot: {
+			Summary:  "List network attachment definitions.",
+			Resource: []NetworkAttachmentDefinition{},
+			Params:   base.ListParams,
+		},
+		NetworkAttachmentDefinitionRoot: {
+			Summary:  "Get a network attachment definition.",
+			Resource: NetworkAttachmentDefinition{},
+		},
+		AllVMsRoot: {
+			Summary:  "List virtual machines in all namespaces.",
+			Resource: []VM{},
+			Params:   base.ListParams,
+		},
+		VMsRoot: {
+			Summary:  "List virtual machines.",
+			Resource: []VM{},
+			Params:   base.ListParams,
+		},
+		VMRoot: {
+			Summary:  "Get a virtual machine.",
+			Resource: VM{},
+		},
+		AllVMIsRoot: {
+			Summary:  "List virtual machine instances in all namespaces.",
+			Resource: []VMI{},
+			Params:   base.ListParams,
+		},
+		VMIsRoot: {
+			Summary:  "List virtual machine instances.",
+			Resource: []VMI{},
+			Params:   base.ListParams,
+		},
+		VMIRoot: {
+			Summary:  "Get a virtual machine instance.",
+			Resource: VMI{},
+		},
+		AllPersistentVolumeClaimsRoot: {
+			Summary:  "List persistent volume claims in all namespaces.",
+			Resource: []PersistentVolumeClaim{},
+			Params:   base.ListParams,
+		},
+		PersistentVolumeClaimsRoot: {
+			Summary:  "List persistent volume claims.",
+			Resource: []PersistentVolumeClaim{},
+			Params:   base.ListParams,
+		},
+		PersistentVolumeClaimRoot: {
+			Summary:  "Get a persistent volume claim.",
+			Resource: PersistentVolumeClaim{},
+		},
+		AllDataVolumesRoot: {
+			Summary:  "List data volumes in all namespaces.",
+			Resource: []DataVolume{},
+			Params:   base.ListParams,
+		},
+		DataVolumesRoot: {
+			Summary:  "List data volumes.",
+			Resource: []DataVolume{},
+			Params:   base.ListParams,
+		},
+		DataVolumeRoot: {
+			Summary:  "Get a data volume.",
+			Resource: DataVolume{},
+		},
+		AllResourceQuotasRoot: {
+			Summary:  "List resource quotas in all namespaces.",
+			Resource: []ResourceQuota{},
+			Params:   base.ListParams,
+		},
+		ResourceQuotasRoot: {
+			Summary:  "List resource quotas.",
+			Resource: []ResourceQuota{},
+			Params:   base.ListParams,
+		},
+		ResourceQuotaRoot: {
+			Summary:  "Get a resource quota.",
+			Resource: ResourceQuota{},
+		},
+		AllLimitRangesRoot: {
+			Summary:  "List limit ranges in all namespaces.",
+			Resource: []LimitRange{},
+			Params:   base.ListParams,
+		},
+		LimitRangesRoot: {
+			Summary:  "List limit ranges.",
+			Resource: []LimitRange{},
+			Params:   base.ListParams,
+		},
+		LimitRangeRoot: {
+			Summary:  "Get a limit range.",
+			Resource: LimitRange{},
+		},
+		EventsRoot: {
+			Summary:   "Stream inventory events.",
+			Resource:  base.EventResource{},
+			Params:    base.EventParams,
+			MediaType: base.EventMediaType,
+		},
+		CollectionEventsRoot: {
+			Summary:   "Stream inventory events for a collection.",
+			Resource:  base.EventResource{},
+			Params:    base.EventParams,
+			MediaType: base.EventMediaType,
+		},
 	}
 }
@@ -0,0 +1,182 @@
+package ocp
+
+import (
+	"github.com/gin-gonic/gin"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/ocp"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	"net/http"
+)
+
+//
+// Routes.
+const (
+	EventsRoot           = ProviderRoot + "/" + base.EventCollection
+	CollectionEventsRoot = EventsRoot + "/:" + base.CollectionParam
+)
+
+//
+// Event collections.
+const (
+	NamespaceCollection                   = "namespaces"
+	StorageClassCollection                = "storageclasses"
+	NetworkAttachmentDefinitionCollection = "networkattachmentdefinitions"
+	VMCollection                          = "virtualmachines"
+	VMICollection                         = "virtualmachineinstances"
+	PersistentVolumeClaimCollection       = "persistentvolumeclaims"
+	PersistentVolumeCollection            = "persistentvolumes"
+	DataVolumeCollection                  = "datavolumes"
+	NodeCollection                        = "nodes"
+	ResourceQuotaCollection               = "resourcequotas"
+	LimitRangeCollection                  = "limitranges"
+)
+
+//
+// Event (change feed) handler.
+type EventHandler struct {
+	Handler
+}
+
+//
+// Add routes to the `gin` router.
+func (h *EventHandler) AddRoutes(e *gin.Engine) {
+	e.GET(EventsRoot, h.List)
+	e.GET(EventsRoot+"/", h.List)
+	e.GET(CollectionEventsRoot, h.Get)
+}
+
+//
+// Stream events for all collections.
+func (h EventHandler) List(ctx *gin.Context) {
+	h.Get(ctx)
+}
+
+//
+// Stream events.
+// The collection (optional) limits the events to
+// those for the specified collection. Example: `namespaces`.
+func (h EventHandler) Get(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	collection := ctx.Param(base.CollectionParam)
+	switch collection {
+	case "",
+		NamespaceCollection,
+		StorageClassCollection,
+		NetworkAttachmentDefinitionCollection,
+		VMCollection,
+		VMICollection,
+		PersistentVolumeClaimCollection,
+		PersistentVolumeCollection,
+		DataVolumeCollection,
+		NodeCollection,
+		ResourceQuotaCollection,
+		LimitRangeCollection:
+	default:
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	feed, err := base.FeedFor(
+		h.Reconciler.DB(),
+		[]interface{}{
+			&model.Namespace{},
+			&model.StorageClass{},
+			&model.NetworkAttachmentDefinition{},
+			&model.VM{},
+			&model.VMI{},
+			&model.PersistentVolumeClaim{},
+			&model.PersistentVolume{},
+			&model.DataVolume{},
+			&model.Node{},
+			&model.ResourceQuota{},
+			&model.LimitRange{},
+		})
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.Stream(ctx, feed, collection, h.resource)
+}
+
+//
+// Build the event resource.
+func (h EventHandler) resource(event base.Event) (r *base.EventResource, reported bool) {
+	r = &base.EventResource{}
+	reported = true
+	switch m := event.Model.(type) {
+	case *model.Namespace:
+		resource := &Namespace{}
+		resource.With(m)
+		resource.SelfLink = NamespaceHandler{Handler: h.Handler}.Link(h.Provider, m)
+		r.Collection = NamespaceCollection
+		r.Resource = resource.Content(h.Detail)
+	case *model.StorageClass:
+		resource := &StorageClass{}
+		resource.With(m)
+		resource.SelfLink = StorageClassHandler{Handler: h.Handler}.Link(h.Provider, m)
+		r.Collection = StorageClassCollection
+		r.Resource = resource.Content(h.Detail)
+	case *model.NetworkAttachmentDefinition:
+		resource := &NetworkAttachmentDefinition{}
+		resource.With(m)
+		resource.SelfLink = NetworkAttachmentDefinitionHandler{Handler: h.Handler}.Link(h.Provider, m)
+		r.Collection = NetworkAttachmentDefinitionCollection
+		r.Resource = resource.Content(h.Detail)
+	case *model.VM:
+		resource := &VM{}
+		resource.With(m)
+		resource.SelfLink = VMHandler{Handler: h.Handler}.Link(h.Provider, m)
+		r.Collection = VMCollection
+		r.Resource = resource.Content(h.Detail)
+	case *model.VMI:
+		resource := &VMI{}
+		resource.With(m)
+		resource.SelfLink = VMIHandler{Handler: h.Handler}.Link(h.Provider, m)
+		r.Collection = VMICollection
+		r.Resource = resource.Content(h.Detail)
+	case *model.PersistentVolumeClaim:
+		resource := &PersistentVolumeClaim{}
+		resource.With(m)
+		resource.SelfLink = PersistentVolumeClaimHandler{Handler: h.Handler}.Link(h.Provider, m)
+		r.Collection = PersistentVolumeClaimCollection
+		r.Resource = resource.Content(h.Detail)
+	case *model.PersistentVolume:
+		resource := &PersistentVolume{}
+		resource.With(m)
+		resource.SelfLink = PersistentVolumeHandler{Handler: h.Handler}.Link(h.Provider, m)
+		r.Collection = PersistentVolumeCollection
+		r.Resource = resource.Content(h.Detail)
+	case *model.DataVolume:
+		resource := &DataVolume{}
+		resource.With(m)
+		resource.SelfLink = DataVolumeHandler{Handler: h.Handler}.Link(h.Provider, m)
+		r.Collection = DataVolumeCollection
+		r.Resource = resource.Content(h.Detail)
+	case *model.Node:
+		resource := &Node{}
+		resource.With(m)
+		resource.SelfLink = NodeHandler{Handler: h.Handler}.Link(h.Provider, m)
+		r.Collection = NodeCollection
+		r.Resource = resource.Content(h.Detail)
+	case *model.ResourceQuota:
+		resource := &ResourceQuota{}
+		resource.With(m)
+		resource.SelfLink = ResourceQuotaHandler{Handler: h.Handler}.Link(h.Provider, m)
+		r.Collection = ResourceQuotaCollection
+		r.Resource = resource.Content(h.Detail)
+	case *model.LimitRange:
+		resource := &LimitRange{}
+		resource.With(m)
+		resource.SelfLink = LimitRangeHandler{Handler: h.Handler}.Link(h.Provider, m)
+		r.Collection = LimitRangeCollection
+		r.Resource = resource.Content(h.Detail)
+	default:
+		reported = false
+	}
+
+	return
+}
@@ -0,0 +1,159 @@
+package ocp
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/ocp"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	core "k8s.io/api/core/v1"
+	"net/http"
+)
+
+//
+// Routes.
+const (
+	LimitRangeParam    = "limitrange"
+	LimitRangesRoot    = NamespaceRoot + "/limitranges"
+	AllLimitRangesRoot = ProviderRoot + "/limitranges"
+	LimitRangeRoot     = LimitRangesRoot + "/:" + LimitRangeParam
+)
+
+//
+// LimitRange handler.
+type LimitRangeHandler struct {
+	Handler
+}
+
+//
+// Add routes to the `gin` router.
+func (h *LimitRangeHandler) AddRoutes(e *gin.Engine) {
+	e.GET(AllLimitRangesRoot, h.ListAll)
+	e.GET(LimitRangesRoot, h.List)
+	e.GET(LimitRangesRoot+"/", h.List)
+	e.GET(LimitRangeRoot, h.Get)
+}
+
+//
+// List resources in a REST collection (all namespaces).
+func (h LimitRangeHandler) ListAll(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	db := h.Reconciler.DB()
+	list := []model.LimitRange{}
+	err := db.List(&list, h.ListOptions(ctx))
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	content := []interface{}{}
+	for _, m := range list {
+		r := &LimitRange{}
+		r.With(&m)
+		r.SelfLink = h.Link(h.Provider, &m)
+		content = append(content, r.Content(h.Detail))
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// List resources in a REST collection.
+func (h LimitRangeHandler) List(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	db := h.Reconciler.DB()
+	list := []model.LimitRange{}
+	err := db.List(&list, h.ListOptions(ctx))
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	content := []interface{}{}
+	for _, m := range list {
+		r := &LimitRange{}
+		r.With(&m)
+		r.SelfLink = h.Link(h.Provider, &m)
+		content = append(content, r.Content(h.Detail))
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// Get a specific REST resource.
+func (h LimitRangeHandler) Get(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	m := &model.LimitRange{
+		Base: model.Base{
+			Namespace: ctx.Param(Ns2Param),
+			Name:      ctx.Param(LimitRangeParam),
+		},
+	}
+	db := h.Reconciler.DB()
+	err := db.Get(m)
+	if errors.Is(err, model.NotFound) {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	r := &LimitRange{}
+	r.With(m)
+	r.SelfLink = h.Link(h.Provider, m)
+	content := r.Content(true)
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// Build self link (URI).
+func (h LimitRangeHandler) Link(p *api.Provider, m *model.LimitRange) string {
+	return h.Handler.Link(
+		LimitRangeRoot,
+		base.Params{
+			base.NsParam:       p.Namespace,
+			base.ProviderParam: p.Name,
+			Ns2Param:           m.Namespace,
+			LimitRangeParam:    m.Name,
+		})
+}
+
+//
+// REST Resource.
+type LimitRange struct {
+	Resource
+	Object core.LimitRange `json:"object"`
+}
+
+//
+// Set fields with the specified object.
+func (r *LimitRange) With(m *model.LimitRange) {
+	r.Resource.With(&m.Base)
+	r.Object = m.Object
+}
+
+//
+// As content.
+func (r *LimitRange) Content(detail bool) interface{} {
+	if !detail {
+		return r.Resource
+	}
+
+	return r
+}
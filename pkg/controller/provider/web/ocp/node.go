@@ -0,0 +1,128 @@
+package ocp
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/ocp"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	core "k8s.io/api/core/v1"
+	"net/http"
+)
+
+//
+// Routes.
+const (
+	NodeParam = "node"
+	NodesRoot = ProviderRoot + "/nodes"
+	NodeRoot  = NodesRoot + "/:" + NodeParam
+)
+
+//
+// Node handler.
+type NodeHandler struct {
+	Handler
+}
+
+//
+// Add routes to the `gin` router.
+func (h *NodeHandler) AddRoutes(e *gin.Engine) {
+	e.GET(NodesRoot, h.List)
+	e.GET(NodesRoot+"/", h.List)
+	e.GET(NodeRoot, h.Get)
+}
+
+//
+// List resources in a REST collection.
+func (h NodeHandler) List(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	db := h.Reconciler.DB()
+	list := []model.Node{}
+	err := db.List(&list, h.ListOptions(ctx))
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	content := []interface{}{}
+	for _, m := range list {
+		r := &Node{}
+		r.With(&m)
+		r.SelfLink = h.Link(h.Provider, &m)
+		content = append(content, r.Content(h.Detail))
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// Get a specific REST resource.
+func (h NodeHandler) Get(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	m := &model.Node{
+		Base: model.Base{
+			Name: ctx.Param(NodeParam),
+		},
+	}
+	db := h.Reconciler.DB()
+	err := db.Get(m)
+	if errors.Is(err, model.NotFound) {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	r := &Node{}
+	r.With(m)
+	r.SelfLink = h.Link(h.Provider, m)
+	content := r.Content(true)
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// Build self link (URI).
+func (h NodeHandler) Link(p *api.Provider, m *model.Node) string {
+	return h.Handler.Link(
+		NodeRoot,
+		base.Params{
+			base.NsParam:       p.Namespace,
+			base.ProviderParam: p.Name,
+			NodeParam:          m.Name,
+		})
+}
+
+//
+// REST Resource.
+type Node struct {
+	Resource
+	Object core.Node `json:"object"`
+}
+
+//
+// Set fields with the specified object.
+func (r *Node) With(m *model.Node) {
+	r.Resource.With(&m.Base)
+	r.Object = m.Object
+}
+
+//
+// As content.
+func (r *Node) Content(detail bool) interface{} {
+	if !detail {
+		return r.Resource
+	}
+
+	return r
+}
@@ -118,15 +118,28 @@ func (h *ProviderHandler) ListContent(ctx *gin.Context) (content []interface{},
 
 //
 // Add counts.
-func (h ProviderHandler) AddCount(r *Provider) error {
+func (h ProviderHandler) AddCount(r *Provider) (err error) {
 	if !h.Detail {
-		return nil
+		return
+	}
+	db := h.Reconciler.DB()
+	r.VMCount, err = db.Count(&model.VM{}, nil)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.NetworkCount, err = db.Count(&model.NetworkAttachmentDefinition{}, nil)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.NamespaceCount, err = db.Count(&model.Namespace{}, nil)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
 	}
 
-	//
-	// TODO:
-
-	return nil
+	return
 }
 
 //
@@ -0,0 +1,128 @@
+package ocp
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/ocp"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	core "k8s.io/api/core/v1"
+	"net/http"
+)
+
+//
+// Routes.
+const (
+	PersistentVolumeParam = "pv"
+	PersistentVolumesRoot = ProviderRoot + "/persistentvolumes"
+	PersistentVolumeRoot  = PersistentVolumesRoot + "/:" + PersistentVolumeParam
+)
+
+//
+// PersistentVolume handler.
+type PersistentVolumeHandler struct {
+	Handler
+}
+
+//
+// Add routes to the `gin` router.
+func (h *PersistentVolumeHandler) AddRoutes(e *gin.Engine) {
+	e.GET(PersistentVolumesRoot, h.List)
+	e.GET(PersistentVolumesRoot+"/", h.List)
+	e.GET(PersistentVolumeRoot, h.Get)
+}
+
+//
+// List resources in a REST collection.
+func (h PersistentVolumeHandler) List(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	db := h.Reconciler.DB()
+	list := []model.PersistentVolume{}
+	err := db.List(&list, h.ListOptions(ctx))
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	content := []interface{}{}
+	for _, m := range list {
+		r := &PersistentVolume{}
+		r.With(&m)
+		r.SelfLink = h.Link(h.Provider, &m)
+		content = append(content, r.Content(h.Detail))
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// Get a specific REST resource.
+func (h PersistentVolumeHandler) Get(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	m := &model.PersistentVolume{
+		Base: model.Base{
+			Name: ctx.Param(PersistentVolumeParam),
+		},
+	}
+	db := h.Reconciler.DB()
+	err := db.Get(m)
+	if errors.Is(err, model.NotFound) {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	r := &PersistentVolume{}
+	r.With(m)
+	r.SelfLink = h.Link(h.Provider, m)
+	content := r.Content(true)
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// Build self link (URI).
+func (h PersistentVolumeHandler) Link(p *api.Provider, m *model.PersistentVolume) string {
+	return h.Handler.Link(
+		PersistentVolumeRoot,
+		base.Params{
+			base.NsParam:          p.Namespace,
+			base.ProviderParam:    p.Name,
+			PersistentVolumeParam: m.Name,
+		})
+}
+
+//
+// REST Resource.
+type PersistentVolume struct {
+	Resource
+	Object core.PersistentVolume `json:"object"`
+}
+
+//
+// Set fields with the specified object.
+func (r *PersistentVolume) With(m *model.PersistentVolume) {
+	r.Resource.With(&m.Base)
+	r.Object = m.Object
+}
+
+//
+// As content.
+func (r *PersistentVolume) Content(detail bool) interface{} {
+	if !detail {
+		return r.Resource
+	}
+
+	return r
+}
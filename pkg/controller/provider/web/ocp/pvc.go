@@ -0,0 +1,159 @@
+package ocp
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/ocp"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	core "k8s.io/api/core/v1"
+	"net/http"
+)
+
+//
+// Routes.
+const (
+	PersistentVolumeClaimParam    = "pvc"
+	PersistentVolumeClaimsRoot    = NamespaceRoot + "/persistentvolumeclaims"
+	AllPersistentVolumeClaimsRoot = ProviderRoot + "/persistentvolumeclaims"
+	PersistentVolumeClaimRoot     = PersistentVolumeClaimsRoot + "/:" + PersistentVolumeClaimParam
+)
+
+//
+// PersistentVolumeClaim handler.
+type PersistentVolumeClaimHandler struct {
+	Handler
+}
+
+//
+// Add routes to the `gin` router.
+func (h *PersistentVolumeClaimHandler) AddRoutes(e *gin.Engine) {
+	e.GET(AllPersistentVolumeClaimsRoot, h.ListAll)
+	e.GET(PersistentVolumeClaimsRoot, h.List)
+	e.GET(PersistentVolumeClaimsRoot+"/", h.List)
+	e.GET(PersistentVolumeClaimRoot, h.Get)
+}
+
+//
+// List resources in a REST collection (all namespaces).
+func (h PersistentVolumeClaimHandler) ListAll(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	db := h.Reconciler.DB()
+	list := []model.PersistentVolumeClaim{}
+	err := db.List(&list, h.ListOptions(ctx))
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	content := []interface{}{}
+	for _, m := range list {
+		r := &PersistentVolumeClaim{}
+		r.With(&m)
+		r.SelfLink = h.Link(h.Provider, &m)
+		content = append(content, r.Content(h.Detail))
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// List resources in a REST collection.
+func (h PersistentVolumeClaimHandler) List(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	db := h.Reconciler.DB()
+	list := []model.PersistentVolumeClaim{}
+	err := db.List(&list, h.ListOptions(ctx))
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	content := []interface{}{}
+	for _, m := range list {
+		r := &PersistentVolumeClaim{}
+		r.With(&m)
+		r.SelfLink = h.Link(h.Provider, &m)
+		content = append(content, r.Content(h.Detail))
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// Get a specific REST resource.
+func (h PersistentVolumeClaimHandler) Get(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	m := &model.PersistentVolumeClaim{
+		Base: model.Base{
+			Namespace: ctx.Param(Ns2Param),
+			Name:      ctx.Param(PersistentVolumeClaimParam),
+		},
+	}
+	db := h.Reconciler.DB()
+	err := db.Get(m)
+	if errors.Is(err, model.NotFound) {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	r := &PersistentVolumeClaim{}
+	r.With(m)
+	r.SelfLink = h.Link(h.Provider, m)
+	content := r.Content(true)
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// Build self link (URI).
+func (h PersistentVolumeClaimHandler) Link(p *api.Provider, m *model.PersistentVolumeClaim) string {
+	return h.Handler.Link(
+		PersistentVolumeClaimRoot,
+		base.Params{
+			base.NsParam:               p.Namespace,
+			base.ProviderParam:         p.Name,
+			Ns2Param:                   m.Namespace,
+			PersistentVolumeClaimParam: m.Name,
+		})
+}
+
+//
+// REST Resource.
+type PersistentVolumeClaim struct {
+	Resource
+	Object core.PersistentVolumeClaim `json:"object"`
+}
+
+//
+// Set fields with the specified object.
+func (r *PersistentVolumeClaim) With(m *model.PersistentVolumeClaim) {
+	r.Resource.With(&m.Base)
+	r.Object = m.Object
+}
+
+//
+// As content.
+func (r *PersistentVolumeClaim) Content(detail bool) interface{} {
+	if !detail {
+		return r.Resource
+	}
+
+	return r
+}
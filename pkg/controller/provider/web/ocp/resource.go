@@ -2,6 +2,9 @@ package ocp
 
 import (
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/ocp"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	"path"
+	"strings"
 )
 
 //
@@ -27,3 +30,19 @@ func (r *Resource) With(m *model.Base) {
 	r.Namespace = m.Namespace
 	r.Name = m.Name
 }
+
+//
+// Match the ref.
+// The ID may be either the UID or the `namespace/name`.
+// The Name may be either the name or the `namespace/name`.
+func (r *Resource) Match(ref base.Ref) bool {
+	qualified := path.Join(r.Namespace, r.Name)
+	if ref.ID != "" {
+		return ref.ID == r.UID || ref.ID == qualified
+	}
+	if strings.Contains(ref.Name, "/") {
+		return ref.Name == qualified
+	}
+
+	return ref.Name == r.Name
+}
@@ -0,0 +1,159 @@
+package ocp
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/ocp"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	core "k8s.io/api/core/v1"
+	"net/http"
+)
+
+//
+// Routes.
+const (
+	ResourceQuotaParam    = "quota"
+	ResourceQuotasRoot    = NamespaceRoot + "/resourcequotas"
+	AllResourceQuotasRoot = ProviderRoot + "/resourcequotas"
+	ResourceQuotaRoot     = ResourceQuotasRoot + "/:" + ResourceQuotaParam
+)
+
+//
+// ResourceQuota handler.
+type ResourceQuotaHandler struct {
+	Handler
+}
+
+//
+// Add routes to the `gin` router.
+func (h *ResourceQuotaHandler) AddRoutes(e *gin.Engine) {
+	e.GET(AllResourceQuotasRoot, h.ListAll)
+	e.GET(ResourceQuotasRoot, h.List)
+	e.GET(ResourceQuotasRoot+"/", h.List)
+	e.GET(ResourceQuotaRoot, h.Get)
+}
+
+//
+// List resources in a REST collection (all namespaces).
+func (h ResourceQuotaHandler) ListAll(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	db := h.Reconciler.DB()
+	list := []model.ResourceQuota{}
+	err := db.List(&list, h.ListOptions(ctx))
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	content := []interface{}{}
+	for _, m := range list {
+		r := &ResourceQuota{}
+		r.With(&m)
+		r.SelfLink = h.Link(h.Provider, &m)
+		content = append(content, r.Content(h.Detail))
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// List resources in a REST collection.
+func (h ResourceQuotaHandler) List(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	db := h.Reconciler.DB()
+	list := []model.ResourceQuota{}
+	err := db.List(&list, h.ListOptions(ctx))
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	content := []interface{}{}
+	for _, m := range list {
+		r := &ResourceQuota{}
+		r.With(&m)
+		r.SelfLink = h.Link(h.Provider, &m)
+		content = append(content, r.Content(h.Detail))
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// Get a specific REST resource.
+func (h ResourceQuotaHandler) Get(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	m := &model.ResourceQuota{
+		Base: model.Base{
+			Namespace: ctx.Param(Ns2Param),
+			Name:      ctx.Param(ResourceQuotaParam),
+		},
+	}
+	db := h.Reconciler.DB()
+	err := db.Get(m)
+	if errors.Is(err, model.NotFound) {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	r := &ResourceQuota{}
+	r.With(m)
+	r.SelfLink = h.Link(h.Provider, m)
+	content := r.Content(true)
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// Build self link (URI).
+func (h ResourceQuotaHandler) Link(p *api.Provider, m *model.ResourceQuota) string {
+	return h.Handler.Link(
+		ResourceQuotaRoot,
+		base.Params{
+			base.NsParam:       p.Namespace,
+			base.ProviderParam: p.Name,
+			Ns2Param:           m.Namespace,
+			ResourceQuotaParam: m.Name,
+		})
+}
+
+//
+// REST Resource.
+type ResourceQuota struct {
+	Resource
+	Object core.ResourceQuota `json:"object"`
+}
+
+//
+// Set fields with the specified object.
+func (r *ResourceQuota) With(m *model.ResourceQuota) {
+	r.Resource.With(&m.Base)
+	r.Object = m.Object
+}
+
+//
+// As content.
+func (r *ResourceQuota) Content(detail bool) interface{} {
+	if !detail {
+		return r.Resource
+	}
+
+	return r
+}
@@ -0,0 +1,488 @@
+package web
+
+import (
+	"encoding"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	"net/http"
+	pathlib "path"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+//
+// Routes.
+const (
+	OpenAPIRoot = "/openapi.json"
+)
+
+//
+// OpenAPI (specification) handler.
+// The specification is generated using the routes registered
+// with the router and the (route) documentation. The paths are
+// relative to the versioned (server) root.
+type OpenAPIHandler struct {
+	// Route documentation.
+	Docs base.Docs
+	// Router.
+	router *gin.Engine
+	// Generated specification.
+	spec *OpenAPI
+	// Generated once.
+	once sync.Once
+}
+
+//
+// Add routes to the `gin` router.
+func (h *OpenAPIHandler) AddRoutes(e *gin.Engine) {
+	e.GET(OpenAPIRoot, h.Get)
+	h.router = e
+}
+
+//
+// List resources in a REST collection.
+// Not supported.
+func (h *OpenAPIHandler) List(ctx *gin.Context) {
+	ctx.Status(http.StatusMethodNotAllowed)
+}
+
+//
+// Get the specification.
+func (h *OpenAPIHandler) Get(ctx *gin.Context) {
+	h.once.Do(func() {
+		h.spec = h.build()
+	})
+
+	ctx.JSON(http.StatusOK, h.spec)
+}
+
+//
+// Build the specification.
+func (h *OpenAPIHandler) build() (spec *OpenAPI) {
+	spec = &OpenAPI{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:   "Forklift Inventory",
+			Version: base.Version,
+		},
+		Servers: []Server{
+			{URL: base.VersionRoot},
+		},
+		Paths: map[string]PathItem{},
+		Components: Components{
+			Schemas: map[string]*Schema{},
+		},
+	}
+	schemas := Schemas{
+		components: spec.Components.Schemas,
+		names:      map[reflect.Type]string{},
+	}
+	for _, route := range h.router.Routes() {
+		path := route.Path
+		if !h.documented(path) {
+			continue
+		}
+		doc := h.Docs[path]
+		op := &Operation{
+			Summary:    doc.Summary,
+			Tags:       h.tags(path),
+			Parameters: h.parameters(path, doc),
+			Responses: map[string]Response{
+				"400": {Description: "Bad request."},
+				"404": {Description: "Not found."},
+				"500": {Description: "Internal error."},
+			},
+		}
+		mediaType := doc.MediaType
+		if mediaType == "" {
+			mediaType = gin.MIMEJSON
+		}
+		ok := Response{
+			Description: "OK",
+			Content: map[string]MediaType{
+				mediaType: {
+					Schema: schemas.Of(doc.Resource),
+				},
+			},
+		}
+		op.Responses["200"] = ok
+		path = h.template(path)
+		item, found := spec.Paths[path]
+		if !found {
+			item = PathItem{}
+			spec.Paths[path] = item
+		}
+		item[strings.ToLower(route.Method)] = op
+	}
+
+	return
+}
+
+//
+// The route is included in the specification.
+// Versioned routes are served using the (unversioned) route.
+// Routes with a trailing slash are redundant.
+func (h *OpenAPIHandler) documented(path string) bool {
+	if path == OpenAPIRoot || h.versioned(path) {
+		return false
+	}
+	if len(path) > 1 && strings.HasSuffix(path, "/") {
+		return false
+	}
+
+	return true
+}
+
+//
+// The path has the version prefix.
+func (h *OpenAPIHandler) versioned(path string) bool {
+	return path == base.VersionRoot ||
+		strings.HasPrefix(path, base.VersionRoot+"/")
+}
+
+//
+// Operation tags.
+// The provider type for provider routes.
+func (h *OpenAPIHandler) tags(path string) (tags []string) {
+	root := base.ProvidersRoot + "/"
+	if !strings.HasPrefix(path, root) {
+		return
+	}
+	part := strings.SplitN(path[len(root):], "/", 2)
+	if !strings.HasPrefix(part[0], ":") {
+		tags = append(tags, part[0])
+	}
+
+	return
+}
+
+//
+// Path (template) and query parameters.
+func (h *OpenAPIHandler) parameters(path string, doc base.Doc) (params []Parameter) {
+	for _, part := range strings.Split(path, "/") {
+		if strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") {
+			params = append(
+				params,
+				Parameter{
+					Name:     part[1:],
+					In:       "path",
+					Required: true,
+					Schema:   &Schema{Type: "string"},
+				})
+		}
+	}
+	for _, p := range doc.Params {
+		schema := &Schema{Type: p.Type}
+		if schema.Type == "" {
+			schema.Type = "string"
+		}
+		if p.Repeated {
+			schema = &Schema{
+				Type:  "array",
+				Items: schema,
+			}
+		}
+		params = append(
+			params,
+			Parameter{
+				Name:        p.Name,
+				In:          "query",
+				Description: p.Description,
+				Schema:      schema,
+			})
+	}
+
+	return
+}
+
+//
+// Build the OpenAPI path template.
+// Example: /vms/:vm => /vms/{vm}.
+func (h *OpenAPIHandler) template(path string) string {
+	part := strings.Split(path, "/")
+	for i := range part {
+		if strings.HasPrefix(part[i], ":") || strings.HasPrefix(part[i], "*") {
+			part[i] = "{" + part[i][1:] + "}"
+		}
+	}
+
+	return strings.Join(part, "/")
+}
+
+//
+// Schemas (components) built using reflection.
+// Named (struct) types are added to the components and
+// referenced. Fields are described using the `json` tags.
+type Schemas struct {
+	// Component schemas (by name).
+	components map[string]*Schema
+	// Component names (by type).
+	names map[reflect.Type]string
+}
+
+//
+// Schema for the object.
+// Nil object is (any) value.
+func (r *Schemas) Of(object interface{}) *Schema {
+	if object == nil {
+		return &Schema{}
+	}
+
+	return r.schema(reflect.TypeOf(object))
+}
+
+//
+// Build the schema for the type.
+func (r *Schemas) schema(t reflect.Type) (schema *Schema) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		schema = &Schema{Type: "string", Format: "date-time"}
+		return
+	}
+	if kind, format, found := r.openAPIType(t); found {
+		schema = &Schema{Type: kind, Format: format}
+		return
+	}
+	pt := reflect.PtrTo(t)
+	if pt.Implements(textMarshaler) {
+		schema = &Schema{Type: "string"}
+		return
+	}
+	if pt.Implements(jsonMarshaler) {
+		schema = &Schema{}
+		return
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		schema = &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		schema = &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		schema = &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		schema = &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		schema = &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		schema = &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			schema = &Schema{Type: "string", Format: "byte"}
+			break
+		}
+		schema = &Schema{
+			Type:  "array",
+			Items: r.schema(t.Elem()),
+		}
+	case reflect.Map:
+		schema = &Schema{
+			Type:                 "object",
+			AdditionalProperties: r.schema(t.Elem()),
+		}
+	case reflect.Struct:
+		if t.Name() == "" {
+			schema = r.object(t)
+			break
+		}
+		schema = &Schema{Ref: r.component(t)}
+	default:
+		schema = &Schema{}
+	}
+
+	return
+}
+
+//
+// Add the (named) type to the components.
+// Returns the reference.
+func (r *Schemas) component(t reflect.Type) (ref string) {
+	name, found := r.names[t]
+	if !found {
+		name = r.name(t)
+		r.names[t] = name
+		r.components[name] = &Schema{}
+		*r.components[name] = *r.object(t)
+	}
+
+	ref = "#/components/schemas/" + name
+	return
+}
+
+//
+// Component name.
+// Qualified by the package (parent/name) and qualified
+// by the full package path when not unique.
+func (r *Schemas) name(t reflect.Type) (name string) {
+	pkg := t.PkgPath()
+	parent, last := pathlib.Split(pkg)
+	name = strings.Join(
+		[]string{
+			pathlib.Base(parent),
+			last,
+			t.Name(),
+		},
+		".")
+	if _, found := r.components[name]; found {
+		name = strings.ReplaceAll(pkg, "/", ".") + "." + t.Name()
+	}
+
+	return
+}
+
+//
+// Build the object schema.
+// Embedded (anonymous) structs and those with the `inline`
+// option are flattened.
+func (r *Schemas) object(t reflect.Type) (schema *Schema) {
+	schema = &Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{},
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		part := strings.Split(tag, ",")
+		name := part[0]
+		inline := false
+		for _, option := range part[1:] {
+			if option == "inline" {
+				inline = true
+			}
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if (f.Anonymous && name == "" || inline) && ft.Kind() == reflect.Struct {
+			embedded := r.object(ft)
+			for name, property := range embedded.Properties {
+				schema.Properties[name] = property
+			}
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		schema.Properties[name] = r.schema(f.Type)
+	}
+
+	return
+}
+
+//
+// Type and format reported by the (k8s) type.
+func (r *Schemas) openAPIType(t reflect.Type) (kind, format string, found bool) {
+	typed, found := reflect.New(t).Interface().(openAPITyped)
+	if !found {
+		return
+	}
+	kinds := typed.OpenAPISchemaType()
+	if len(kinds) > 0 {
+		kind = kinds[0]
+	}
+	format = typed.OpenAPISchemaFormat()
+
+	return
+}
+
+//
+// Types (k8s) that report the OpenAPI type and format.
+type openAPITyped interface {
+	OpenAPISchemaType() []string
+	OpenAPISchemaFormat() string
+}
+
+//
+// Marshaler types.
+var (
+	jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+//
+// OpenAPI (v3) specification.
+type OpenAPI struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Servers    []Server            `json:"servers"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+//
+// API info.
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+//
+// API server.
+type Server struct {
+	URL string `json:"url"`
+}
+
+//
+// Path operations (by method).
+type PathItem map[string]*Operation
+
+//
+// Operation.
+type Operation struct {
+	Summary    string              `json:"summary,omitempty"`
+	Tags       []string            `json:"tags,omitempty"`
+	Parameters []Parameter         `json:"parameters,omitempty"`
+	Responses  map[string]Response `json:"responses"`
+}
+
+//
+// Operation parameter.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+}
+
+//
+// Operation response.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+//
+// Response media type.
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+//
+// Components.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+//
+// Schema.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
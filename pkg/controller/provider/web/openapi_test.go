@@ -0,0 +1,41 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	libcontainer "github.com/konveyor/controller/pkg/inventory/container"
+	"github.com/onsi/gomega"
+	"testing"
+)
+
+func TestDocs(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	gin.SetMode(gin.TestMode)
+	e := gin.New()
+	for _, h := range All(libcontainer.New()) {
+		h.AddRoutes(e)
+	}
+	docs := Docs()
+	h := &OpenAPIHandler{Docs: docs}
+	undocumented := []string{}
+	for _, route := range e.Routes() {
+		if !h.documented(route.Path) {
+			continue
+		}
+		if docs[route.Path].Summary == "" {
+			undocumented = append(undocumented, route.Method+" "+route.Path)
+		}
+	}
+	g.Expect(undocumented).To(gomega.BeEmpty())
+	// Documented routes not registered.
+	registered := map[string]bool{}
+	for _, route := range e.Routes() {
+		registered[route.Path] = true
+	}
+	unregistered := []string{}
+	for path := range docs {
+		if !registered[path] {
+			unregistered = append(unregistered, path)
+		}
+	}
+	g.Expect(unregistered).To(gomega.BeEmpty())
+}
@@ -0,0 +1,44 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	"net/http"
+	"strings"
+)
+
+//
+// API version handler.
+// Serves the routes (registered by the other handlers) with
+// the version prefix. Must be the last handler added to the router.
+// Example: /v1/namespaces/:ns1/providers.
+type VersionHandler struct {
+}
+
+//
+// Add routes to the `gin` router.
+func (h *VersionHandler) AddRoutes(e *gin.Engine) {
+	for _, route := range e.Routes() {
+		if strings.HasPrefix(route.Path, base.VersionRoot+"/") {
+			continue
+		}
+		e.Handle(
+			route.Method,
+			base.VersionRoot+route.Path,
+			route.HandlerFunc)
+	}
+}
+
+//
+// List resources in a REST collection.
+// Not supported.
+func (h VersionHandler) List(ctx *gin.Context) {
+	ctx.Status(http.StatusMethodNotAllowed)
+}
+
+//
+// Get a specific REST resource.
+// Not supported.
+func (h VersionHandler) Get(ctx *gin.Context) {
+	ctx.Status(http.StatusMethodNotAllowed)
+}
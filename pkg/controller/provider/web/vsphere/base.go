@@ -12,6 +12,7 @@ import (
 const (
 	DetailParam = base.DetailParam
 	NameParam   = base.NameParam
+	TagParam    = "tag"
 )
 
 //
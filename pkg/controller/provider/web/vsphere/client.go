@@ -68,6 +68,13 @@ func (r *Resolver) Path(resource interface{}, id string) (path string, err error
 			&model.Network{
 				Base: model.Base{ID: id},
 			})
+	case *DVSwitch:
+		h := DVSwitchHandler{}
+		path = h.Link(
+			r.Provider,
+			&model.DVSwitch{
+				Base: model.Base{ID: id},
+			})
 	case *Datastore:
 		h := DatastoreHandler{}
 		path = h.Link(
@@ -82,6 +89,22 @@ func (r *Resolver) Path(resource interface{}, id string) (path string, err error
 			&model.VM{
 				Base: model.Base{ID: id},
 			})
+	case *ResourcePool:
+		h := ResourcePoolHandler{}
+		path = h.Link(
+			r.Provider,
+			&model.ResourcePool{
+				Base: model.Base{ID: id},
+			})
+	case *VApp:
+		h := VAppHandler{}
+		path = h.Link(
+			r.Provider,
+			&model.VApp{
+				ResourcePool: model.ResourcePool{
+					Base: model.Base{ID: id},
+				},
+			})
 	default:
 		err = liberr.Wrap(
 			base.ResourceNotResolvedError{
@@ -242,6 +265,13 @@ func (r *Finder) ByRef(resource interface{}, ref base.Ref) (err error) {
 			}
 			*resource.(*VM) = list[0]
 		}
+	case *ResourcePool, *VApp:
+		id := ref.ID
+		if id != "" {
+			err = r.Get(resource, id)
+			return
+		}
+		err = liberr.Wrap(NotFoundError{Ref: ref})
 	default:
 		err = liberr.Wrap(
 			ResourceNotResolvedError{
@@ -44,18 +44,26 @@ func (h ClusterHandler) List(ctx *gin.Context) {
 	}
 	db := h.Reconciler.DB()
 	list := []model.Cluster{}
-	err := db.List(&list, h.ListOptions(ctx))
+	options := h.ListOptions(ctx)
+	h.Query.Options(&options, &model.Cluster{})
+	err := db.List(&list, options)
 	if err != nil {
 		Log.Trace(err)
 		ctx.Status(http.StatusInternalServerError)
 		return
 	}
-	content := []interface{}{}
+	resources := []base.Content{}
 	for _, m := range list {
 		r := &Cluster{}
 		r.With(&m)
 		r.SelfLink = h.Link(h.Provider, &m)
-		content = append(content, r.Content(h.Detail))
+		resources = append(resources, r)
+	}
+	content, err := h.Query.Content(resources, h.Detail, &h.Page)
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusBadRequest)
+		return
 	}
 
 	ctx.JSON(http.StatusOK, content)
@@ -115,26 +123,32 @@ func (h ClusterHandler) Link(p *api.Provider, m *model.Cluster) string {
 // REST Resource.
 type Cluster struct {
 	Resource
-	Networks    []model.Ref `json:"networks"`
-	Datastores  []model.Ref `json:"datastores"`
-	DasEnabled  bool        `json:"dasEnabled"`
-	DasVms      []model.Ref `json:"DasVms"`
-	DrsEnabled  bool        `json:"drsEnabled"`
-	DrsBehavior string      `json:"drsBehavior"`
-	DrsVms      []model.Ref `json:"drsVms"`
+	ResourcePool model.Ref       `json:"resourcePool"`
+	Networks     []model.Ref     `json:"networks"`
+	Datastores   []model.Ref     `json:"datastores"`
+	DasEnabled   bool            `json:"dasEnabled"`
+	DasVms       []model.Ref     `json:"DasVms"`
+	DrsEnabled   bool            `json:"drsEnabled"`
+	DrsBehavior  string          `json:"drsBehavior"`
+	DrsVms       []model.Ref     `json:"drsVms"`
+	DrsRules     []model.DrsRule `json:"drsRules"`
+	VmGroups     []model.VmGroup `json:"vmGroups"`
 }
 
 //
 // Build the resource using the model.
 func (r *Cluster) With(m *model.Cluster) {
 	r.Resource.With(&m.Base)
+	r.ResourcePool = m.ResourcePool
 	r.DasEnabled = m.DasEnabled
 	r.DrsEnabled = m.DrsEnabled
 	r.DrsBehavior = m.DrsBehavior
 	r.Networks = m.Networks
 	r.Datastores = m.Datastores
 	r.DasVms = m.DasVms
-	r.DrsVms = m.DasVms
+	r.DrsVms = m.DrsVms
+	r.DrsRules = m.DrsRules
+	r.VmGroups = m.VmGroups
 }
 
 //
@@ -44,18 +44,26 @@ func (h DatacenterHandler) List(ctx *gin.Context) {
 	}
 	db := h.Reconciler.DB()
 	list := []model.Datacenter{}
-	err := db.List(&list, h.ListOptions(ctx))
+	options := h.ListOptions(ctx)
+	h.Query.Options(&options, &model.Datacenter{})
+	err := db.List(&list, options)
 	if err != nil {
 		Log.Trace(err)
 		ctx.Status(http.StatusInternalServerError)
 		return
 	}
-	content := []interface{}{}
+	resources := []base.Content{}
 	for _, m := range list {
 		r := &Datacenter{}
 		r.With(&m)
 		r.SelfLink = h.Link(h.Provider, &m)
-		content = append(content, r.Content(h.Detail))
+		resources = append(resources, r)
+	}
+	content, err := h.Query.Content(resources, h.Detail, &h.Page)
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusBadRequest)
+		return
 	}
 
 	ctx.JSON(http.StatusOK, content)
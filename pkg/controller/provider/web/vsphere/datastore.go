@@ -44,13 +44,15 @@ func (h DatastoreHandler) List(ctx *gin.Context) {
 	}
 	db := h.Reconciler.DB()
 	list := []model.Datastore{}
-	err := db.List(&list, h.ListOptions(ctx))
+	options := h.ListOptions(ctx)
+	h.Query.Options(&options, &model.Datastore{})
+	err := db.List(&list, options)
 	if err != nil {
 		Log.Trace(err)
 		ctx.Status(http.StatusInternalServerError)
 		return
 	}
-	content := []interface{}{}
+	resources := []base.Content{}
 	err = h.filter(ctx, &list)
 	if err != nil {
 		Log.Trace(err)
@@ -61,7 +63,13 @@ func (h DatastoreHandler) List(ctx *gin.Context) {
 		r := &Datastore{}
 		r.With(&m)
 		r.SelfLink = h.Link(h.Provider, &m)
-		content = append(content, r.Content(h.Detail))
+		resources = append(resources, r)
+	}
+	content, err := h.Query.Content(resources, h.Detail, &h.Page)
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusBadRequest)
+		return
 	}
 
 	ctx.JSON(http.StatusOK, content)
@@ -154,10 +162,12 @@ func (h DatastoreHandler) filter(ctx *gin.Context, list *[]model.Datastore) (err
 // REST Resource.
 type Datastore struct {
 	Resource
-	Type            string `json:"type"`
-	Capacity        int64  `json:"capacity"`
-	Free            int64  `json:"free"`
-	MaintenanceMode string `json:"maintenance"`
+	Type             string           `json:"type"`
+	Capacity         int64            `json:"capacity"`
+	Free             int64            `json:"free"`
+	MaintenanceMode  string           `json:"maintenance"`
+	Tags             []model.Tag      `json:"tags"`
+	CustomAttributes model.Attributes `json:"customAttributes"`
 }
 
 //
@@ -168,6 +178,8 @@ func (r *Datastore) With(m *model.Datastore) {
 	r.Capacity = m.Capacity
 	r.Free = m.Free
 	r.MaintenanceMode = m.MaintenanceMode
+	r.Tags = m.Tags
+	r.CustomAttributes = m.CustomAttributes
 }
 
 //
@@ -5,6 +5,7 @@ import (
 	libweb "github.com/konveyor/controller/pkg/inventory/web"
 	"github.com/konveyor/controller/pkg/logging"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	inventory "github.com/konveyor/forklift-controller/pkg/controller/provider/model"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
 )
 
@@ -62,15 +63,298 @@ func Handlers(container *container.Container) []libweb.RequestHandler {
 				base.Handler{Container: container},
 			},
 		},
+		&DVSwitchHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
 		&DatastoreHandler{
 			Handler: Handler{
 				base.Handler{Container: container},
 			},
 		},
+		&ResourcePoolHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&VAppHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
 		&VMHandler{
 			Handler: Handler{
 				base.Handler{Container: container},
 			},
 		},
+		&EventHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&ExportHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&GraphHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&WaveHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&HealthHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+	}
+}
+
+//
+// API documentation.
+func Docs() base.Docs {
+	collection := base.JoinParams(base.ListParams, base.QueryParams)
+	return base.Docs{
+		ProvidersRoot: {
+			Summary:  "List vSphere providers.",
+			Resource: []Provider{},
+			Params:   base.ListParams,
+		},
+		ProviderRoot: {
+			Summary:  "Get a vSphere provider.",
+			Resource: Provider{},
+		},
+		TreeHostRoot: {
+			Summary:  "Get the host (datacenter/cluster/host/vm) tree.",
+			Resource: TreeNode{},
+		},
+		TreeVmRoot: {
+			Summary:  "Get the VM (folder) tree.",
+			Resource: TreeNode{},
+		},
+		TreePoolRoot: {
+			Summary:  "Get the resource pool tree.",
+			Resource: TreeNode{},
+		},
+		TreeNetRoot: {
+			Summary:  "Get the network tree.",
+			Resource: TreeNode{},
+		},
+		FoldersRoot: {
+			Summary:  "List folders.",
+			Resource: []Folder{},
+			Params:   collection,
+		},
+		FolderRoot: {
+			Summary:  "Get a folder.",
+			Resource: Folder{},
+		},
+		DatacentersRoot: {
+			Summary:  "List datacenters.",
+			Resource: []Datacenter{},
+			Params:   collection,
+		},
+		DatacenterRoot: {
+			Summary:  "Get a datacenter.",
+			Resource: Datacenter{},
+		},
+		ClustersRoot: {
+			Summary:  "List clusters.",
+			Resource: []Cluster{},
+			Params:   collection,
+		},
+		ClusterRoot: {
+			Summary:  "Get a cluster.",
+			Resource: Cluster{},
+		},
+		HostsRoot: {
+			Summary:  "List hosts.",
+			Resource: []Host{},
+			Params:   collection,
+		},
+		HostRoot: {
+			Summary:  "Get a host.",
+			Resource: Host{},
+		},
+		NetworksRoot: {
+			Summary:  "List networks.",
+			Resource: []Network{},
+			Params:   collection,
+		},
+		NetworkRoot: {
+			Summary:  "Get a network.",
+			Resource: Network{},
+		},
+		DVSwitchesRoot: {
+			Summary:  "List distributed virtual switches.",
+			Resource: []DVSwitch{},
+			Params:   collection,
+		},
+		DVSwitchRoot: {
+			Summary:  "Get a distributed virtual switch.",
+			Resource: DVSwitch{},
+		},
+		DatastoresRoot: {
+			Summary:  "List datastores.",
+			Resource: []Datastore{},
+			Params:   collection,
+		},
+		DatastoreRoot: {
+			Summary:  "Get a datastore.",
+			Resource: Datastore{},
+		},
+		PoolsRoot: {
+			Summary:  "List resource pools.",
+			Resource: []ResourcePool{},
+			Params:   collection,
+		},
+		PoolRoot: {
+			Summary:  "Get a resource pool.",
+			Resource: ResourcePool{},
+		},
+		VAppsRoot: {
+			Summary:  "List vApps.",
+			Resource: []VApp{},
+			Params:   collection,
+		},
+		VAppRoot: {
+			Summary:  "Get a vApp.",
+			Resource: VApp{},
+		},
+		VMsRoot: {
+			Summary:  "List VMs.",
+			Resource: []VM{},
+			Params: append(
+				[]base.ParamDoc{
+					{
+						Name:        TagParam,
+						Description: "Match VMs with the tag. Format: <category>:<name> or <name>.",
+						Repeated:    true,
+					},
+				},
+				collection...),
+		},
+		VMRoot: {
+			Summary:  "Get a VM.",
+			Resource: VM{},
+		},
+		EventsRoot: {
+			Summary:   "Stream inventory events.",
+			Resource:  base.EventResource{},
+			Params:    base.EventParams,
+			MediaType: base.EventMediaType,
+		},
+		CollectionEventsRoot: {
+			Summary:   "Stream inventory events for a collection.",
+			Resource:  base.EventResource{},
+			Params:    base.EventParams,
+			MediaType: base.EventMediaType,
+		},
+		ExportRoot: {
+			Summary:  "List reports.",
+			Resource: []Report{},
+		},
+		ReportRoot: {
+			Summary: "Export a report (rows).",
+			Params: []base.ParamDoc{
+				{
+					Name:        FormatParam,
+					Description: "Format: json|csv. Default: json.",
+				},
+			},
+		},
+		GraphRoot: {
+			Summary:  "Get the VM dependency graph.",
+			Resource: Graph{},
+			Params: []base.ParamDoc{
+				{
+					Name:        RelationParam,
+					Description: "Comma separated relations. Example: network,datastore.",
+					Repeated:    true,
+				},
+			},
+		},
+		GraphVMRoot: {
+			Summary:  "Get the VM dependency graph for a VM.",
+			Resource: Graph{},
+			Params: []base.ParamDoc{
+				{
+					Name:        RelationParam,
+					Description: "Comma separated relations. Example: network,datastore.",
+					Repeated:    true,
+				},
+			},
+		},
+		WavesRoot: {
+			Summary:  "Propose migration waves.",
+			Resource: WaveProposal{},
+			Params: []base.ParamDoc{
+				{
+					Name:        MaxVMsParam,
+					Type:        "integer",
+					Description: "Maximum number of VMs in a wave.",
+				},
+				{
+					Name:        MaxTBParam,
+					Type:        "number",
+					Description: "Maximum disk capacity (TB) in a wave.",
+				},
+				{
+					Name:        DatastoreLimitParam,
+					Type:        "integer",
+					Description: "Maximum number of VMs per datastore.",
+				},
+				{
+					Name:        HostLimitParam,
+					Type:        "integer",
+					Description: "Maximum number of VMs per host.",
+				},
+				{
+					Name:        FolderParam,
+					Description: "Select VMs by folder path. Example: /dc1/vm/finance.",
+				},
+				{
+					Name:        TagParam,
+					Description: "Select VMs with the tag.",
+					Repeated:    true,
+				},
+				{
+					Name:        base.FilterParam,
+					Description: "Select VMs matched by the filter expression.",
+				},
+				{
+					Name:        PlanParam,
+					Description: "Draft plan name (prefix).",
+				},
+				{
+					Name:        TargetNamespaceParam,
+					Description: "Draft plan target namespace.",
+				},
+				{
+					Name:        DestinationParam,
+					Description: "Draft plan destination provider: <namespace>/<name> or <name>.",
+				},
+				{
+					Name:        NetworkMapParam,
+					Description: "Draft plan network map: <network ID>:pod or <network ID>:<namespace>/<name>.",
+					Repeated:    true,
+				},
+				{
+					Name:        StorageMapParam,
+					Description: "Draft plan storage map: <datastore ID>:<storage class>.",
+					Repeated:    true,
+				},
+			},
+		},
+		HealthRoot: {
+			Summary:  "Get the inventory health.",
+			Resource: inventory.Health{},
+		},
 	}
 }
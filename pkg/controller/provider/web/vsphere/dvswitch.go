@@ -0,0 +1,148 @@
+package vsphere
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	"net/http"
+)
+
+//
+// Routes.
+const (
+	DVSwitchParam      = "dvswitch"
+	DVSwitchCollection = "dvswitches"
+	DVSwitchesRoot     = ProviderRoot + "/" + DVSwitchCollection
+	DVSwitchRoot       = DVSwitchesRoot + "/:" + DVSwitchParam
+)
+
+//
+// Distributed virtual switch handler.
+type DVSwitchHandler struct {
+	Handler
+}
+
+//
+// Add routes to the `gin` router.
+func (h *DVSwitchHandler) AddRoutes(e *gin.Engine) {
+	e.GET(DVSwitchesRoot, h.List)
+	e.GET(DVSwitchesRoot+"/", h.List)
+	e.GET(DVSwitchRoot, h.Get)
+}
+
+//
+// List resources in a REST collection.
+func (h DVSwitchHandler) List(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	db := h.Reconciler.DB()
+	list := []model.DVSwitch{}
+	options := h.ListOptions(ctx)
+	h.Query.Options(&options, &model.DVSwitch{})
+	err := db.List(&list, options)
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	resources := []base.Content{}
+	for _, m := range list {
+		r := &DVSwitch{}
+		r.With(&m)
+		r.SelfLink = h.Link(h.Provider, &m)
+		resources = append(resources, r)
+	}
+	content, err := h.Query.Content(resources, h.Detail, &h.Page)
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// Get a specific REST resource.
+func (h DVSwitchHandler) Get(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	m := &model.DVSwitch{
+		Base: model.Base{
+			ID: ctx.Param(DVSwitchParam),
+		},
+	}
+	db := h.Reconciler.DB()
+	err := db.Get(m)
+	if errors.Is(err, model.NotFound) {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	r := &DVSwitch{}
+	r.With(m)
+	r.Path, err = m.Path(db)
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	r.SelfLink = h.Link(h.Provider, m)
+	content := r.Content(true)
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// Build self link (URI).
+func (h DVSwitchHandler) Link(p *api.Provider, m *model.DVSwitch) string {
+	return h.Handler.Link(
+		DVSwitchRoot,
+		base.Params{
+			base.NsParam:       p.Namespace,
+			base.ProviderParam: p.Name,
+			DVSwitchParam:      m.ID,
+		})
+}
+
+//
+// REST Resource.
+type DVSwitch struct {
+	Resource
+	Uplinks    []string        `json:"uplinks"`
+	MTU        int32           `json:"mtu"`
+	Hosts      []model.DVSHost `json:"hosts"`
+	PortGroups []model.Ref     `json:"portGroups"`
+}
+
+//
+// Build the resource using the model.
+func (r *DVSwitch) With(m *model.DVSwitch) {
+	r.Resource.With(&m.Base)
+	r.Uplinks = m.Uplinks
+	r.MTU = m.MTU
+	r.Hosts = m.Host
+	r.PortGroups = m.PortGroups
+}
+
+//
+// As content.
+func (r *DVSwitch) Content(detail bool) interface{} {
+	if !detail {
+		return r.Resource
+	}
+
+	return r
+}
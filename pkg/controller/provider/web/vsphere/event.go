@@ -0,0 +1,170 @@
+package vsphere
+
+import (
+	"github.com/gin-gonic/gin"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	"net/http"
+)
+
+//
+// Routes.
+const (
+	EventsRoot           = ProviderRoot + "/" + base.EventCollection
+	CollectionEventsRoot = EventsRoot + "/:" + base.CollectionParam
+)
+
+//
+// Event (change feed) handler.
+type EventHandler struct {
+	Handler
+}
+
+//
+// Add routes to the `gin` router.
+func (h *EventHandler) AddRoutes(e *gin.Engine) {
+	e.GET(EventsRoot, h.List)
+	e.GET(EventsRoot+"/", h.List)
+	e.GET(CollectionEventsRoot, h.Get)
+}
+
+//
+// Stream events for all collections.
+func (h EventHandler) List(ctx *gin.Context) {
+	h.Get(ctx)
+}
+
+//
+// Stream events.
+// The collection (optional) limits the events to
+// those for the specified collection. Example: `vms`.
+func (h EventHandler) Get(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	collection := ctx.Param(base.CollectionParam)
+	if collection != "" && !h.known(collection) {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	feed, err := base.FeedFor(h.Reconciler.DB(), h.models())
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.Stream(ctx, feed, collection, h.resource)
+}
+
+//
+// Watched models.
+func (h EventHandler) models() []interface{} {
+	return []interface{}{
+		&model.Folder{},
+		&model.Datacenter{},
+		&model.Cluster{},
+		&model.Host{},
+		&model.Network{},
+		&model.DVSwitch{},
+		&model.Datastore{},
+		&model.ResourcePool{},
+		&model.VApp{},
+		&model.VM{},
+	}
+}
+
+//
+// Known collection.
+func (h EventHandler) known(collection string) bool {
+	switch collection {
+	case FolderCollection,
+		DatacenterCollection,
+		ClusterCollection,
+		HostCollection,
+		NetworkCollection,
+		DVSwitchCollection,
+		DatastoreCollection,
+		PoolCollection,
+		VAppCollection,
+		VMCollection:
+		return true
+	}
+
+	return false
+}
+
+//
+// Build the event resource.
+func (h EventHandler) resource(event base.Event) (r *base.EventResource, reported bool) {
+	r = &base.EventResource{}
+	reported = true
+	switch m := event.Model.(type) {
+	case *model.Folder:
+		resource := &Folder{}
+		resource.With(m)
+		resource.SelfLink = FolderHandler{Handler: h.Handler}.Link(h.Provider, m)
+		r.Collection = FolderCollection
+		r.Resource = resource.Content(h.Detail)
+	case *model.Datacenter:
+		resource := &Datacenter{}
+		resource.With(m)
+		resource.SelfLink = DatacenterHandler{Handler: h.Handler}.Link(h.Provider, m)
+		r.Collection = DatacenterCollection
+		r.Resource = resource.Content(h.Detail)
+	case *model.Cluster:
+		resource := &Cluster{}
+		resource.With(m)
+		resource.SelfLink = ClusterHandler{Handler: h.Handler}.Link(h.Provider, m)
+		r.Collection = ClusterCollection
+		r.Resource = resource.Content(h.Detail)
+	case *model.Host:
+		resource := &Host{}
+		resource.With(m)
+		resource.SelfLink = HostHandler{Handler: h.Handler}.Link(h.Provider, m)
+		r.Collection = HostCollection
+		r.Resource = resource.Content(h.Detail)
+	case *model.Network:
+		resource := &Network{}
+		resource.With(m)
+		resource.SelfLink = NetworkHandler{Handler: h.Handler}.Link(h.Provider, m)
+		r.Collection = NetworkCollection
+		r.Resource = resource.Content(h.Detail)
+	case *model.DVSwitch:
+		resource := &DVSwitch{}
+		resource.With(m)
+		resource.SelfLink = DVSwitchHandler{Handler: h.Handler}.Link(h.Provider, m)
+		r.Collection = DVSwitchCollection
+		r.Resource = resource.Content(h.Detail)
+	case *model.Datastore:
+		resource := &Datastore{}
+		resource.With(m)
+		resource.SelfLink = DatastoreHandler{Handler: h.Handler}.Link(h.Provider, m)
+		r.Collection = DatastoreCollection
+		r.Resource = resource.Content(h.Detail)
+	case *model.ResourcePool:
+		resource := &ResourcePool{}
+		resource.With(m)
+		resource.SelfLink = ResourcePoolHandler{Handler: h.Handler}.Link(h.Provider, m)
+		r.Collection = PoolCollection
+		r.Resource = resource.Content(h.Detail)
+	case *model.VApp:
+		resource := &VApp{}
+		resource.With(m)
+		resource.SelfLink = VAppHandler{Handler: h.Handler}.Link(h.Provider, m)
+		r.Collection = VAppCollection
+		r.Resource = resource.Content(h.Detail)
+	case *model.VM:
+		resource := &VM{}
+		resource.With(m)
+		resource.SelfLink = VMHandler{Handler: h.Handler}.Link(h.Provider, m)
+		r.Collection = VMCollection
+		r.Resource = resource.Content(h.Detail)
+	default:
+		reported = false
+	}
+
+	return
+}
@@ -0,0 +1,561 @@
+package vsphere
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	liberr "github.com/konveyor/controller/pkg/error"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+//
+// Routes.
+const (
+	ReportParam      = "report"
+	FormatParam      = "format"
+	ExportCollection = "export"
+	ExportRoot       = ProviderRoot + "/" + ExportCollection
+	ReportRoot       = ExportRoot + "/:" + ReportParam
+)
+
+//
+// Report formats.
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+//
+// Reports.
+const (
+	VMReport        = "vms"
+	HostReport      = "hosts"
+	DatastoreReport = "datastores"
+)
+
+//
+// Separator for (flattened) list values.
+const ListSeparator = ";"
+
+//
+// Leading characters interpreted as a formula by
+// spreadsheet applications.
+const FormulaPrefix = "=+-@\t\r"
+
+//
+// VM power state.
+const PoweredOn = "poweredOn"
+
+//
+// Export (report) handler.
+type ExportHandler struct {
+	Handler
+	// Inventory (lookup) by kind and ID.
+	index map[string]map[string]interface{}
+}
+
+//
+// Add routes to the `gin` router.
+func (h *ExportHandler) AddRoutes(e *gin.Engine) {
+	e.GET(ExportRoot, h.List)
+	e.GET(ExportRoot+"/", h.List)
+	e.GET(ReportRoot, h.Get)
+}
+
+//
+// List available reports.
+func (h ExportHandler) List(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	content := []interface{}{}
+	for _, name := range []string{VMReport, HostReport, DatastoreReport} {
+		content = append(
+			content,
+			Report{
+				Name:     name,
+				SelfLink: h.Link(h.Provider, name),
+			})
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// Get (export) a report.
+// The format is specified using the `format` parameter.
+func (h ExportHandler) Get(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	format := strings.ToLower(ctx.Query(FormatParam))
+	switch format {
+	case "":
+		format = FormatJSON
+	case FormatJSON, FormatCSV:
+	default:
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+	err := h.buildIndex()
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	var rows interface{}
+	name := ctx.Param(ReportParam)
+	switch name {
+	case VMReport:
+		rows, err = h.vmRows()
+	case HostReport:
+		rows, err = h.hostRows()
+	case DatastoreReport:
+		rows, err = h.datastoreRows()
+	default:
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	switch format {
+	case FormatCSV:
+		ctx.Header(
+			"Content-Disposition",
+			fmt.Sprintf("attachment; filename=%s-%s.csv", h.Provider.Name, name))
+		ctx.Status(http.StatusOK)
+		ctx.Writer.Header().Set("Content-Type", "text/csv")
+		err = h.writeCSV(ctx, rows)
+		if err != nil {
+			Log.Trace(err)
+		}
+	default:
+		ctx.JSON(http.StatusOK, rows)
+	}
+}
+
+//
+// Build self link (URI).
+func (h ExportHandler) Link(p *api.Provider, report string) string {
+	return h.Handler.Link(
+		ReportRoot,
+		base.Params{
+			base.NsParam:       p.Namespace,
+			base.ProviderParam: p.Name,
+			ReportParam:        report,
+		})
+}
+
+//
+// Index the inventory used to resolve references.
+func (h *ExportHandler) buildIndex() (err error) {
+	db := h.Reconciler.DB()
+	h.index = map[string]map[string]interface{}{}
+	add := func(kind string, list interface{}) {
+		h.index[kind] = map[string]interface{}{}
+		lv := reflect.ValueOf(list).Elem()
+		for i := 0; i < lv.Len(); i++ {
+			m := lv.Index(i).Addr().Interface().(model.Model)
+			h.index[kind][m.Pk()] = m
+		}
+	}
+	clusters := []model.Cluster{}
+	err = db.List(&clusters, libmodel.ListOptions{Detail: 1})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	add(model.ClusterKind, &clusters)
+	hosts := []model.Host{}
+	err = db.List(&hosts, libmodel.ListOptions{Detail: 1})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	add(model.HostKind, &hosts)
+	datastores := []model.Datastore{}
+	err = db.List(&datastores, libmodel.ListOptions{Detail: 1})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	add(model.DsKind, &datastores)
+	networks := []model.Network{}
+	err = db.List(&networks, libmodel.ListOptions{Detail: 1})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	add(model.NetKind, &networks)
+	vms := []model.VM{}
+	err = db.List(&vms, libmodel.ListOptions{Detail: 1})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	add(model.VmKind, &vms)
+
+	return
+}
+
+//
+// Resolve the name of the referenced object.
+func (h *ExportHandler) name(ref model.Ref) string {
+	if m, found := h.index[ref.Kind][ref.ID]; found {
+		switch m := m.(type) {
+		case *model.Cluster:
+			return m.Name
+		case *model.Host:
+			return m.Name
+		case *model.Datastore:
+			return m.Name
+		case *model.Network:
+			return m.Name
+		case *model.VM:
+			return m.Name
+		}
+	}
+
+	return ref.ID
+}
+
+//
+// Cluster containing the host.
+func (h *ExportHandler) hostCluster(hostID string) (cluster *model.Cluster) {
+	for _, m := range h.index[model.ClusterKind] {
+		c := m.(*model.Cluster)
+		for _, ref := range c.Hosts {
+			if ref.ID == hostID {
+				cluster = c
+				return
+			}
+		}
+	}
+
+	return
+}
+
+//
+// Sorted (unique) names of the referenced objects.
+func (h *ExportHandler) names(refs []model.Ref) (list []string) {
+	set := map[string]bool{}
+	for _, ref := range refs {
+		set[h.name(ref)] = true
+	}
+	list = []string{}
+	for name := range set {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return
+}
+
+//
+// Build the VM report.
+func (h *ExportHandler) vmRows() (rows []VMRow, err error) {
+	db := h.Reconciler.DB()
+	rows = []VMRow{}
+	for _, m := range h.index[model.VmKind] {
+		vm := m.(*model.VM)
+		row := VMRow{
+			ID:          vm.ID,
+			Name:        vm.Name,
+			PowerState:  vm.PowerState,
+			GuestName:   vm.GuestName,
+			IpAddress:   vm.IpAddress,
+			CpuCount:    vm.CpuCount,
+			MemoryMB:    vm.MemoryMB,
+			DiskCount:   len(vm.Disks),
+			StorageUsed: vm.StorageUsed,
+			Firmware:    vm.Firmware,
+			Host:        h.name(vm.Host),
+			Networks:    h.names(vm.Networks),
+			Concerns:    []string{},
+		}
+		row.Path, err = vm.Path(db)
+		if err != nil {
+			Log.Trace(err)
+			err = nil
+		}
+		if cluster := h.hostCluster(vm.Host.ID); cluster != nil {
+			row.Cluster = cluster.Name
+		}
+		datastores := []model.Ref{}
+		for _, disk := range vm.Disks {
+			datastores = append(datastores, disk.Datastore)
+			row.DiskCapacity += disk.Capacity
+		}
+		row.Datastores = h.names(datastores)
+		for _, concern := range vm.Concerns {
+			row.Concerns = append(row.Concerns, concern.Name)
+			switch concern.Severity {
+			case model.Critical:
+				row.Critical++
+			case model.Warning:
+				row.Warning++
+			}
+		}
+		rows = append(rows, row)
+	}
+	sort.Slice(
+		rows,
+		func(i, j int) bool {
+			return rows[i].Path < rows[j].Path
+		})
+
+	return
+}
+
+//
+// Build the host report.
+func (h *ExportHandler) hostRows() (rows []HostRow, err error) {
+	db := h.Reconciler.DB()
+	rows = []HostRow{}
+	for _, m := range h.index[model.HostKind] {
+		host := m.(*model.Host)
+		row := HostRow{
+			ID:                host.ID,
+			Name:              host.Name,
+			ProductName:       host.ProductName,
+			ProductVersion:    host.ProductVersion,
+			InMaintenanceMode: host.InMaintenanceMode,
+			CpuSockets:        host.CpuSockets,
+			CpuCores:          host.CpuCores,
+			VmCount:           len(host.Vms),
+			Datastores:        h.names(host.Datastores),
+			Networks:          h.names(host.Networks),
+		}
+		row.Path, err = host.Path(db)
+		if err != nil {
+			Log.Trace(err)
+			err = nil
+		}
+		if cluster := h.hostCluster(host.ID); cluster != nil {
+			row.Cluster = cluster.Name
+		}
+		for _, ref := range host.Vms {
+			if m, found := h.index[model.VmKind][ref.ID]; found {
+				vm := m.(*model.VM)
+				row.CpuAllocated += int64(vm.CpuCount)
+				row.MemoryAllocatedMB += int64(vm.MemoryMB)
+				if vm.PowerState == PoweredOn {
+					row.PoweredOn++
+				}
+			}
+		}
+		rows = append(rows, row)
+	}
+	sort.Slice(
+		rows,
+		func(i, j int) bool {
+			return rows[i].Path < rows[j].Path
+		})
+
+	return
+}
+
+//
+// Build the datastore report.
+func (h *ExportHandler) datastoreRows() (rows []DatastoreRow, err error) {
+	db := h.Reconciler.DB()
+	rows = []DatastoreRow{}
+	vmCount := map[string]int{}
+	provisioned := map[string]int64{}
+	for _, m := range h.index[model.VmKind] {
+		vm := m.(*model.VM)
+		counted := map[string]bool{}
+		for _, disk := range vm.Disks {
+			provisioned[disk.Datastore.ID] += disk.Capacity
+			if !counted[disk.Datastore.ID] {
+				counted[disk.Datastore.ID] = true
+				vmCount[disk.Datastore.ID]++
+			}
+		}
+	}
+	hostCount := map[string]int{}
+	for _, m := range h.index[model.HostKind] {
+		for _, ref := range m.(*model.Host).Datastores {
+			hostCount[ref.ID]++
+		}
+	}
+	for _, m := range h.index[model.DsKind] {
+		ds := m.(*model.Datastore)
+		row := DatastoreRow{
+			ID:              ds.ID,
+			Name:            ds.Name,
+			Type:            ds.Type,
+			MaintenanceMode: ds.MaintenanceMode,
+			Capacity:        ds.Capacity,
+			Free:            ds.Free,
+			Used:            ds.Capacity - ds.Free,
+			Provisioned:     provisioned[ds.ID],
+			VmCount:         vmCount[ds.ID],
+			HostCount:       hostCount[ds.ID],
+		}
+		if ds.Capacity > 0 {
+			row.UsedPercent = float64(row.Used) * 100 / float64(ds.Capacity)
+		}
+		row.Path, err = ds.Path(db)
+		if err != nil {
+			Log.Trace(err)
+			err = nil
+		}
+		rows = append(rows, row)
+	}
+	sort.Slice(
+		rows,
+		func(i, j int) bool {
+			return rows[i].Path < rows[j].Path
+		})
+
+	return
+}
+
+//
+// Write the rows as CSV.
+// The header is built using the `json` tags and list
+// values are joined using the separator. String values
+// are escaped to prevent formula injection.
+func (h *ExportHandler) writeCSV(ctx *gin.Context, rows interface{}) (err error) {
+	writer := csv.NewWriter(ctx.Writer)
+	rv := reflect.ValueOf(rows)
+	rt := rv.Type().Elem()
+	header := []string{}
+	for i := 0; i < rt.NumField(); i++ {
+		header = append(header, strings.Split(rt.Field(i).Tag.Get("json"), ",")[0])
+	}
+	err = writer.Write(header)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := 0; i < rv.Len(); i++ {
+		row := rv.Index(i)
+		record := []string{}
+		for n := 0; n < row.NumField(); n++ {
+			f := row.Field(n)
+			switch f.Kind() {
+			case reflect.Slice:
+				list := []string{}
+				for x := 0; x < f.Len(); x++ {
+					list = append(list, fmt.Sprint(f.Index(x).Interface()))
+				}
+				record = append(record, h.escape(strings.Join(list, ListSeparator)))
+			case reflect.String:
+				record = append(record, h.escape(f.String()))
+			case reflect.Float32, reflect.Float64:
+				record = append(record, fmt.Sprintf("%.2f", f.Float()))
+			default:
+				record = append(record, fmt.Sprint(f.Interface()))
+			}
+		}
+		err = writer.Write(record)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	writer.Flush()
+	err = writer.Error()
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
+
+//
+// Escape a (CSV) string value.
+// Values beginning with a formula character are
+// prefixed with a single quote.
+func (h *ExportHandler) escape(s string) string {
+	if s != "" && strings.ContainsRune(FormulaPrefix, rune(s[0])) {
+		s = "'" + s
+	}
+
+	return s
+}
+
+//
+// Report (REST) resource.
+type Report struct {
+	// Report name.
+	Name string `json:"name"`
+	// Self link.
+	SelfLink string `json:"selfLink"`
+}
+
+//
+// VM report row.
+type VMRow struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Path         string   `json:"path"`
+	PowerState   string   `json:"powerState"`
+	GuestName    string   `json:"guestName"`
+	IpAddress    string   `json:"ipAddress"`
+	Firmware     string   `json:"firmware"`
+	CpuCount     int32    `json:"cpuCount"`
+	MemoryMB     int32    `json:"memoryMB"`
+	Cluster      string   `json:"cluster"`
+	Host         string   `json:"host"`
+	Datastores   []string `json:"datastores"`
+	Networks     []string `json:"networks"`
+	DiskCount    int      `json:"diskCount"`
+	DiskCapacity int64    `json:"diskCapacity"`
+	StorageUsed  int64    `json:"storageUsed"`
+	Critical     int      `json:"critical"`
+	Warning      int      `json:"warning"`
+	Concerns     []string `json:"concerns"`
+}
+
+//
+// Host report row.
+type HostRow struct {
+	ID                string   `json:"id"`
+	Name              string   `json:"name"`
+	Path              string   `json:"path"`
+	Cluster           string   `json:"cluster"`
+	ProductName       string   `json:"productName"`
+	ProductVersion    string   `json:"productVersion"`
+	InMaintenanceMode bool     `json:"inMaintenanceMode"`
+	CpuSockets        int16    `json:"cpuSockets"`
+	CpuCores          int16    `json:"cpuCores"`
+	VmCount           int      `json:"vmCount"`
+	PoweredOn         int      `json:"poweredOn"`
+	CpuAllocated      int64    `json:"cpuAllocated"`
+	MemoryAllocatedMB int64    `json:"memoryAllocatedMB"`
+	Datastores        []string `json:"datastores"`
+	Networks          []string `json:"networks"`
+}
+
+//
+// Datastore report row.
+type DatastoreRow struct {
+	ID              string  `json:"id"`
+	Name            string  `json:"name"`
+	Path            string  `json:"path"`
+	Type            string  `json:"type"`
+	MaintenanceMode string  `json:"maintenanceMode"`
+	Capacity        int64   `json:"capacity"`
+	Free            int64   `json:"free"`
+	Used            int64   `json:"used"`
+	UsedPercent     float64 `json:"usedPercent"`
+	Provisioned     int64   `json:"provisioned"`
+	VmCount         int     `json:"vmCount"`
+	HostCount       int     `json:"hostCount"`
+}
@@ -0,0 +1,556 @@
+package vsphere
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	liberr "github.com/konveyor/controller/pkg/error"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+//
+// Routes.
+const (
+	RelationParam   = "relation"
+	GraphCollection = "graph"
+	GraphRoot       = ProviderRoot + "/" + GraphCollection
+	GraphVMRoot     = GraphRoot + "/:" + VMParam
+)
+
+//
+// VM relations.
+const (
+	// Connected to the same network.
+	RelationNetwork = "network"
+	// Disks on the same datastore.
+	RelationDatastore = "datastore"
+	// Shared (multi-writer) disk.
+	RelationDisk = "disk"
+	// Running on the same host.
+	RelationHost = "host"
+	// DRS (VM-VM) affinity rule.
+	RelationAffinity = "affinity"
+	// DRS (VM-VM) anti-affinity rule.
+	RelationAntiAffinity = "anti-affinity"
+	// DRS VM group.
+	RelationGroup = "group"
+	// DRS (VM group) dependency rule.
+	RelationDependency = "dependency"
+)
+
+//
+// Graph node kinds.
+// Shared objects (hubs) are reported using the
+// relation as the kind.
+const (
+	NodeVM = "vm"
+)
+
+//
+// Relations reported by default.
+var DefaultRelations = []string{
+	RelationDisk,
+	RelationAffinity,
+	RelationGroup,
+	RelationDependency,
+}
+
+//
+// VM dependency graph handler.
+type GraphHandler struct {
+	Handler
+	// Requested relations.
+	relations map[string]bool
+	// VMs by ID.
+	vms map[string]*model.VM
+	// Shared objects (hub nodes) by ID.
+	hubs map[string]*GraphNode
+	// Edges by node pair.
+	edges map[[2]string]*GraphEdge
+}
+
+//
+// Add routes to the `gin` router.
+func (h *GraphHandler) AddRoutes(e *gin.Engine) {
+	e.GET(GraphRoot, h.List)
+	e.GET(GraphRoot+"/", h.List)
+	e.GET(GraphVMRoot, h.Get)
+}
+
+//
+// Get the graph of all VMs.
+func (h GraphHandler) List(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	err := h.build(ctx)
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	content := h.graph(nil)
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// Get the graph of the VMs related to the specified VM.
+func (h GraphHandler) Get(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	err := h.build(ctx)
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	id := ctx.Param(VMParam)
+	if _, found := h.vms[id]; !found {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	members := map[string]bool{id: true}
+	for _, group := range h.groups() {
+		for _, member := range group {
+			if member == id {
+				for _, member := range group {
+					members[member] = true
+				}
+			}
+		}
+	}
+	content := h.graph(members)
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// Build the graph.
+// The relations are specified by the `relation` parameter as a
+// comma separated list. See: DefaultRelations.
+func (h *GraphHandler) build(ctx *gin.Context) (err error) {
+	h.relations = map[string]bool{}
+	for _, param := range ctx.QueryArray(RelationParam) {
+		for _, name := range strings.Split(param, ",") {
+			name = strings.TrimSpace(name)
+			if name != "" {
+				h.relations[name] = true
+			}
+		}
+	}
+	if len(h.relations) == 0 {
+		for _, name := range DefaultRelations {
+			h.relations[name] = true
+		}
+	}
+	db := h.Reconciler.DB()
+	vmList := []model.VM{}
+	err = db.List(&vmList, libmodel.ListOptions{Detail: 1})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	h.vms = map[string]*model.VM{}
+	for i := range vmList {
+		vm := &vmList[i]
+		h.vms[vm.ID] = vm
+	}
+	h.hubs = map[string]*GraphNode{}
+	h.edges = map[[2]string]*GraphEdge{}
+	err = h.addShared(db)
+	if err != nil {
+		return
+	}
+	err = h.addDrs(db)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+//
+// Add relations for shared networks, datastores, disks and hosts.
+// The shared object is added as a (hub) node and each VM is
+// related to the hub rather than to each of the other VMs.
+func (h *GraphHandler) addShared(db libmodel.DB) (err error) {
+	type member struct {
+		name string
+		vms  []string
+	}
+	shared := map[string]map[string]*member{
+		RelationNetwork:   {},
+		RelationDatastore: {},
+		RelationDisk:      {},
+		RelationHost:      {},
+	}
+	add := func(relation, key, name, vm string) {
+		m, found := shared[relation][key]
+		if !found {
+			m = &member{name: name}
+			shared[relation][key] = m
+		}
+		for _, id := range m.vms {
+			if id == vm {
+				return
+			}
+		}
+		m.vms = append(m.vms, vm)
+	}
+	for _, vm := range h.vms {
+		for _, ref := range vm.Networks {
+			add(RelationNetwork, ref.ID, ref.ID, vm.ID)
+		}
+		for _, disk := range vm.Disks {
+			add(RelationDatastore, disk.Datastore.ID, disk.Datastore.ID, vm.ID)
+			if disk.Shared {
+				add(RelationDisk, disk.File, disk.File, vm.ID)
+			}
+		}
+		if vm.Host.ID != "" {
+			add(RelationHost, vm.Host.ID, vm.Host.ID, vm.ID)
+		}
+	}
+	for relation, members := range shared {
+		if !h.relations[relation] {
+			continue
+		}
+		for key, m := range members {
+			if len(m.vms) < 2 {
+				continue
+			}
+			var hub *GraphNode
+			hub, err = h.hub(db, relation, key)
+			if err != nil {
+				return
+			}
+			h.hubs[hub.ID] = hub
+			for _, vm := range m.vms {
+				h.link(vm, hub.ID, relation, hub.Name)
+			}
+		}
+	}
+
+	return
+}
+
+//
+// Add relations defined by DRS rules and groups.
+func (h *GraphHandler) addDrs(db libmodel.DB) (err error) {
+	clusterList := []model.Cluster{}
+	err = db.List(&clusterList, libmodel.ListOptions{Detail: 1})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, cluster := range clusterList {
+		groups := map[string][]string{}
+		for _, group := range cluster.VmGroups {
+			groups[group.Name] = h.ids(group.Vms)
+			if h.relations[RelationGroup] {
+				vms := groups[group.Name]
+				h.connect(vms, vms, RelationGroup, group.Name)
+			}
+		}
+		for _, rule := range cluster.DrsRules {
+			if !rule.Enabled {
+				continue
+			}
+			switch rule.Kind {
+			case model.AffinityRule:
+				if h.relations[RelationAffinity] {
+					vms := h.ids(rule.Vms)
+					h.connect(vms, vms, RelationAffinity, rule.Name)
+				}
+			case model.AntiAffinityRule:
+				if h.relations[RelationAntiAffinity] {
+					vms := h.ids(rule.Vms)
+					h.connect(vms, vms, RelationAntiAffinity, rule.Name)
+				}
+			case model.DependencyRule:
+				if h.relations[RelationDependency] {
+					h.connect(
+						groups[rule.VmGroup],
+						groups[rule.DependsOnVmGroup],
+						RelationDependency,
+						rule.Name)
+				}
+			}
+		}
+	}
+
+	return
+}
+
+//
+// IDs of the (known) referenced VMs.
+func (h *GraphHandler) ids(refs []model.Ref) (list []string) {
+	list = []string{}
+	for _, ref := range refs {
+		if _, found := h.vms[ref.ID]; found {
+			list = append(list, ref.ID)
+		}
+	}
+
+	return
+}
+
+//
+// Build the (hub) node for the shared object.
+// The name defaults to the ID when not found.
+func (h *GraphHandler) hub(db libmodel.DB, relation, id string) (node *GraphNode, err error) {
+	node = &GraphNode{
+		ID:   id,
+		Kind: relation,
+		Name: id,
+	}
+	switch relation {
+	case RelationNetwork:
+		m := &model.Network{Base: model.Base{ID: id}}
+		err = db.Get(m)
+		if err == nil {
+			node.Name = m.Name
+			node.SelfLink = NetworkHandler{Handler: h.Handler}.Link(h.Provider, m)
+		}
+	case RelationDatastore:
+		m := &model.Datastore{Base: model.Base{ID: id}}
+		err = db.Get(m)
+		if err == nil {
+			node.Name = m.Name
+			node.SelfLink = DatastoreHandler{Handler: h.Handler}.Link(h.Provider, m)
+		}
+	case RelationHost:
+		m := &model.Host{Base: model.Base{ID: id}}
+		err = db.Get(m)
+		if err == nil {
+			node.Name = m.Name
+			node.SelfLink = HostHandler{Handler: h.Handler}.Link(h.Provider, m)
+		}
+	}
+	if err != nil {
+		if errors.Is(err, model.NotFound) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+	}
+
+	return
+}
+
+//
+// Add the relation between each VM in (a) and each VM in (b).
+func (h *GraphHandler) connect(a, b []string, relation, name string) {
+	for _, source := range a {
+		for _, target := range b {
+			if source == target {
+				continue
+			}
+			if source > target {
+				h.link(target, source, relation, name)
+			} else {
+				h.link(source, target, relation, name)
+			}
+		}
+	}
+}
+
+//
+// Add the relation between the source and target nodes.
+func (h *GraphHandler) link(source, target, relation, name string) {
+	key := [2]string{source, target}
+	edge, found := h.edges[key]
+	if !found {
+		edge = &GraphEdge{
+			Source:    source,
+			Target:    target,
+			Relations: []Relation{},
+		}
+		h.edges[key] = edge
+	}
+	r := Relation{Kind: relation, Name: name}
+	for _, existing := range edge.Relations {
+		if existing == r {
+			return
+		}
+	}
+	edge.Relations = append(edge.Relations, r)
+}
+
+//
+// Groups of related VMs (connected components).
+// Anti-affinity relations do not join groups.
+// Unrelated VMs and hub nodes are not reported.
+func (h *GraphHandler) groups() (groups [][]string) {
+	parent := map[string]string{}
+	var find func(string) string
+	find = func(id string) string {
+		p := parent[id]
+		if p == id {
+			return id
+		}
+		root := find(p)
+		parent[id] = root
+		return root
+	}
+	for _, edge := range h.edges {
+		joined := false
+		for _, r := range edge.Relations {
+			if r.Kind != RelationAntiAffinity {
+				joined = true
+				break
+			}
+		}
+		if !joined {
+			continue
+		}
+		for _, id := range []string{edge.Source, edge.Target} {
+			if _, found := parent[id]; !found {
+				parent[id] = id
+			}
+		}
+		a := find(edge.Source)
+		b := find(edge.Target)
+		if a != b {
+			parent[a] = b
+		}
+	}
+	byRoot := map[string][]string{}
+	for id := range parent {
+		if _, found := h.vms[id]; !found {
+			continue
+		}
+		root := find(id)
+		byRoot[root] = append(byRoot[root], id)
+	}
+	groups = [][]string{}
+	for _, members := range byRoot {
+		sort.Strings(members)
+		groups = append(groups, members)
+	}
+	sort.Slice(
+		groups,
+		func(i, j int) bool {
+			return groups[i][0] < groups[j][0]
+		})
+
+	return
+}
+
+//
+// Build the graph content.
+// Limited to the (optional) VM members and the hub
+// nodes related to them.
+func (h *GraphHandler) graph(members map[string]bool) (graph Graph) {
+	included := func(id string) bool {
+		return members == nil || members[id]
+	}
+	hubs := map[string]bool{}
+	graph = Graph{
+		Nodes:  []GraphNode{},
+		Edges:  []GraphEdge{},
+		Groups: [][]string{},
+	}
+	vmHandler := VMHandler{Handler: h.Handler}
+	for id, vm := range h.vms {
+		if !included(id) {
+			continue
+		}
+		host := vm.Host
+		graph.Nodes = append(
+			graph.Nodes,
+			GraphNode{
+				ID:       vm.ID,
+				Kind:     NodeVM,
+				Name:     vm.Name,
+				Host:     &host,
+				SelfLink: vmHandler.Link(h.Provider, vm),
+			})
+	}
+	for _, edge := range h.edges {
+		if _, found := h.hubs[edge.Target]; found && included(edge.Source) {
+			hubs[edge.Target] = true
+		}
+	}
+	for id := range hubs {
+		graph.Nodes = append(graph.Nodes, *h.hubs[id])
+	}
+	sort.Slice(
+		graph.Nodes,
+		func(i, j int) bool {
+			return graph.Nodes[i].ID < graph.Nodes[j].ID
+		})
+	for _, edge := range h.edges {
+		if included(edge.Source) && (included(edge.Target) || hubs[edge.Target]) {
+			graph.Edges = append(graph.Edges, *edge)
+		}
+	}
+	sort.Slice(
+		graph.Edges,
+		func(i, j int) bool {
+			a := graph.Edges[i]
+			b := graph.Edges[j]
+			if a.Source != b.Source {
+				return a.Source < b.Source
+			}
+			return a.Target < b.Target
+		})
+	for _, group := range h.groups() {
+		if included(group[0]) {
+			graph.Groups = append(graph.Groups, group)
+		}
+	}
+
+	return
+}
+
+//
+// VM dependency graph.
+type Graph struct {
+	// VMs and shared objects (hubs).
+	Nodes []GraphNode `json:"nodes"`
+	// Related VMs and VMs related to shared objects.
+	Edges []GraphEdge `json:"edges"`
+	// Groups of related VMs (connected).
+	Groups [][]string `json:"groups"`
+}
+
+//
+// Graph node (VM or shared object).
+type GraphNode struct {
+	ID string `json:"id"`
+	// Kind (vm|network|datastore|disk|host).
+	Kind     string     `json:"kind"`
+	Name     string     `json:"name"`
+	Host     *model.Ref `json:"host,omitempty"`
+	SelfLink string     `json:"selfLink,omitempty"`
+}
+
+//
+// Graph edge.
+type GraphEdge struct {
+	// Source (VM) node ID.
+	Source string `json:"source"`
+	// Target (VM or hub) node ID.
+	Target string `json:"target"`
+	// Relations.
+	Relations []Relation `json:"relations"`
+}
+
+//
+// VM relation.
+type Relation struct {
+	// Relation kind.
+	Kind string `json:"kind"`
+	// Name of the shared object, rule or group.
+	Name string `json:"name"`
+}
@@ -0,0 +1,60 @@
+package vsphere
+
+import (
+	"github.com/gin-gonic/gin"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	inventory "github.com/konveyor/forklift-controller/pkg/controller/provider/model"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"net/http"
+)
+
+//
+// Routes.
+const (
+	HealthRoot = ProviderRoot + "/health"
+)
+
+//
+// Collector health handler.
+type HealthHandler struct {
+	Handler
+}
+
+//
+// Add routes to the `gin` router.
+func (h *HealthHandler) AddRoutes(e *gin.Engine) {
+	e.GET(HealthRoot, h.Get)
+}
+
+//
+// List resources in a REST collection.
+// Not supported.
+func (h HealthHandler) List(ctx *gin.Context) {
+	ctx.Status(http.StatusMethodNotAllowed)
+}
+
+//
+// Get the collector health.
+// Reported without waiting for the inventory to be consistent.
+func (h HealthHandler) Get(ctx *gin.Context) {
+	provider := &api.Provider{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: ctx.Param(base.NsParam),
+			Name:      ctx.Param(ProviderParam),
+		},
+	}
+	reconciler, found := h.Container.Get(provider)
+	if !found {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	reporter, cast := reconciler.(inventory.HealthReporter)
+	if !cast {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	content := reporter.Health()
+
+	ctx.JSON(http.StatusOK, content)
+}
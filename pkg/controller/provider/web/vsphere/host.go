@@ -46,7 +46,9 @@ func (h HostHandler) List(ctx *gin.Context) {
 	}
 	db := h.Reconciler.DB()
 	list := []model.Host{}
-	err := db.List(&list, h.ListOptions(ctx))
+	options := h.ListOptions(ctx)
+	h.Query.Options(&options, &model.Host{})
+	err := db.List(&list, options)
 	if err != nil {
 		Log.Trace(err)
 		ctx.Status(http.StatusInternalServerError)
@@ -58,7 +60,7 @@ func (h HostHandler) List(ctx *gin.Context) {
 		ctx.Status(http.StatusInternalServerError)
 		return
 	}
-	content := []interface{}{}
+	resources := []base.Content{}
 	for _, m := range list {
 		r := &Host{}
 		r.With(&m)
@@ -69,7 +71,13 @@ func (h HostHandler) List(ctx *gin.Context) {
 			return
 		}
 		r.SelfLink = h.Link(h.Provider, &m)
-		content = append(content, r.Content(h.Detail))
+		resources = append(resources, r)
+	}
+	content, err := h.Query.Content(resources, h.Detail, &h.Page)
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusBadRequest)
+		return
 	}
 
 	ctx.JSON(http.StatusOK, content)
@@ -188,6 +196,7 @@ type Host struct {
 	Thumbprint        string            `json:"thumbprint"`
 	CpuSockets        int16             `json:"cpuSockets"`
 	CpuCores          int16             `json:"cpuCores"`
+	CpuMhz            int32             `json:"cpuMhz"`
 	ProductName       string            `json:"productName"`
 	ProductVersion    string            `json:"productVersion"`
 	Network           model.HostNetwork `json:"networking"`
@@ -195,6 +204,8 @@ type Host struct {
 	Datastores        []model.Ref       `json:"datastores"`
 	VMs               []model.Ref       `json:"vms"`
 	NetworkAdapters   []NetworkAdapter  `json:"networkAdapters"`
+	Tags              []model.Tag       `json:"tags"`
+	CustomAttributes  model.Attributes  `json:"customAttributes"`
 }
 
 //
@@ -205,12 +216,15 @@ func (r *Host) With(m *model.Host) {
 	r.Thumbprint = m.Thumbprint
 	r.CpuSockets = m.CpuSockets
 	r.CpuCores = m.CpuCores
+	r.CpuMhz = m.CpuMhz
 	r.ProductVersion = m.ProductVersion
 	r.ProductName = m.ProductName
 	r.Network = m.Network
 	r.Networks = m.Networks
 	r.Datastores = m.Datastores
 	r.VMs = m.Vms
+	r.Tags = m.Tags
+	r.CustomAttributes = m.CustomAttributes
 	r.NetworkAdapters = []NetworkAdapter{}
 }
 
@@ -44,7 +44,9 @@ func (h NetworkHandler) List(ctx *gin.Context) {
 	}
 	db := h.Reconciler.DB()
 	list := []model.Network{}
-	err := db.List(&list, h.ListOptions(ctx))
+	options := h.ListOptions(ctx)
+	h.Query.Options(&options, &model.Network{})
+	err := db.List(&list, options)
 	if err != nil {
 		Log.Trace(err)
 		ctx.Status(http.StatusInternalServerError)
@@ -56,12 +58,18 @@ func (h NetworkHandler) List(ctx *gin.Context) {
 		ctx.Status(http.StatusInternalServerError)
 		return
 	}
-	content := []interface{}{}
+	resources := []base.Content{}
 	for _, m := range list {
 		r := &Network{}
 		r.With(&m)
 		r.SelfLink = h.Link(h.Provider, &m)
-		content = append(content, r.Content(h.Detail))
+		resources = append(resources, r)
+	}
+	content, err := h.Query.Content(resources, h.Detail, &h.Page)
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusBadRequest)
+		return
 	}
 
 	ctx.JSON(http.StatusOK, content)
@@ -150,13 +158,19 @@ func (h NetworkHandler) filter(ctx *gin.Context, list *[]model.Network) (err err
 	return
 }
 
+//
+// Tag assigned to DVS uplink portgroups.
+const UplinkTag = "SYSTEM/DVS.UPLINKPG"
+
 //
 // REST Resource.
 type Network struct {
 	Resource
-	Type     string     `json:"type"`
-	DVSwitch *model.Ref `json:"dvSwitch,omitempty"`
-	Tag      string     `json:"tag,omitempty"`
+	Type     string      `json:"type"`
+	DVSwitch *model.Ref  `json:"dvSwitch,omitempty"`
+	Tag      string      `json:"tag,omitempty"`
+	Uplink   bool        `json:"uplink,omitempty"`
+	VLAN     *model.VLAN `json:"vlan,omitempty"`
 }
 
 //
@@ -167,6 +181,8 @@ func (r *Network) With(m *model.Network) {
 	if len(m.DVSwitch.ID) > 0 {
 		r.DVSwitch = &m.DVSwitch
 		r.Type = "dvportgroup"
+		r.Uplink = strings.Contains(m.Tag, UplinkTag)
+		r.VLAN = &m.VLAN
 	} else {
 		r.Type = "standard"
 	}
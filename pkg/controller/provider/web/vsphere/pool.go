@@ -0,0 +1,267 @@
+package vsphere
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	"net/http"
+)
+
+//
+// Routes.
+const (
+	PoolParam      = "pool"
+	PoolCollection = "resourcepools"
+	PoolsRoot      = ProviderRoot + "/" + PoolCollection
+	PoolRoot       = PoolsRoot + "/:" + PoolParam
+	VAppParam      = "vapp"
+	VAppCollection = "vapps"
+	VAppsRoot      = ProviderRoot + "/" + VAppCollection
+	VAppRoot       = VAppsRoot + "/:" + VAppParam
+)
+
+//
+// Resource pool handler.
+type ResourcePoolHandler struct {
+	Handler
+}
+
+//
+// Add routes to the `gin` router.
+func (h *ResourcePoolHandler) AddRoutes(e *gin.Engine) {
+	e.GET(PoolsRoot, h.List)
+	e.GET(PoolsRoot+"/", h.List)
+	e.GET(PoolRoot, h.Get)
+}
+
+//
+// List resources in a REST collection.
+func (h ResourcePoolHandler) List(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	db := h.Reconciler.DB()
+	list := []model.ResourcePool{}
+	options := h.ListOptions(ctx)
+	h.Query.Options(&options, &model.ResourcePool{})
+	err := db.List(&list, options)
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	resources := []base.Content{}
+	for _, m := range list {
+		r := &ResourcePool{}
+		r.With(&m)
+		r.SelfLink = h.Link(h.Provider, &m)
+		resources = append(resources, r)
+	}
+	content, err := h.Query.Content(resources, h.Detail, &h.Page)
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// Get a specific REST resource.
+func (h ResourcePoolHandler) Get(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	m := &model.ResourcePool{
+		Base: model.Base{
+			ID: ctx.Param(PoolParam),
+		},
+	}
+	db := h.Reconciler.DB()
+	err := db.Get(m)
+	if errors.Is(err, model.NotFound) {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	r := &ResourcePool{}
+	r.With(m)
+	r.Path, err = m.Path(db)
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	r.SelfLink = h.Link(h.Provider, m)
+	content := r.Content(true)
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// Build self link (URI).
+func (h ResourcePoolHandler) Link(p *api.Provider, m *model.ResourcePool) string {
+	return h.Handler.Link(
+		PoolRoot,
+		base.Params{
+			base.NsParam:       p.Namespace,
+			base.ProviderParam: p.Name,
+			PoolParam:          m.ID,
+		})
+}
+
+//
+// vApp handler.
+type VAppHandler struct {
+	Handler
+}
+
+//
+// Add routes to the `gin` router.
+func (h *VAppHandler) AddRoutes(e *gin.Engine) {
+	e.GET(VAppsRoot, h.List)
+	e.GET(VAppsRoot+"/", h.List)
+	e.GET(VAppRoot, h.Get)
+}
+
+//
+// List resources in a REST collection.
+func (h VAppHandler) List(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	db := h.Reconciler.DB()
+	list := []model.VApp{}
+	options := h.ListOptions(ctx)
+	h.Query.Options(&options, &model.VApp{})
+	err := db.List(&list, options)
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	resources := []base.Content{}
+	for _, m := range list {
+		r := &VApp{}
+		r.With(&m)
+		r.SelfLink = h.Link(h.Provider, &m)
+		resources = append(resources, r)
+	}
+	content, err := h.Query.Content(resources, h.Detail, &h.Page)
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// Get a specific REST resource.
+func (h VAppHandler) Get(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	m := &model.VApp{
+		ResourcePool: model.ResourcePool{
+			Base: model.Base{
+				ID: ctx.Param(VAppParam),
+			},
+		},
+	}
+	db := h.Reconciler.DB()
+	err := db.Get(m)
+	if errors.Is(err, model.NotFound) {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	r := &VApp{}
+	r.With(m)
+	r.Path, err = m.Path(db)
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	r.SelfLink = h.Link(h.Provider, m)
+	content := r.Content(true)
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// Build self link (URI).
+func (h VAppHandler) Link(p *api.Provider, m *model.VApp) string {
+	return h.Handler.Link(
+		VAppRoot,
+		base.Params{
+			base.NsParam:       p.Namespace,
+			base.ProviderParam: p.Name,
+			VAppParam:          m.ID,
+		})
+}
+
+//
+// REST Resource.
+type ResourcePool struct {
+	Resource
+	Owner            model.Ref        `json:"owner"`
+	CpuAllocation    model.Allocation `json:"cpuAllocation"`
+	MemoryAllocation model.Allocation `json:"memoryAllocation"`
+	Pools            []model.Ref      `json:"pools"`
+	VMs              []model.Ref      `json:"vms"`
+}
+
+//
+// Build the resource using the model.
+func (r *ResourcePool) With(m *model.ResourcePool) {
+	r.Resource.With(&m.Base)
+	r.Owner = m.Owner
+	r.CpuAllocation = m.CpuAllocation
+	r.MemoryAllocation = m.MemoryAllocation
+	r.Pools = m.Pools
+	r.VMs = m.Vms
+}
+
+//
+// As content.
+func (r *ResourcePool) Content(detail bool) interface{} {
+	if !detail {
+		return r.Resource
+	}
+
+	return r
+}
+
+//
+// REST Resource.
+type VApp struct {
+	ResourcePool
+}
+
+//
+// Build the resource using the model.
+func (r *VApp) With(m *model.VApp) {
+	r.ResourcePool.With(&m.ResourcePool)
+}
@@ -16,6 +16,8 @@ const (
 	TreeRoot     = ProviderRoot + "/tree"
 	TreeHostRoot = TreeRoot + "/host"
 	TreeVmRoot   = TreeRoot + "/vm"
+	TreePoolRoot = TreeRoot + "/pool"
+	TreeNetRoot  = TreeRoot + "/network"
 )
 
 //
@@ -31,6 +33,8 @@ type TreeHandler struct {
 func (h *TreeHandler) AddRoutes(e *gin.Engine) {
 	e.GET(TreeHostRoot, h.HostTree)
 	e.GET(TreeVmRoot, h.VmTree)
+	e.GET(TreePoolRoot, h.PoolTree)
+	e.GET(TreeNetRoot, h.NetworkTree)
 }
 
 //
@@ -167,6 +171,109 @@ func (h TreeHandler) HostTree(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, content)
 }
 
+//
+// Cluster & Resource pool (and vApp) Tree.
+func (h TreeHandler) PoolTree(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	db := h.Reconciler.DB()
+	content := TreeNode{}
+	for _, dc := range h.datacenters {
+		ref := dc.Clusters
+		folder := &model.Folder{
+			Base: model.Base{
+				ID: ref.ID,
+			},
+		}
+		err := db.Get(folder)
+		if err != nil {
+			Log.Trace(err)
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+		tr := Tree{
+			Provider: h.Provider,
+			Root:     folder,
+			Leaf:     model.PoolKind,
+			DB:       db,
+			Detail: map[string]bool{
+				model.ClusterKind: h.Detail,
+				model.PoolKind:    h.Detail,
+				model.VAppKind:    h.Detail,
+				model.VmKind:      h.Detail,
+			},
+		}
+		branch, err := tr.Build()
+		if err != nil {
+			Log.Trace(err)
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+		r := Datacenter{}
+		r.With(&dc)
+		r.SelfLink = DatacenterHandler{}.Link(h.Provider, &dc)
+		branch.Kind = model.DatacenterKind
+		branch.Object = r
+		content.Children = append(content.Children, branch)
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// Network Tree.
+// Distributed portgroups are listed by switch.
+func (h TreeHandler) NetworkTree(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	db := h.Reconciler.DB()
+	content := TreeNode{}
+	for _, dc := range h.datacenters {
+		ref := dc.Networks
+		folder := &model.Folder{
+			Base: model.Base{
+				ID: ref.ID,
+			},
+		}
+		err := db.Get(folder)
+		if err != nil {
+			Log.Trace(err)
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+		tr := Tree{
+			Provider: h.Provider,
+			Root:     folder,
+			Leaf:     model.NetKind,
+			DB:       db,
+			Detail: map[string]bool{
+				model.DVSwitchKind: h.Detail,
+				model.NetKind:      h.Detail,
+			},
+		}
+		branch, err := tr.Build()
+		if err != nil {
+			Log.Trace(err)
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+		r := Datacenter{}
+		r.With(&dc)
+		r.SelfLink = DatacenterHandler{}.Link(h.Provider, &dc)
+		branch.Kind = model.DatacenterKind
+		branch.Object = r
+		content.Children = append(content.Children, branch)
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
 //
 // Tree.
 type Tree struct {
@@ -293,6 +400,39 @@ func (r *Tree) node(parent *TreeNode, m model.Model) *TreeNode {
 			Kind:   kind,
 			Object: object,
 		}
+	case model.PoolKind:
+		resource := &ResourcePool{}
+		resource.With(m.(*model.ResourcePool))
+		resource.SelfLink =
+			ResourcePoolHandler{}.Link(r.Provider, m.(*model.ResourcePool))
+		object := resource.Content(r.detail(kind))
+		node = &TreeNode{
+			parent: parent,
+			Kind:   kind,
+			Object: object,
+		}
+	case model.VAppKind:
+		resource := &VApp{}
+		resource.With(m.(*model.VApp))
+		resource.SelfLink =
+			VAppHandler{}.Link(r.Provider, m.(*model.VApp))
+		object := resource.Content(r.detail(kind))
+		node = &TreeNode{
+			parent: parent,
+			Kind:   kind,
+			Object: object,
+		}
+	case model.DVSwitchKind:
+		resource := &DVSwitch{}
+		resource.With(m.(*model.DVSwitch))
+		resource.SelfLink =
+			DVSwitchHandler{}.Link(r.Provider, m.(*model.DVSwitch))
+		object := resource.Content(r.detail(kind))
+		node = &TreeNode{
+			parent: parent,
+			Kind:   kind,
+			Object: object,
+		}
 	case model.DsKind:
 		resource := &Datastore{}
 		resource.With(m.(*model.Datastore))
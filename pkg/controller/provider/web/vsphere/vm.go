@@ -4,6 +4,7 @@ import (
 	"errors"
 	"github.com/gin-gonic/gin"
 	liberr "github.com/konveyor/controller/pkg/error"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
@@ -44,24 +45,48 @@ func (h VMHandler) List(ctx *gin.Context) {
 	}
 	db := h.Reconciler.DB()
 	list := []model.VM{}
-	err := db.List(&list, h.ListOptions(ctx))
+	options := h.ListOptions(ctx)
+	h.Query.Options(&options, &model.VM{})
+	if len(ctx.Request.URL.Query()[TagParam]) > 0 {
+		h.Query.InMemory(&options)
+		options.Detail = 1
+	}
+	err := db.List(&list, options)
 	if err != nil {
 		Log.Trace(err)
 		ctx.Status(http.StatusInternalServerError)
 		return
 	}
-	content := []interface{}{}
+	resources := []base.Content{}
 	err = h.filter(ctx, &list)
 	if err != nil {
 		Log.Trace(err)
 		ctx.Status(http.StatusInternalServerError)
 		return
 	}
+	metrics := map[string]*model.VMMetrics{}
+	if h.Detail {
+		metrics, err = h.metrics(db)
+		if err != nil {
+			Log.Trace(err)
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+	}
 	for _, m := range list {
 		r := &VM{}
 		r.With(&m)
+		if mx, found := metrics[m.ID]; found {
+			r.Metrics = mx.Windows
+		}
 		r.SelfLink = h.Link(h.Provider, &m)
-		content = append(content, r.Content(h.Detail))
+		resources = append(resources, r)
+	}
+	content, err := h.Query.Content(resources, h.Detail, &h.Page)
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusBadRequest)
+		return
 	}
 
 	ctx.JSON(http.StatusOK, content)
@@ -99,6 +124,21 @@ func (h VMHandler) Get(ctx *gin.Context) {
 		ctx.Status(http.StatusInternalServerError)
 		return
 	}
+	metrics := &model.VMMetrics{
+		Base: model.Base{
+			ID: m.ID,
+		},
+	}
+	err = db.Get(metrics)
+	if err == nil {
+		r.Metrics = metrics.Windows
+	} else {
+		if !errors.Is(err, model.NotFound) {
+			Log.Trace(err)
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+	}
 	r.SelfLink = h.Link(h.Provider, m)
 	content := r.Content(true)
 
@@ -117,10 +157,37 @@ func (h VMHandler) Link(p *api.Provider, m *model.VM) string {
 		})
 }
 
+//
+// VM performance metrics mapped by VM ID.
+func (h VMHandler) metrics(db libmodel.DB) (metrics map[string]*model.VMMetrics, err error) {
+	metrics = map[string]*model.VMMetrics{}
+	list := []model.VMMetrics{}
+	err = db.List(&list, libmodel.ListOptions{Detail: 1})
+	if err != nil {
+		return
+	}
+	for i := range list {
+		metrics[list[i].ID] = &list[i]
+	}
+
+	return
+}
+
 //
 // Filter result set.
-// Filter by path for `name` query.
 func (h VMHandler) filter(ctx *gin.Context, list *[]model.VM) (err error) {
+	err = h.filterPath(ctx, list)
+	if err != nil {
+		return
+	}
+	h.filterTag(ctx, list)
+
+	return
+}
+
+//
+// Filter by path for `name` query.
+func (h VMHandler) filterPath(ctx *gin.Context, list *[]model.VM) (err error) {
 	if len(*list) < 2 {
 		return
 	}
@@ -150,32 +217,88 @@ func (h VMHandler) filter(ctx *gin.Context, list *[]model.VM) (err error) {
 	return
 }
 
+//
+// Filter by `tag` query.
+// The tag may be specified as: <category>:<name> or <name>.
+// When multiple tags are specified, all must match.
+func (h VMHandler) filterTag(ctx *gin.Context, list *[]model.VM) {
+	wanted := ctx.Request.URL.Query()[TagParam]
+	if len(wanted) == 0 {
+		return
+	}
+	kept := []model.VM{}
+	for _, m := range *list {
+		matched := true
+		for _, tag := range wanted {
+			if !h.hasTag(m.Tags, tag) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			kept = append(kept, m)
+		}
+	}
+
+	*list = kept
+}
+
+//
+// Determine if the tag is in the list.
+func (h VMHandler) hasTag(list []model.Tag, tag string) bool {
+	category := ""
+	name := tag
+	if i := strings.Index(tag, ":"); i > 0 {
+		category = tag[:i]
+		name = tag[i+1:]
+	}
+	for _, t := range list {
+		if t.Name != name {
+			continue
+		}
+		if category == "" || t.Category == category {
+			return true
+		}
+	}
+
+	return false
+}
+
 //
 // REST Resource.
 type VM struct {
 	Resource
-	UUID                  string          `json:"uuid"`
-	Firmware              string          `json:"firmware"`
-	PowerState            string          `json:"powerState"`
-	CpuAffinity           []int32         `json:"cpuAffinity"`
-	CpuHotAddEnabled      bool            `json:"cpuHotAddEnabled"`
-	CpuHotRemoveEnabled   bool            `json:"cpuHotRemoveEnabled"`
-	MemoryHotAddEnabled   bool            `json:"memoryHotAddEnabled"`
-	FaultToleranceEnabled bool            `json:"faultToleranceEnabled"`
-	CpuCount              int32           `json:"cpuCount"`
-	CoresPerSocket        int32           `json:"coresPerSocket"`
-	MemoryMB              int32           `json:"memoryMB"`
-	GuestName             string          `json:"guestName"`
-	BalloonedMemory       int32           `json:"balloonedMemory"`
-	IpAddress             string          `json:"ipAddress"`
-	StorageUsed           int64           `json:"storageUsed"`
-	NumaNodeAffinity      []string        `json:"numaNodeAffinity"`
-	Devices               []model.Device  `json:"devices"`
-	Networks              []model.Ref     `json:"networks"`
-	Disks                 []model.Disk    `json:"disks"`
-	Host                  model.Ref       `json:"host"`
-	RevisionAnalyzed      int64           `json:"revisionAnalyzed"`
-	Concerns              []model.Concern `json:"concerns"`
+	UUID                  string               `json:"uuid"`
+	Firmware              string               `json:"firmware"`
+	SecureBoot            bool                 `json:"secureBoot"`
+	TpmEnabled            bool                 `json:"tpmEnabled"`
+	Encrypted             bool                 `json:"encrypted"`
+	PowerState            string               `json:"powerState"`
+	CpuAffinity           []int32              `json:"cpuAffinity"`
+	CpuHotAddEnabled      bool                 `json:"cpuHotAddEnabled"`
+	CpuHotRemoveEnabled   bool                 `json:"cpuHotRemoveEnabled"`
+	MemoryHotAddEnabled   bool                 `json:"memoryHotAddEnabled"`
+	FaultToleranceEnabled bool                 `json:"faultToleranceEnabled"`
+	CpuCount              int32                `json:"cpuCount"`
+	CoresPerSocket        int32                `json:"coresPerSocket"`
+	MemoryMB              int32                `json:"memoryMB"`
+	CpuAllocation         model.Allocation     `json:"cpuAllocation"`
+	MemoryAllocation      model.Allocation     `json:"memoryAllocation"`
+	GuestName             string               `json:"guestName"`
+	BalloonedMemory       int32                `json:"balloonedMemory"`
+	IpAddress             string               `json:"ipAddress"`
+	StorageUsed           int64                `json:"storageUsed"`
+	NumaNodeAffinity      []string             `json:"numaNodeAffinity"`
+	Devices               []model.Device       `json:"devices"`
+	Networks              []model.Ref          `json:"networks"`
+	Disks                 []model.Disk         `json:"disks"`
+	Host                  model.Ref            `json:"host"`
+	ResourcePool          model.Ref            `json:"resourcePool"`
+	RevisionAnalyzed      int64                `json:"revisionAnalyzed"`
+	Concerns              []model.Concern      `json:"concerns"`
+	Tags                  []model.Tag          `json:"tags"`
+	CustomAttributes      model.Attributes     `json:"customAttributes"`
+	Metrics               []model.MetricWindow `json:"metrics,omitempty"`
 }
 
 //
@@ -184,6 +307,9 @@ func (r *VM) With(m *model.VM) {
 	r.Resource.With(&m.Base)
 	r.UUID = m.UUID
 	r.Firmware = m.Firmware
+	r.SecureBoot = m.SecureBoot
+	r.TpmEnabled = m.TpmEnabled
+	r.Encrypted = m.Encrypted
 	r.PowerState = m.PowerState
 	r.CpuAffinity = m.CpuAffinity
 	r.CpuHotAddEnabled = m.CpuHotAddEnabled
@@ -192,6 +318,8 @@ func (r *VM) With(m *model.VM) {
 	r.CpuCount = m.CpuCount
 	r.CoresPerSocket = m.CoresPerSocket
 	r.MemoryMB = m.MemoryMB
+	r.CpuAllocation = m.CpuAllocation
+	r.MemoryAllocation = m.MemoryAllocation
 	r.GuestName = m.GuestName
 	r.BalloonedMemory = m.BalloonedMemory
 	r.IpAddress = m.IpAddress
@@ -202,8 +330,11 @@ func (r *VM) With(m *model.VM) {
 	r.Networks = m.Networks
 	r.Disks = m.Disks
 	r.Host = m.Host
+	r.ResourcePool = m.ResourcePool
 	r.RevisionAnalyzed = m.RevisionAnalyzed
 	r.Concerns = m.Concerns
+	r.Tags = m.Tags
+	r.CustomAttributes = m.CustomAttributes
 }
 
 //
@@ -0,0 +1,361 @@
+package vsphere_test
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1/ref"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/vcsim"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
+	"github.com/onsi/gomega"
+	"net/http"
+	"path"
+	"testing"
+)
+
+func TestREST(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	h := &vcsim.Harness{}
+	defer h.Stop()
+	g.Expect(h.Start()).NotTo(gomega.HaveOccurred())
+	client, err := web.NewClient(h.Provider)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	// Provider.
+	provider := &vsphere.Provider{}
+	err = client.Get(provider, path.Join(h.Provider.Namespace, h.Provider.Name))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(provider.Type).To(gomega.Equal(h.Provider.Type()))
+	g.Expect(provider.APIVersion).NotTo(gomega.BeEmpty())
+	vmCount, err := h.DB.Count(&model.VM{}, nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(provider.VMCount).To(gomega.Equal(vmCount))
+	hostCount, err := h.DB.Count(&model.Host{}, nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(provider.HostCount).To(gomega.Equal(hostCount))
+
+	// VMs.
+	list := []vsphere.VM{}
+	g.Expect(client.List(&list)).NotTo(gomega.HaveOccurred())
+	g.Expect(int64(len(list))).To(gomega.Equal(vmCount))
+	for _, item := range list {
+		m := &model.VM{Base: model.Base{ID: item.ID}}
+		g.Expect(h.DB.Get(m)).NotTo(gomega.HaveOccurred())
+		vm := &vsphere.VM{}
+		g.Expect(client.Get(vm, item.ID)).NotTo(gomega.HaveOccurred())
+		g.Expect(vm.Name).To(gomega.Equal(m.Name))
+		g.Expect(vm.UUID).To(gomega.Equal(m.UUID))
+		g.Expect(vm.Host).To(gomega.Equal(m.Host))
+		g.Expect(vm.Disks).To(gomega.Equal(m.Disks))
+		g.Expect(vm.Path).To(gomega.HavePrefix("/"))
+		g.Expect(vm.SelfLink).To(gomega.HavePrefix(base.VersionRoot + "/"))
+		// Find by name.
+		found := &vsphere.VM{}
+		err = client.Find(found, ref.Ref{Name: vm.Name})
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		g.Expect(found.ID).To(gomega.Equal(vm.ID))
+	}
+	err = client.Get(&vsphere.VM{}, "vm-0")
+	g.Expect(errors.As(err, &web.NotFoundError{})).To(gomega.BeTrue())
+
+	// Hosts.
+	hosts := []vsphere.Host{}
+	g.Expect(client.List(&hosts, base.Param{Key: base.DetailParam, Value: "1"})).NotTo(gomega.HaveOccurred())
+	g.Expect(int64(len(hosts))).To(gomega.Equal(hostCount))
+	for _, host := range hosts {
+		g.Expect(host.Thumbprint).NotTo(gomega.BeEmpty())
+	}
+
+	// DVSwitches.
+	dvsList := []model.DVSwitch{}
+	g.Expect(h.DB.List(&dvsList, libmodel.ListOptions{Detail: 1})).NotTo(gomega.HaveOccurred())
+	g.Expect(dvsList).NotTo(gomega.BeEmpty())
+	for _, m := range dvsList {
+		dvs := &vsphere.DVSwitch{}
+		g.Expect(client.Get(dvs, m.ID)).NotTo(gomega.HaveOccurred())
+		g.Expect(dvs.Name).To(gomega.Equal(m.Name))
+		g.Expect(dvs.Uplinks).To(gomega.ConsistOf(m.Uplinks))
+		g.Expect(dvs.Hosts).To(gomega.HaveLen(len(m.Host)))
+		g.Expect(dvs.PortGroups).To(gomega.HaveLen(len(m.PortGroups)))
+		g.Expect(dvs.PortGroups).NotTo(gomega.BeEmpty())
+	}
+
+	// Networks (portgroup VLAN).
+	networkList := []model.Network{}
+	g.Expect(h.DB.List(&networkList, libmodel.ListOptions{Detail: 1})).NotTo(gomega.HaveOccurred())
+	portGroups := 0
+	for _, m := range networkList {
+		network := &vsphere.Network{}
+		g.Expect(client.Get(network, m.ID)).NotTo(gomega.HaveOccurred())
+		if m.DVSwitch.ID == "" {
+			g.Expect(network.Type).To(gomega.Equal("standard"))
+			g.Expect(network.VLAN).To(gomega.BeNil())
+			continue
+		}
+		portGroups++
+		g.Expect(network.Type).To(gomega.Equal("dvportgroup"))
+		g.Expect(network.DVSwitch.ID).To(gomega.Equal(m.DVSwitch.ID))
+		g.Expect(network.VLAN).NotTo(gomega.BeNil())
+		g.Expect(*network.VLAN).To(gomega.Equal(m.VLAN))
+	}
+	g.Expect(portGroups).To(gomega.BeNumerically(">", 0))
+
+	// VM tree.
+	url := h.Web.URL + (&base.Handler{}).Link(
+		vsphere.TreeVmRoot,
+		base.Params{
+			base.NsParam:       h.Provider.Namespace,
+			base.ProviderParam: h.Provider.Name,
+		})
+	response, err := http.Get(url)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	defer response.Body.Close()
+	g.Expect(response.StatusCode).To(gomega.Equal(http.StatusOK))
+	tree := &vsphere.TreeNode{}
+	g.Expect(json.NewDecoder(response.Body).Decode(tree)).NotTo(gomega.HaveOccurred())
+	var leaves func(*vsphere.TreeNode) int64
+	leaves = func(node *vsphere.TreeNode) (n int64) {
+		if node.Kind == model.VmKind {
+			n++
+		}
+		for _, child := range node.Children {
+			n += leaves(child)
+		}
+		return
+	}
+	g.Expect(leaves(tree)).To(gomega.Equal(vmCount))
+
+	// Graph (shared hosts).
+	url = h.Web.URL + (&base.Handler{}).Link(
+		vsphere.GraphRoot,
+		base.Params{
+			base.NsParam:       h.Provider.Namespace,
+			base.ProviderParam: h.Provider.Name,
+		}) + "?" + vsphere.RelationParam + "=" + vsphere.RelationHost
+	response, err = http.Get(url)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	defer response.Body.Close()
+	g.Expect(response.StatusCode).To(gomega.Equal(http.StatusOK))
+	graph := &vsphere.Graph{}
+	g.Expect(json.NewDecoder(response.Body).Decode(graph)).NotTo(gomega.HaveOccurred())
+	kinds := map[string]string{}
+	for _, node := range graph.Nodes {
+		kinds[node.ID] = node.Kind
+	}
+	g.Expect(graph.Edges).NotTo(gomega.BeEmpty())
+	for _, edge := range graph.Edges {
+		g.Expect(kinds[edge.Source]).To(gomega.Equal(vsphere.NodeVM))
+		g.Expect(kinds[edge.Target]).To(gomega.Equal(vsphere.RelationHost))
+	}
+	for _, group := range graph.Groups {
+		for _, id := range group {
+			g.Expect(kinds[id]).To(gomega.Equal(vsphere.NodeVM))
+		}
+	}
+
+	// Export (CSV).
+	vm := &model.VM{Base: model.Base{ID: list[0].ID}}
+	g.Expect(h.DB.Get(vm)).NotTo(gomega.HaveOccurred())
+	vm.Name = "=1+1"
+	g.Expect(h.DB.Update(vm)).NotTo(gomega.HaveOccurred())
+	url = h.Web.URL + (&base.Handler{}).Link(
+		vsphere.ReportRoot,
+		base.Params{
+			base.NsParam:        h.Provider.Namespace,
+			base.ProviderParam:  h.Provider.Name,
+			vsphere.ReportParam: vsphere.VMReport,
+		}) + "?" + vsphere.FormatParam + "=" + vsphere.FormatCSV
+	response, err = http.Get(url)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	defer response.Body.Close()
+	g.Expect(response.StatusCode).To(gomega.Equal(http.StatusOK))
+	records, err := csv.NewReader(response.Body).ReadAll()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(records).To(gomega.HaveLen(int(vmCount) + 1))
+	names := []string{}
+	for _, record := range records[1:] {
+		names = append(names, record[1])
+	}
+	g.Expect(names).To(gomega.ContainElement("'=1+1"))
+
+	// Events (resume from another epoch).
+	url = h.Web.URL + (&base.Handler{}).Link(
+		vsphere.EventsRoot,
+		base.Params{
+			base.NsParam:       h.Provider.Namespace,
+			base.ProviderParam: h.Provider.Name,
+		})
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	request.Header.Set(base.LastEventHeader, "1-0")
+	response, err = http.DefaultClient.Do(request)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	defer response.Body.Close()
+	g.Expect(response.StatusCode).To(gomega.Equal(http.StatusOK))
+	reader := bufio.NewReader(response.Body)
+	line, err := reader.ReadString('\n')
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(line).To(gomega.MatchRegexp(`^id: [0-9]+-[0-9]+\n$`))
+	g.Expect(line).NotTo(gomega.HavePrefix("id: 1-"))
+	line, err = reader.ReadString('\n')
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(line).To(gomega.Equal("event: " + base.ActionReset + "\n"))
+	// Malformed event ID.
+	request.Header.Set(base.LastEventHeader, "10")
+	response, err = http.DefaultClient.Do(request)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	defer response.Body.Close()
+	g.Expect(response.StatusCode).To(gomega.Equal(http.StatusBadRequest))
+}
+
+func TestTagFilter(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	h := &vcsim.Harness{}
+	defer h.Stop()
+	g.Expect(h.Start()).NotTo(gomega.HaveOccurred())
+	client, err := web.NewClient(h.Provider)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	all := []vsphere.VM{}
+	g.Expect(client.List(&all)).NotTo(gomega.HaveOccurred())
+	g.Expect(len(all)).To(gomega.BeNumerically(">", 2))
+	tag := func(id string, tags ...model.Tag) {
+		m := &model.VM{Base: model.Base{ID: id}}
+		g.Expect(h.DB.Get(m)).NotTo(gomega.HaveOccurred())
+		m.Tags = tags
+		g.Expect(h.DB.Update(m)).NotTo(gomega.HaveOccurred())
+	}
+	// Tagged VMs listed last.
+	last := all[len(all)-1].ID
+	next := all[len(all)-2].ID
+	tag(last, model.Tag{Category: "env", Name: "prod"}, model.Tag{Category: "tier", Name: "db"})
+	tag(next, model.Tag{Category: "stage", Name: "prod"})
+	find := func(params ...base.Param) (ids []string) {
+		list := []vsphere.VM{}
+		g.Expect(client.List(&list, params...)).NotTo(gomega.HaveOccurred())
+		ids = []string{}
+		for _, vm := range list {
+			ids = append(ids, vm.ID)
+		}
+		return
+	}
+	param := func(key, value string) base.Param {
+		return base.Param{Key: key, Value: value}
+	}
+	// Name.
+	g.Expect(find(param(vsphere.TagParam, "prod"))).To(gomega.ConsistOf(last, next))
+	// Category and name.
+	g.Expect(find(param(vsphere.TagParam, "env:prod"))).To(gomega.ConsistOf(last))
+	g.Expect(find(param(vsphere.TagParam, "env:db"))).To(gomega.BeEmpty())
+	// All must match.
+	g.Expect(
+		find(
+			param(vsphere.TagParam, "prod"),
+			param(vsphere.TagParam, "tier:db"))).To(gomega.ConsistOf(last))
+	// Paged after filtering.
+	g.Expect(
+		find(
+			param(vsphere.TagParam, "prod"),
+			param(base.LimitParam, "1"))).To(gomega.HaveLen(1))
+	g.Expect(
+		find(
+			param(vsphere.TagParam, "prod"),
+			param(base.LimitParam, "1"),
+			param(base.OffsetParam, "1"))).To(gomega.HaveLen(1))
+	g.Expect(
+		find(
+			param(vsphere.TagParam, "prod"),
+			param(base.OffsetParam, "2"))).To(gomega.BeEmpty())
+}
+
+func TestPools(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	h := &vcsim.Harness{}
+	defer h.Stop()
+	g.Expect(h.Start()).NotTo(gomega.HaveOccurred())
+	client, err := web.NewClient(h.Provider)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	// Resource pools.
+	poolList := []model.ResourcePool{}
+	g.Expect(h.DB.List(&poolList, libmodel.ListOptions{Detail: 1})).NotTo(gomega.HaveOccurred())
+	g.Expect(poolList).NotTo(gomega.BeEmpty())
+	list := []vsphere.ResourcePool{}
+	g.Expect(client.List(&list)).NotTo(gomega.HaveOccurred())
+	g.Expect(list).To(gomega.HaveLen(len(poolList)))
+	clusterPools := map[string]bool{}
+	for _, m := range poolList {
+		pool := &vsphere.ResourcePool{}
+		g.Expect(client.Find(pool, ref.Ref{ID: m.ID})).NotTo(gomega.HaveOccurred())
+		g.Expect(pool.Name).To(gomega.Equal(m.Name))
+		g.Expect(pool.Owner).To(gomega.Equal(m.Owner))
+		g.Expect(pool.CpuAllocation).To(gomega.Equal(m.CpuAllocation))
+		g.Expect(pool.MemoryAllocation).To(gomega.Equal(m.MemoryAllocation))
+		g.Expect(pool.VMs).To(gomega.HaveLen(len(m.Vms)))
+		g.Expect(pool.Path).To(gomega.HavePrefix("/"))
+		if m.Owner.Kind == model.ClusterKind {
+			clusterPools[m.ID] = true
+		}
+	}
+	g.Expect(clusterPools).NotTo(gomega.BeEmpty())
+	err = client.Find(&vsphere.ResourcePool{}, ref.Ref{ID: "resgroup-0"})
+	g.Expect(errors.As(err, &web.NotFoundError{})).To(gomega.BeTrue())
+
+	// vApp.
+	vApp := &model.VApp{
+		ResourcePool: model.ResourcePool{
+			Base: model.Base{
+				ID:     "resgroup-v1",
+				Name:   "app",
+				Parent: model.Ref{Kind: model.PoolKind, ID: poolList[0].ID},
+			},
+		},
+	}
+	g.Expect(h.DB.Insert(vApp)).NotTo(gomega.HaveOccurred())
+	found := &vsphere.VApp{}
+	g.Expect(client.Find(found, ref.Ref{ID: vApp.ID})).NotTo(gomega.HaveOccurred())
+	g.Expect(found.Name).To(gomega.Equal(vApp.Name))
+	g.Expect(found.Parent).To(gomega.Equal(vApp.Parent))
+
+	// Pool tree.
+	// Lists the VMs in the cluster resource pools.
+	vmList := []model.VM{}
+	g.Expect(h.DB.List(&vmList, libmodel.ListOptions{Detail: 1})).NotTo(gomega.HaveOccurred())
+	vmCount := 0
+	for _, vm := range vmList {
+		if clusterPools[vm.ResourcePool.ID] {
+			vmCount++
+		}
+	}
+	g.Expect(vmCount).To(gomega.BeNumerically(">", 0))
+	url := h.Web.URL + (&base.Handler{}).Link(
+		vsphere.TreePoolRoot,
+		base.Params{
+			base.NsParam:       h.Provider.Namespace,
+			base.ProviderParam: h.Provider.Name,
+		})
+	response, err := http.Get(url)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	defer response.Body.Close()
+	g.Expect(response.StatusCode).To(gomega.Equal(http.StatusOK))
+	tree := &vsphere.TreeNode{}
+	g.Expect(json.NewDecoder(response.Body).Decode(tree)).NotTo(gomega.HaveOccurred())
+	kinds := map[string]int{}
+	var walk func(*vsphere.TreeNode)
+	walk = func(node *vsphere.TreeNode) {
+		kinds[node.Kind]++
+		for _, child := range node.Children {
+			g.Expect(child.Kind).NotTo(gomega.Equal(model.HostKind))
+			walk(child)
+		}
+	}
+	walk(tree)
+	g.Expect(kinds[model.DatacenterKind]).To(gomega.BeNumerically(">", 0))
+	g.Expect(kinds[model.ClusterKind]).To(gomega.BeNumerically(">", 0))
+	g.Expect(kinds[model.PoolKind]).To(gomega.Equal(len(clusterPools)))
+	g.Expect(kinds[model.VmKind]).To(gomega.Equal(vmCount))
+}
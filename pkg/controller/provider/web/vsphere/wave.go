@@ -0,0 +1,602 @@
+package vsphere
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	liberr "github.com/konveyor/controller/pkg/error"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1/mapped"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/analysis"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//
+// Routes.
+const (
+	MaxVMsParam          = "maxVms"
+	MaxTBParam           = "maxTB"
+	DatastoreLimitParam  = "datastoreConcurrency"
+	HostLimitParam       = "hostConcurrency"
+	PlanParam            = "plan"
+	TargetNamespaceParam = "targetNamespace"
+	DestinationParam     = "destination"
+	NetworkMapParam      = "network"
+	StorageMapParam      = "storage"
+	WaveCollection       = "waves"
+	WavesRoot            = ProviderRoot + "/" + WaveCollection
+)
+
+//
+// Destination network types.
+const (
+	PodNetwork    = "pod"
+	MultusNetwork = "multus"
+)
+
+//
+// Default maximum number of VMs in a wave.
+const DefaultWaveVMs = 20
+
+//
+// Bytes in a (decimal) terabyte.
+const TB = 1000 * 1000 * 1000 * 1000
+
+//
+// Migration wave planning handler.
+// Proposes the VMs selected by folder, tag and query (filter)
+// be migrated in waves. Related VMs (See: GraphHandler) are kept
+// in the same wave. Each wave is limited by:
+//   maxVms - number of VMs (default: 20).
+//   maxTB - total disk capacity (TB).
+//   datastoreConcurrency - number of VMs with disks on any datastore.
+//   hostConcurrency - number of VMs running on any host.
+// Limits (other than maxVms) of 0 are unlimited.
+// Each wave includes a draft Plan CR. The plan (resource) map
+// is built using the `network` and `storage` parameters:
+//   network - <network ID>:pod or <network ID>:<namespace>/<name>.
+//   storage - <datastore ID>:<storage class>.
+// Networks and datastores used by the wave but not mapped are
+// reported as warnings.
+type WaveHandler struct {
+	Handler
+}
+
+//
+// Add routes to the `gin` router.
+func (h *WaveHandler) AddRoutes(e *gin.Engine) {
+	e.GET(WavesRoot, h.List)
+	e.GET(WavesRoot+"/", h.List)
+}
+
+//
+// Propose migration waves.
+func (h WaveHandler) List(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	limits, err := h.limits(ctx)
+	if err != nil {
+		ctx.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	mp, err := h.resourceMap(ctx)
+	if err != nil {
+		ctx.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	graph := GraphHandler{Handler: h.Handler}
+	err = graph.build(ctx)
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	selected, err := h.selected(ctx, graph.vms)
+	if err != nil {
+		Log.Trace(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	proposal := h.propose(&graph, selected, limits)
+	h.plans(ctx, proposal, graph.vms, mp)
+
+	ctx.JSON(http.StatusOK, proposal)
+}
+
+//
+// Get a specific REST resource.
+// Not supported.
+func (h WaveHandler) Get(ctx *gin.Context) {
+	ctx.Status(http.StatusMethodNotAllowed)
+}
+
+//
+// Wave limits.
+type WaveLimits struct {
+	// Maximum number of VMs.
+	VMs int `json:"maxVms"`
+	// Maximum disk capacity (bytes).
+	Bytes int64 `json:"maxBytes,omitempty"`
+	// Maximum number of VMs per datastore.
+	Datastore int `json:"datastoreConcurrency,omitempty"`
+	// Maximum number of VMs per host.
+	Host int `json:"hostConcurrency,omitempty"`
+}
+
+//
+// Parse the limits.
+func (h WaveHandler) limits(ctx *gin.Context) (limits WaveLimits, err error) {
+	q := ctx.Request.URL.Query()
+	limits.VMs = DefaultWaveVMs
+	integer := func(name string, n *int) (err error) {
+		s := q.Get(name)
+		if s == "" {
+			return
+		}
+		*n, err = strconv.Atoi(s)
+		if err != nil || *n < 0 {
+			err = liberr.New(name + " must be a non-negative integer.")
+		}
+		return
+	}
+	err = integer(MaxVMsParam, &limits.VMs)
+	if err != nil {
+		return
+	}
+	err = integer(DatastoreLimitParam, &limits.Datastore)
+	if err != nil {
+		return
+	}
+	err = integer(HostLimitParam, &limits.Host)
+	if err != nil {
+		return
+	}
+	if s := q.Get(MaxTBParam); s != "" {
+		tb, pErr := strconv.ParseFloat(s, 64)
+		if pErr != nil || tb < 0 {
+			err = liberr.New(MaxTBParam + " must be a non-negative number.")
+			return
+		}
+		limits.Bytes = int64(tb * TB)
+	}
+	if limits.VMs == 0 {
+		err = liberr.New(MaxVMsParam + " must be greater than 0.")
+	}
+
+	return
+}
+
+//
+// Parse the (network and storage) resource map.
+func (h WaveHandler) resourceMap(ctx *gin.Context) (mp *plan.Map, err error) {
+	q := ctx.Request.URL.Query()
+	mp = &plan.Map{}
+	for _, s := range q[NetworkMapParam] {
+		part := strings.SplitN(s, ":", 2)
+		if len(part) != 2 || part[0] == "" || part[1] == "" {
+			err = liberr.New(NetworkMapParam + " must be: <network ID>:pod or <network ID>:<namespace>/<name>.")
+			return
+		}
+		destination := mapped.DestinationNetwork{Type: PodNetwork}
+		if part[1] != PodNetwork {
+			nsName := strings.SplitN(part[1], "/", 2)
+			if len(nsName) != 2 || nsName[0] == "" || nsName[1] == "" {
+				err = liberr.New(NetworkMapParam + " must be: <network ID>:pod or <network ID>:<namespace>/<name>.")
+				return
+			}
+			destination = mapped.DestinationNetwork{
+				Type:      MultusNetwork,
+				Namespace: nsName[0],
+				Name:      nsName[1],
+			}
+		}
+		mp.Networks = append(
+			mp.Networks,
+			mapped.NetworkPair{
+				Source:      ref.Ref{ID: part[0]},
+				Destination: destination,
+			})
+	}
+	for _, s := range q[StorageMapParam] {
+		part := strings.SplitN(s, ":", 2)
+		if len(part) != 2 || part[0] == "" || part[1] == "" {
+			err = liberr.New(StorageMapParam + " must be: <datastore ID>:<storage class>.")
+			return
+		}
+		mp.Datastores = append(
+			mp.Datastores,
+			mapped.StoragePair{
+				Source: ref.Ref{ID: part[0]},
+				Destination: mapped.DestinationStorage{
+					StorageClass: part[1],
+				},
+			})
+	}
+
+	return
+}
+
+//
+// Select VMs by `folder`, `tag` and `filter`.
+// The folder is matched by path prefix. Example: /dc1/vm/finance.
+func (h WaveHandler) selected(ctx *gin.Context, vms map[string]*model.VM) (selected map[string]bool, err error) {
+	selected = map[string]bool{}
+	q := ctx.Request.URL.Query()
+	folder := strings.TrimRight(q.Get(FolderParam), "/")
+	vmHandler := VMHandler{Handler: h.Handler}
+	db := h.Reconciler.DB()
+	for id, m := range vms {
+		if folder != "" {
+			path, pErr := m.Path(db)
+			if pErr != nil {
+				err = liberr.Wrap(pErr)
+				return
+			}
+			if !strings.HasPrefix(path, folder+"/") {
+				continue
+			}
+		}
+		matched := true
+		for _, tag := range q[TagParam] {
+			if !vmHandler.hasTag(m.Tags, tag) {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		if h.Query.Filter != nil {
+			r := &VM{}
+			r.With(m)
+			object, oErr := analysis.Object(r.Content(true))
+			if oErr != nil {
+				err = liberr.Wrap(oErr)
+				return
+			}
+			mp, _ := object.(map[string]interface{})
+			matched, err = h.Query.Filter.Eval(analysis.Env(mp))
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+			if !matched {
+				continue
+			}
+		}
+		selected[id] = true
+	}
+
+	return
+}
+
+//
+// Propose waves.
+// Groups of related VMs are placed (largest first) in the
+// first wave with sufficient room.
+func (h WaveHandler) propose(graph *GraphHandler, selected map[string]bool, limits WaveLimits) (proposal *WaveProposal) {
+	proposal = &WaveProposal{
+		Limits: limits,
+		Waves:  []*Wave{},
+	}
+	units := []*waveUnit{}
+	grouped := map[string]bool{}
+	for _, group := range graph.groups() {
+		unit := &waveUnit{}
+		excluded := []string{}
+		for _, id := range group {
+			grouped[id] = true
+			if selected[id] {
+				unit.add(graph.vms[id])
+			} else {
+				excluded = append(excluded, id)
+			}
+		}
+		if len(unit.vms) == 0 {
+			continue
+		}
+		if len(excluded) > 0 {
+			unit.warnings = append(
+				unit.warnings,
+				fmt.Sprintf(
+					"Related VMs not selected: %s.",
+					strings.Join(excluded, ", ")))
+		}
+		units = append(units, unit)
+	}
+	for id := range selected {
+		if !grouped[id] {
+			unit := &waveUnit{}
+			unit.add(graph.vms[id])
+			units = append(units, unit)
+		}
+	}
+	sort.Slice(
+		units,
+		func(i, j int) bool {
+			a := units[i]
+			b := units[j]
+			if len(a.vms) != len(b.vms) {
+				return len(a.vms) > len(b.vms)
+			}
+			if a.bytes != b.bytes {
+				return a.bytes > b.bytes
+			}
+			return a.vms[0].ID < b.vms[0].ID
+		})
+	for _, unit := range units {
+		placed := false
+		for _, wave := range proposal.Waves {
+			if wave.fits(unit, limits) {
+				wave.add(unit)
+				placed = true
+				break
+			}
+		}
+		if placed {
+			continue
+		}
+		wave := &Wave{
+			Number:     len(proposal.Waves) + 1,
+			VMs:        []WaveVM{},
+			Datastores: map[string]int{},
+			Hosts:      map[string]int{},
+		}
+		if !wave.fits(unit, limits) {
+			wave.Warnings = append(
+				wave.Warnings,
+				fmt.Sprintf(
+					"Related VMs (%d) exceed the wave limits.",
+					len(unit.vms)))
+		}
+		wave.add(unit)
+		proposal.Waves = append(proposal.Waves, wave)
+	}
+	for _, wave := range proposal.Waves {
+		sort.Slice(
+			wave.VMs,
+			func(i, j int) bool {
+				return wave.VMs[i].ID < wave.VMs[j].ID
+			})
+	}
+
+	return
+}
+
+//
+// Build the draft plans.
+// Named: <plan>-wave-<n>. The plan defaults to the provider name.
+// The destination may be specified as: <namespace>/<name> or <name>.
+// The plan map includes the pairs for the networks and datastores
+// used by the wave.
+func (h WaveHandler) plans(ctx *gin.Context, proposal *WaveProposal, vmMap map[string]*model.VM, mp *plan.Map) {
+	q := ctx.Request.URL.Query()
+	prefix := q.Get(PlanParam)
+	if prefix == "" {
+		prefix = h.Provider.Name
+	}
+	destination := core.ObjectReference{
+		Namespace: h.Provider.Namespace,
+	}
+	if s := q.Get(DestinationParam); s != "" {
+		part := strings.SplitN(s, "/", 2)
+		if len(part) == 2 {
+			destination.Namespace = part[0]
+			destination.Name = part[1]
+		} else {
+			destination.Name = s
+		}
+	}
+	for _, wave := range proposal.Waves {
+		vms := []plan.VM{}
+		networks := map[string]bool{}
+		datastores := map[string]bool{}
+		for _, vm := range wave.VMs {
+			vms = append(
+				vms,
+				plan.VM{
+					Ref: ref.Ref{ID: vm.ID},
+				})
+			if m, found := vmMap[vm.ID]; found {
+				for _, network := range m.Networks {
+					networks[network.ID] = true
+				}
+				for _, disk := range m.Disks {
+					datastores[disk.Datastore.ID] = true
+				}
+			}
+		}
+		waveMap := plan.Map{}
+		notMapped := []string{}
+		for _, id := range h.sorted(networks) {
+			if pair, found := mp.FindNetwork(id); found {
+				waveMap.Networks = append(waveMap.Networks, pair)
+			} else {
+				notMapped = append(notMapped, id)
+			}
+		}
+		if len(notMapped) > 0 {
+			wave.Warnings = append(
+				wave.Warnings,
+				fmt.Sprintf(
+					"Networks not mapped: %s.",
+					strings.Join(notMapped, ", ")))
+		}
+		notMapped = []string{}
+		for _, id := range h.sorted(datastores) {
+			if pair, found := mp.FindStorage(id); found {
+				waveMap.Datastores = append(waveMap.Datastores, pair)
+			} else {
+				notMapped = append(notMapped, id)
+			}
+		}
+		if len(notMapped) > 0 {
+			wave.Warnings = append(
+				wave.Warnings,
+				fmt.Sprintf(
+					"Datastores not mapped: %s.",
+					strings.Join(notMapped, ", ")))
+		}
+		wave.Plan = &api.Plan{
+			TypeMeta: meta.TypeMeta{
+				APIVersion: api.SchemeGroupVersion.String(),
+				Kind:       "Plan",
+			},
+			ObjectMeta: meta.ObjectMeta{
+				Namespace: h.Provider.Namespace,
+				Name:      fmt.Sprintf("%s-wave-%d", prefix, wave.Number),
+			},
+			Spec: api.PlanSpec{
+				Description: fmt.Sprintf(
+					"Migration wave %d of %d.",
+					wave.Number,
+					len(proposal.Waves)),
+				TargetNamespace: q.Get(TargetNamespaceParam),
+				Provider: api.ProviderPair{
+					Source: core.ObjectReference{
+						Namespace: h.Provider.Namespace,
+						Name:      h.Provider.Name,
+					},
+					Destination: destination,
+				},
+				Map: waveMap,
+				VMs: vms,
+			},
+		}
+	}
+}
+
+//
+// Sorted set members.
+func (h WaveHandler) sorted(set map[string]bool) (list []string) {
+	list = []string{}
+	for id := range set {
+		list = append(list, id)
+	}
+	sort.Strings(list)
+	return
+}
+
+//
+// Related VMs placed together.
+type waveUnit struct {
+	vms        []*model.VM
+	bytes      int64
+	datastores map[string]int
+	hosts      map[string]int
+	warnings   []string
+}
+
+//
+// Add a VM.
+func (u *waveUnit) add(vm *model.VM) {
+	if u.datastores == nil {
+		u.datastores = map[string]int{}
+		u.hosts = map[string]int{}
+	}
+	u.vms = append(u.vms, vm)
+	datastores := map[string]bool{}
+	for _, disk := range vm.Disks {
+		u.bytes += disk.Capacity
+		datastores[disk.Datastore.ID] = true
+	}
+	for id := range datastores {
+		u.datastores[id]++
+	}
+	if vm.Host.ID != "" {
+		u.hosts[vm.Host.ID]++
+	}
+}
+
+//
+// Migration wave proposal.
+type WaveProposal struct {
+	// Limits.
+	Limits WaveLimits `json:"limits"`
+	// Waves.
+	Waves []*Wave `json:"waves"`
+}
+
+//
+// Migration wave.
+type Wave struct {
+	// Wave number.
+	Number int `json:"number"`
+	// VMs.
+	VMs []WaveVM `json:"vms"`
+	// Total disk capacity (bytes).
+	Bytes int64 `json:"bytes"`
+	// Number of VMs by datastore ID.
+	Datastores map[string]int `json:"datastores"`
+	// Number of VMs by host ID.
+	Hosts map[string]int `json:"hosts"`
+	// Warnings.
+	Warnings []string `json:"warnings,omitempty"`
+	// Draft plan.
+	Plan *api.Plan `json:"plan"`
+}
+
+//
+// VM in a wave.
+type WaveVM struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+//
+// Determine if the unit fits in the wave.
+func (r *Wave) fits(unit *waveUnit, limits WaveLimits) bool {
+	if len(r.VMs)+len(unit.vms) > limits.VMs {
+		return false
+	}
+	if limits.Bytes > 0 && r.Bytes+unit.bytes > limits.Bytes {
+		return false
+	}
+	if limits.Datastore > 0 {
+		for id, n := range unit.datastores {
+			if r.Datastores[id]+n > limits.Datastore {
+				return false
+			}
+		}
+	}
+	if limits.Host > 0 {
+		for id, n := range unit.hosts {
+			if r.Hosts[id]+n > limits.Host {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+//
+// Add the unit.
+func (r *Wave) add(unit *waveUnit) {
+	for _, vm := range unit.vms {
+		r.VMs = append(
+			r.VMs,
+			WaveVM{
+				ID:   vm.ID,
+				Name: vm.Name,
+			})
+	}
+	r.Bytes += unit.bytes
+	for id, n := range unit.datastores {
+		r.Datastores[id] += n
+	}
+	for id, n := range unit.hosts {
+		r.Hosts[id] += n
+	}
+	r.Warnings = append(r.Warnings, unit.warnings...)
+}
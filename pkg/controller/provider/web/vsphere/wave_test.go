@@ -0,0 +1,239 @@
+package vsphere
+
+import (
+	"github.com/gin-gonic/gin"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	"github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPropose(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	vm := func(id, host string, datastores ...string) *model.VM {
+		m := &model.VM{
+			Base: model.Base{ID: id, Name: id},
+			Host: model.Ref{Kind: model.HostKind, ID: host},
+		}
+		for _, ds := range datastores {
+			m.Disks = append(
+				m.Disks,
+				model.Disk{
+					Datastore: model.Ref{Kind: model.DsKind, ID: ds},
+					Capacity:  TB / 2,
+				})
+		}
+		return m
+	}
+	graph := func(vms ...*model.VM) *GraphHandler {
+		h := &GraphHandler{
+			vms:   map[string]*model.VM{},
+			hubs:  map[string]*GraphNode{},
+			edges: map[[2]string]*GraphEdge{},
+		}
+		for _, m := range vms {
+			h.vms[m.ID] = m
+		}
+		return h
+	}
+	all := func(h *GraphHandler) map[string]bool {
+		selected := map[string]bool{}
+		for id := range h.vms {
+			selected[id] = true
+		}
+		return selected
+	}
+	ids := func(wave *Wave) (list []string) {
+		list = []string{}
+		for _, vm := range wave.VMs {
+			list = append(list, vm.ID)
+		}
+		return
+	}
+	handler := WaveHandler{}
+
+	// Split by maxVms.
+	h := graph(
+		vm("vm-1", "host-1", "ds-1"),
+		vm("vm-2", "host-1", "ds-1"),
+		vm("vm-3", "host-1", "ds-1"),
+		vm("vm-4", "host-1", "ds-1"),
+		vm("vm-5", "host-1", "ds-1"))
+	proposal := handler.propose(h, all(h), WaveLimits{VMs: 2})
+	g.Expect(proposal.Waves).To(gomega.HaveLen(3))
+	g.Expect(ids(proposal.Waves[0])).To(gomega.Equal([]string{"vm-1", "vm-2"}))
+	g.Expect(ids(proposal.Waves[1])).To(gomega.Equal([]string{"vm-3", "vm-4"}))
+	g.Expect(ids(proposal.Waves[2])).To(gomega.Equal([]string{"vm-5"}))
+
+	// Related VMs placed (largest group first) in the same wave.
+	h = graph(
+		vm("vm-1", "host-1", "ds-1"),
+		vm("vm-2", "host-1", "ds-1"),
+		vm("vm-3", "host-1", "ds-1"),
+		vm("vm-4", "host-1", "ds-1"),
+		vm("vm-5", "host-1", "ds-1"))
+	h.connect([]string{"vm-2", "vm-4", "vm-5"}, []string{"vm-2", "vm-4", "vm-5"}, RelationAffinity, "r1")
+	proposal = handler.propose(h, all(h), WaveLimits{VMs: 3})
+	g.Expect(proposal.Waves).To(gomega.HaveLen(2))
+	g.Expect(ids(proposal.Waves[0])).To(gomega.Equal([]string{"vm-2", "vm-4", "vm-5"}))
+	g.Expect(ids(proposal.Waves[1])).To(gomega.Equal([]string{"vm-1", "vm-3"}))
+	for _, wave := range proposal.Waves {
+		g.Expect(wave.Warnings).To(gomega.BeEmpty())
+	}
+
+	// Anti-affinity does not group.
+	h = graph(
+		vm("vm-1", "host-1", "ds-1"),
+		vm("vm-2", "host-1", "ds-1"))
+	h.connect([]string{"vm-1", "vm-2"}, []string{"vm-1", "vm-2"}, RelationAntiAffinity, "r1")
+	proposal = handler.propose(h, all(h), WaveLimits{VMs: 1})
+	g.Expect(proposal.Waves).To(gomega.HaveLen(2))
+
+	// Related VMs not selected.
+	h = graph(
+		vm("vm-1", "host-1", "ds-1"),
+		vm("vm-2", "host-1", "ds-1"))
+	h.connect([]string{"vm-1", "vm-2"}, []string{"vm-1", "vm-2"}, RelationGroup, "g1")
+	proposal = handler.propose(h, map[string]bool{"vm-1": true}, WaveLimits{VMs: 10})
+	g.Expect(proposal.Waves).To(gomega.HaveLen(1))
+	g.Expect(ids(proposal.Waves[0])).To(gomega.Equal([]string{"vm-1"}))
+	g.Expect(proposal.Waves[0].Warnings).To(
+		gomega.ConsistOf("Related VMs not selected: vm-2."))
+
+	// Datastore concurrency.
+	h = graph(
+		vm("vm-1", "host-1", "ds-1"),
+		vm("vm-2", "host-2", "ds-1"),
+		vm("vm-3", "host-3", "ds-1"),
+		vm("vm-4", "host-4", "ds-2"))
+	proposal = handler.propose(h, all(h), WaveLimits{VMs: 10, Datastore: 2})
+	g.Expect(proposal.Waves).To(gomega.HaveLen(2))
+	g.Expect(ids(proposal.Waves[0])).To(gomega.Equal([]string{"vm-1", "vm-2", "vm-4"}))
+	g.Expect(ids(proposal.Waves[1])).To(gomega.Equal([]string{"vm-3"}))
+	g.Expect(proposal.Waves[0].Datastores).To(
+		gomega.Equal(map[string]int{"ds-1": 2, "ds-2": 1}))
+
+	// Host concurrency.
+	h = graph(
+		vm("vm-1", "host-1", "ds-1"),
+		vm("vm-2", "host-1", "ds-2"),
+		vm("vm-3", "host-2", "ds-3"))
+	proposal = handler.propose(h, all(h), WaveLimits{VMs: 10, Host: 1})
+	g.Expect(proposal.Waves).To(gomega.HaveLen(2))
+	g.Expect(ids(proposal.Waves[0])).To(gomega.Equal([]string{"vm-1", "vm-3"}))
+	g.Expect(ids(proposal.Waves[1])).To(gomega.Equal([]string{"vm-2"}))
+
+	// Capacity.
+	h = graph(
+		vm("vm-1", "host-1", "ds-1", "ds-1"),
+		vm("vm-2", "host-2", "ds-2"),
+		vm("vm-3", "host-3", "ds-3"))
+	proposal = handler.propose(h, all(h), WaveLimits{VMs: 10, Bytes: TB})
+	g.Expect(proposal.Waves).To(gomega.HaveLen(2))
+	g.Expect(proposal.Waves[0].Bytes).To(gomega.Equal(int64(TB)))
+	g.Expect(proposal.Waves[1].Bytes).To(gomega.Equal(int64(TB)))
+
+	// Oversize group.
+	h = graph(
+		vm("vm-1", "host-1", "ds-1"),
+		vm("vm-2", "host-1", "ds-1"),
+		vm("vm-3", "host-1", "ds-1"),
+		vm("vm-4", "host-2", "ds-2"))
+	h.connect([]string{"vm-1", "vm-2", "vm-3"}, []string{"vm-1", "vm-2", "vm-3"}, RelationDisk, "[ds-1] shared.vmdk")
+	proposal = handler.propose(h, all(h), WaveLimits{VMs: 2})
+	g.Expect(proposal.Waves).To(gomega.HaveLen(2))
+	g.Expect(ids(proposal.Waves[0])).To(gomega.Equal([]string{"vm-1", "vm-2", "vm-3"}))
+	g.Expect(proposal.Waves[0].Warnings).To(
+		gomega.ConsistOf("Related VMs (3) exceed the wave limits."))
+	g.Expect(ids(proposal.Waves[1])).To(gomega.Equal([]string{"vm-4"}))
+	g.Expect(proposal.Waves[1].Warnings).To(gomega.BeEmpty())
+}
+
+func TestWavePlans(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	gin.SetMode(gin.TestMode)
+	context := func(query string) *gin.Context {
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest(http.MethodGet, WavesRoot+"?"+query, nil)
+		return ctx
+	}
+	handler := WaveHandler{
+		Handler: Handler{
+			base.Handler{
+				Provider: &api.Provider{
+					ObjectMeta: meta.ObjectMeta{
+						Namespace: "ns",
+						Name:      "vcenter",
+					},
+				},
+			},
+		},
+	}
+	vms := map[string]*model.VM{
+		"vm-1": {
+			Base:     model.Base{ID: "vm-1"},
+			Networks: []model.Ref{{ID: "net-1"}, {ID: "net-2"}},
+			Disks: []model.Disk{
+				{Datastore: model.Ref{ID: "ds-1"}},
+			},
+		},
+	}
+	proposal := func() *WaveProposal {
+		return &WaveProposal{
+			Waves: []*Wave{
+				{
+					Number: 1,
+					VMs:    []WaveVM{{ID: "vm-1"}},
+				},
+			},
+		}
+	}
+
+	// Mapped.
+	ctx := context("network=net-1:pod&network=net-2:ns/blue&storage=ds-1:gold&plan=p")
+	mp, err := handler.resourceMap(ctx)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	p := proposal()
+	handler.plans(ctx, p, vms, mp)
+	wave := p.Waves[0]
+	g.Expect(wave.Warnings).To(gomega.BeEmpty())
+	g.Expect(wave.Plan.Name).To(gomega.Equal("p-wave-1"))
+	g.Expect(wave.Plan.Spec.Map.Networks).To(gomega.HaveLen(2))
+	g.Expect(wave.Plan.Spec.Map.Networks[0].Destination.Type).To(gomega.Equal(PodNetwork))
+	g.Expect(wave.Plan.Spec.Map.Networks[1].Destination.Type).To(gomega.Equal(MultusNetwork))
+	g.Expect(wave.Plan.Spec.Map.Networks[1].Destination.Namespace).To(gomega.Equal("ns"))
+	g.Expect(wave.Plan.Spec.Map.Networks[1].Destination.Name).To(gomega.Equal("blue"))
+	g.Expect(wave.Plan.Spec.Map.Datastores).To(gomega.HaveLen(1))
+	g.Expect(wave.Plan.Spec.Map.Datastores[0].Destination.StorageClass).To(gomega.Equal("gold"))
+
+	// Not mapped.
+	ctx = context("network=net-1:pod")
+	mp, err = handler.resourceMap(ctx)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	p = proposal()
+	handler.plans(ctx, p, vms, mp)
+	wave = p.Waves[0]
+	g.Expect(wave.Plan.Name).To(gomega.Equal("vcenter-wave-1"))
+	g.Expect(wave.Plan.Spec.Map.Networks).To(gomega.HaveLen(1))
+	g.Expect(wave.Plan.Spec.Map.Datastores).To(gomega.BeEmpty())
+	g.Expect(wave.Warnings).To(
+		gomega.ConsistOf(
+			"Networks not mapped: net-2.",
+			"Datastores not mapped: ds-1."))
+
+	// Malformed.
+	for _, query := range []string{
+		"network=net-1",
+		"network=net-1:ns",
+		"network=:pod",
+		"storage=ds-1",
+		"storage=ds-1:",
+	} {
+		_, err = handler.resourceMap(context(query))
+		g.Expect(err).To(gomega.HaveOccurred(), query)
+	}
+}
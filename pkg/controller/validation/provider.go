@@ -205,3 +205,33 @@ func (r *ProviderPair) validateDestination(pair api.ProviderPair) (result libcnd
 
 	return
 }
+
+//
+// Build a client for the provider (cluster).
+// The host client is returned for the `host` provider.
+func ProviderClient(host client.Client, provider *api.Provider) (pClient client.Client, err error) {
+	if provider.IsHost() {
+		pClient = host
+		return
+	}
+	ref := provider.Spec.Secret
+	secret := &core.Secret{}
+	err = host.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: ref.Namespace,
+			Name:      ref.Name,
+		},
+		secret)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	pClient, err = provider.Client(secret)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
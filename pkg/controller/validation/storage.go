@@ -1,13 +1,16 @@
 package validation
 
 import (
+	"context"
 	"errors"
 	libcnd "github.com/konveyor/controller/pkg/condition"
 	liberr "github.com/konveyor/controller/pkg/error"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1alpha1/mapped"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ocp"
+	core "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -16,6 +19,16 @@ import (
 const (
 	SourceStorageNotValid      = "SourceStorageNotValid"
 	DestinationStorageNotValid = "DestinationStorageNotValid"
+	AccessModeNotSupported     = "AccessModeNotSupported"
+	AccessModeNotHonored       = "AccessModeNotHonored"
+	AccessModeNotShared        = "AccessModeNotShared"
+)
+
+//
+// Reasons
+const (
+	NotSupported = "NotSupported"
+	NotHonored   = "NotHonored"
 )
 
 //
@@ -26,6 +39,8 @@ type StoragePair struct {
 		Source      *api.Provider
 		Destination *api.Provider
 	}
+	// Shared disks (optional).
+	SharedDisks *mapped.SharedDisks
 }
 
 //
@@ -37,12 +52,31 @@ func (r *StoragePair) Validate(list []mapped.StoragePair) (result libcnd.Conditi
 		return
 	}
 	result.UpdateConditions(conditions)
+	// The shared disk storage class is validated
+	// as a (block/RWX) destination.
+	if r.SharedDisks != nil {
+		list = append(
+			list,
+			mapped.StoragePair{
+				Destination: mapped.DestinationStorage{
+					StorageClass: r.SharedDisks.StorageClass,
+					VolumeMode:   core.PersistentVolumeBlock,
+					AccessMode:   core.ReadWriteMany,
+				},
+			})
+	}
 	conditions, err = r.validateDestination(list)
 	if err != nil {
 		err = liberr.Wrap(err)
 		return
 	}
 	result.UpdateConditions(conditions)
+	conditions, err = r.validateAccessMode(list)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	result.UpdateConditions(conditions)
 
 	return
 }
@@ -162,3 +196,145 @@ func (r *StoragePair) validateDestination(list []mapped.StoragePair) (result lib
 
 	return
 }
+
+//
+// Validate destination access modes.
+// The access mode (when not specified) is defaulted using the
+// Provisioner CR matched by the storage class provisioner.
+// VMIO support for access modes is detected on the destination.
+// VMs can only be live migrated with ReadWriteMany storage.
+func (r *StoragePair) validateAccessMode(list []mapped.StoragePair) (result libcnd.Conditions, err error) {
+	provider := r.Provider.Destination
+	if provider == nil || provider.Type() != api.OpenShift {
+		return
+	}
+	inventory, err := web.NewClient(provider)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	provisioners, err := r.provisioners(provider.Namespace)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	capabilities, err := r.capabilities(provider)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	notSupported := []string{}
+	notHonored := []string{}
+	notShared := []string{}
+	for _, entry := range list {
+		dm := entry.Destination
+		sc := &ocp.StorageClass{}
+		pErr := inventory.Get(sc, dm.StorageClass)
+		if pErr != nil {
+			if errors.As(pErr, &web.NotFoundError{}) {
+				continue
+			} else {
+				err = liberr.Wrap(pErr)
+				return
+			}
+		}
+		accessMode := dm.AccessMode
+		if provisioner, found := provisioners[sc.Object.Provisioner]; found {
+			volumeMode := provisioner.VolumeMode(dm.VolumeMode)
+			defaulted := volumeMode.AccessMode(accessMode)
+			if accessMode == "" {
+				accessMode = defaulted.Name
+			} else {
+				if defaulted.Name != accessMode {
+					notSupported = append(notSupported, dm.StorageClass)
+				}
+			}
+		}
+		if accessMode == "" {
+			accessMode = core.ReadWriteOnce
+		}
+		if accessMode != core.ReadWriteOnce && !capabilities.AccessMode {
+			notHonored = append(notHonored, dm.StorageClass)
+			accessMode = core.ReadWriteOnce
+		}
+		if accessMode != core.ReadWriteMany {
+			notShared = append(notShared, dm.StorageClass)
+		}
+	}
+	if len(notSupported) > 0 {
+		result.SetCondition(libcnd.Condition{
+			Type:     AccessModeNotSupported,
+			Status:   True,
+			Reason:   NotSupported,
+			Category: Critical,
+			Message:  "Access mode not supported by the storage provisioner.",
+			Items:    notSupported,
+		})
+	}
+	if len(notHonored) > 0 {
+		result.SetCondition(libcnd.Condition{
+			Type:     AccessModeNotHonored,
+			Status:   True,
+			Reason:   NotHonored,
+			Category: Critical,
+			Message:  "Access mode cannot be honored by the installed VM Import Operator.",
+			Items:    notHonored,
+		})
+	}
+	if len(notShared) > 0 {
+		result.SetCondition(libcnd.Condition{
+			Type:     AccessModeNotShared,
+			Status:   True,
+			Reason:   NotSupported,
+			Category: Warn,
+			Message:  "Access mode not ReadWriteMany; VMs will not be live migratable.",
+			Items:    notShared,
+		})
+	}
+
+	return
+}
+
+//
+// Provisioner CRs keyed by name.
+func (r *StoragePair) provisioners(namespace string) (mp map[string]*api.Provisioner, err error) {
+	list := &api.ProvisionerList{}
+	err = r.List(
+		context.TODO(),
+		list,
+		&client.ListOptions{
+			Namespace: namespace,
+		},
+	)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	mp = map[string]*api.Provisioner{}
+	for i := range list.Items {
+		p := &list.Items[i]
+		mp[p.Spec.Name] = p
+	}
+
+	return
+}
+
+//
+// Detect destination cluster capabilities.
+func (r *StoragePair) capabilities(provider *api.Provider) (capabilities plancontext.Capabilities, err error) {
+	if capabilities.Cached(provider) {
+		return
+	}
+	destination, err := ProviderClient(r.Client, provider)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = capabilities.Detect(provider, destination)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
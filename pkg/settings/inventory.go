@@ -1,6 +1,7 @@
 package settings
 
 import (
+	liberr "github.com/konveyor/controller/pkg/error"
 	"os"
 	"strconv"
 	"strings"
@@ -15,18 +16,24 @@ const (
 //
 // Environment variables.
 const (
-	AllowedOrigins = "CORS_ALLOWED_ORIGINS"
-	WorkingDir     = "WORKING_DIR"
-	AuthOptional   = "AUTH_OPTIONAL"
-	Host           = "API_HOST"
-	Port           = "API_PORT"
-	TLSEnabled     = "API_TLS_ENABLED"
-	TLSCertificate = "API_TLS_CERTIFICATE"
-	TLSKey         = "API_TLS_KEY"
-	TLSCa          = "API_TLS_CA"
-	XavierURL      = "XAVIER_URL"
-	XavierUser     = "XAVIER_USER"
-	XavierPassword = "XAVIER_PASSWORD"
+	AllowedOrigins    = "CORS_ALLOWED_ORIGINS"
+	WorkingDir        = "WORKING_DIR"
+	AuthOptional      = "AUTH_OPTIONAL"
+	Host              = "API_HOST"
+	Port              = "API_PORT"
+	TLSEnabled        = "API_TLS_ENABLED"
+	TLSCertificate    = "API_TLS_CERTIFICATE"
+	TLSKey            = "API_TLS_KEY"
+	TLSCa             = "API_TLS_CA"
+	XavierURL         = "XAVIER_URL"
+	XavierUser        = "XAVIER_USER"
+	XavierPassword    = "XAVIER_PASSWORD"
+	AnalysisRules     = "ANALYSIS_RULES"
+	AnalysisConfigMap = "ANALYSIS_RULES_CONFIGMAP"
+	PerfEnabled       = "PERF_ENABLED"
+	PerfInterval      = "PERF_INTERVAL"
+	PerfWindows       = "PERF_WINDOWS"
+	WarmStart         = "INVENTORY_WARM_START"
 )
 
 //
@@ -42,7 +49,14 @@ type Inventory struct {
 	// CORS settings.
 	CORS CORS
 	// DB working directory.
+	// Defaults to the (ephemeral) temp directory.
 	WorkingDir string
+	// Warm start.
+	// The inventory DB is retained across restarts and the cached
+	// (stale) inventory served until the initial sync has completed.
+	// Enabled by default only when the working directory is specified
+	// and expected to be a (persistent) volume.
+	WarmStart bool
 	// Authorization disabled.
 	AuthOptional bool
 	// Host.
@@ -60,6 +74,28 @@ type Inventory struct {
 		// CA path
 		CA string
 	}
+	// Analysis rule set path.
+	// Typically, a key within a mounted ConfigMap.
+	AnalysisRules string
+	// Analysis rule set ConfigMap (namespace/name).
+	// The ConfigMap is watched and the rule set read from
+	// the `rules` key. Takes precedence over AnalysisRules.
+	// Requires `list` and `watch` on configmaps.
+	AnalysisConfigMap struct {
+		// Namespace.
+		Namespace string
+		// Name.
+		Name string
+	}
+	// Performance statistics.
+	Perf struct {
+		// Collection enabled.
+		Enabled bool
+		// Collection interval (minutes).
+		Interval int
+		// Aggregation windows (hours).
+		Windows []int
+	}
 }
 
 //
@@ -78,11 +114,14 @@ func (r *Inventory) Load() error {
 		}
 	}
 	// WorkingDir
-	if s, found := os.LookupEnv(WorkingDir); found {
-		r.WorkingDir = s
+	workingDir, found := os.LookupEnv(WorkingDir)
+	if found {
+		r.WorkingDir = workingDir
 	} else {
 		r.WorkingDir = os.TempDir()
 	}
+	// Warm start.
+	r.WarmStart = getEnvBool(WarmStart, found)
 	// Auth
 	r.AuthOptional = getEnvBool(AuthOptional, false)
 	// Host
@@ -112,6 +151,36 @@ func (r *Inventory) Load() error {
 			r.TLS.CA = ServiceCAFile
 		}
 	}
+	// Analysis
+	if s, found := os.LookupEnv(AnalysisRules); found {
+		r.AnalysisRules = s
+	}
+	if s, found := os.LookupEnv(AnalysisConfigMap); found {
+		parts := strings.Split(s, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return liberr.New(AnalysisConfigMap + " must be: <namespace>/<name>.")
+		}
+		r.AnalysisConfigMap.Namespace = parts[0]
+		r.AnalysisConfigMap.Name = parts[1]
+	}
+	// Performance
+	r.Perf.Enabled = getEnvBool(PerfEnabled, false)
+	n, err := getEnvLimit(PerfInterval, 5)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	r.Perf.Interval = n
+	r.Perf.Windows = []int{1, 24}
+	if s, found := os.LookupEnv(PerfWindows); found {
+		r.Perf.Windows = []int{}
+		for _, field := range strings.Fields(s) {
+			n, err := strconv.Atoi(field)
+			if err != nil || n < 1 {
+				return liberr.New(PerfWindows + " must be a list of integers >= 1")
+			}
+			r.Perf.Windows = append(r.Perf.Windows, n)
+		}
+	}
 
 	return nil
 }